@@ -0,0 +1,308 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fabian-z/gopherlab/ipc"
+	"github.com/fabian-z/gopherlab/protocol"
+	repl "github.com/fabian-z/gopherlab/replpkg"
+	uuid "github.com/nu7hatch/gouuid"
+	"sync"
+)
+
+// CommTarget is called when the frontend opens a comm to a target registered with
+// RegisterCommTarget, with the data of the comm_open message.
+type CommTarget func(comm *Comm, data map[string]interface{})
+
+// Comm is a comm between the kernel and the frontend. Comms are either opened by
+// the frontend on a target registered by the kernel, or by user code.
+type Comm struct {
+	ID     string
+	Target string
+
+	// OnMsg and OnClose are called for the comm_msg and comm_close messages sent
+	// by the frontend, if set.
+	OnMsg   func(data map[string]interface{})
+	OnClose func(data map[string]interface{})
+
+	// receipt is the message in response to which messages on the comm are sent:
+	// the one which opened the comm or the last one received on it.
+	receipt MsgReceipt
+
+	// user is set for comms opened by user code, whose messages from the frontend
+	// are kept in pending until the program asks for them.
+	user    bool
+	pending []json.RawMessage
+	closed  bool
+}
+
+var (
+	commsMu     sync.Mutex // guards the variables below
+	comms       = make(map[string]*Comm)
+	commTargets = make(map[string]CommTarget)
+	// userComms holds the comms opened by the statements of cells, which are
+	// reused when the statements are run again.
+	userComms = make(map[userCommKey]*Comm)
+)
+
+// userCommKey identifies a comm opened by user code: it is the n-th comm opened
+// for target by the statement at pos during a run.
+type userCommKey struct {
+	target string
+	pos    repl.CellPos
+	n      int
+}
+
+// RegisterCommTarget makes target handle the comms opened by the frontend for the
+// target name.
+func RegisterCommTarget(name string, target CommTarget) {
+	commsMu.Lock()
+	defer commsMu.Unlock()
+
+	commTargets[name] = target
+}
+
+// OpenComm opens a comm to the frontend's comm target in response to receipt.
+func OpenComm(receipt MsgReceipt, target string, data interface{}) *Comm {
+	return openComm(receipt, target, data, false)
+}
+
+func openComm(receipt MsgReceipt, target string, data interface{}, user bool) *Comm {
+	u, _ := uuid.NewV4()
+	comm := &Comm{ID: u.String(), Target: target, receipt: receipt, user: user}
+
+	commsMu.Lock()
+	comms[comm.ID] = comm
+	commsMu.Unlock()
+
+	comm.publish("comm_open", protocol.CommOpen{CommID: comm.ID, TargetName: target, Data: commData(data)})
+	return comm
+}
+
+// Send sends data to the frontend in a comm_msg message.
+func (comm *Comm) Send(data interface{}) {
+	comm.publish("comm_msg", protocol.CommMsg{CommID: comm.ID, Data: commData(data)})
+}
+
+// Close closes the comm, sending data in a comm_close message.
+func (comm *Comm) Close(data interface{}) {
+	commsMu.Lock()
+	comm.closed = true
+	delete(comms, comm.ID)
+	commsMu.Unlock()
+
+	comm.publish("comm_close", protocol.CommMsg{CommID: comm.ID, Data: commData(data)})
+}
+
+func (comm *Comm) publish(msgType string, content interface{}) {
+	msg := NewMsg(msgType, comm.receipt.Msg)
+	msg.Content = content
+	comm.receipt.Publish(msg)
+}
+
+// commData returns the data of a comm message to be sent; Jupyter expects an
+// object even if there is no data.
+func commData(data interface{}) interface{} {
+	if raw, ok := data.(json.RawMessage); data == nil || ok && len(raw) == 0 {
+		return make(map[string]interface{})
+	}
+	return data
+}
+
+// HandleCommOpen opens a comm in response to a comm_open message, using the registered
+// target. Comms to unknown targets are closed again right away.
+func HandleCommOpen(receipt MsgReceipt) {
+	var req protocol.CommOpen
+	if err := receipt.Msg.DecodeContent(&req); err != nil {
+		logger.Println("comm_open:", err)
+		return
+	}
+
+	commsMu.Lock()
+	handler, ok := commTargets[req.TargetName]
+	commsMu.Unlock()
+
+	comm := &Comm{ID: req.CommID, Target: req.TargetName, receipt: receipt}
+	if !ok {
+		logger.Printf("comm_open: no comm target %q", req.TargetName)
+		comm.publish("comm_close", protocol.CommMsg{CommID: req.CommID, Data: commData(nil)})
+		return
+	}
+
+	commsMu.Lock()
+	comms[req.CommID] = comm
+	commsMu.Unlock()
+
+	data, _ := req.Data.(map[string]interface{})
+	handler(comm, data)
+}
+
+// HandleCommMsg passes the data of a comm_msg message on to its comm.
+func HandleCommMsg(receipt MsgReceipt) {
+	var req protocol.CommMsg
+	if err := receipt.Msg.DecodeContent(&req); err != nil {
+		logger.Println("comm_msg:", err)
+		return
+	}
+	data, _ := req.Data.(map[string]interface{})
+
+	commsMu.Lock()
+	comm, ok := comms[req.CommID]
+	if ok {
+		comm.receipt = receipt
+		if comm.user {
+			raw, _ := json.Marshal(req.Data)
+			comm.pending = append(comm.pending, raw)
+		}
+	}
+	commsMu.Unlock()
+
+	if !ok {
+		logger.Printf("comm_msg: no comm %q", req.CommID)
+		return
+	}
+	if comm.OnMsg != nil {
+		comm.OnMsg(data)
+	}
+}
+
+// HandleCommClose removes the comm closed by a comm_close message.
+func HandleCommClose(receipt MsgReceipt) {
+	var req protocol.CommMsg
+	if err := receipt.Msg.DecodeContent(&req); err != nil {
+		logger.Println("comm_close:", err)
+		return
+	}
+	data, _ := req.Data.(map[string]interface{})
+
+	commsMu.Lock()
+	comm, ok := comms[req.CommID]
+	if ok {
+		comm.receipt = receipt
+		comm.closed = true
+		delete(comms, req.CommID)
+	}
+	commsMu.Unlock()
+
+	if !ok {
+		logger.Printf("comm_close: no comm %q", req.CommID)
+		return
+	}
+	if comm.OnClose != nil {
+		comm.OnClose(data)
+	}
+}
+
+// HandleCommInfoRequest sends a comm_info_reply listing the open comms, restricted
+// to the target name of the request if it has one.
+func HandleCommInfoRequest(receipt MsgReceipt) {
+	var req protocol.CommInfoRequest
+	if err := receipt.Msg.DecodeContent(&req); err != nil {
+		receipt.ReplyError("comm_info_reply", err)
+		return
+	}
+
+	reply := NewMsg("comm_info_reply", receipt.Msg)
+
+	info := protocol.CommInfoReply{Status: "ok", Comms: make(map[string]protocol.CommInfo)}
+	commsMu.Lock()
+	for id, comm := range comms {
+		if req.TargetName == "" || comm.Target == req.TargetName {
+			info.Comms[id] = protocol.CommInfo{TargetName: comm.Target}
+		}
+	}
+	commsMu.Unlock()
+
+	reply.Content = info
+	receipt.Reply(reply)
+}
+
+// CommHandler returns the handler for the comm requests of the program run in
+// response to receipt, see package github.com/fabian-z/gopherlab/comm. The comms
+// opened by statements of earlier cells which are run again are reused, as located
+// by caller from the trace of the goroutine opening them; caller may be nil.
+func CommHandler(receipt MsgReceipt, caller func(stack string) (repl.CellPos, bool)) ipc.Handler {
+	// opened counts the comms opened by the program per target and statement
+	opened := make(map[userCommKey]int)
+
+	return func(conn *ipc.Conn, msg ipc.Message) {
+		var req ipc.CommMessage
+		if err := msg.Decode(&req); err != nil {
+			ipc.Reply(conn, nil, err)
+			return
+		}
+
+		switch msg.Type {
+		case "comm_open":
+			var comm *Comm
+			if pos, ok := callerPos(caller, req.Stack); ok {
+				key := userCommKey{target: req.Target, pos: pos}
+				key.n = opened[key]
+				opened[key]++
+				comm = openUserComm(receipt, key, req.Data)
+			} else {
+				comm = openComm(receipt, req.Target, req.Data, true)
+			}
+			ipc.Reply(conn, ipc.CommMessage{ID: comm.ID}, nil)
+		case "comm_msg", "comm_recv", "comm_close":
+			commsMu.Lock()
+			comm, ok := comms[req.ID]
+			var pending []json.RawMessage
+			if ok && msg.Type == "comm_recv" {
+				pending, comm.pending = comm.pending, nil
+			}
+			commsMu.Unlock()
+
+			if !ok || !comm.user {
+				ipc.Reply(conn, nil, fmt.Errorf("comm %s is not open", req.ID))
+				return
+			}
+
+			switch msg.Type {
+			case "comm_msg":
+				comm.Send(req.Data)
+			case "comm_close":
+				comm.Close(req.Data)
+			}
+			ipc.Reply(conn, ipc.CommMessages{Messages: pending}, nil)
+		default:
+			logger.Println("Unhandled ipc message:", msg.Type)
+		}
+	}
+}
+
+// callerPos returns the statement of a cell opening a comm, located by caller in
+// stack, if both are known.
+func callerPos(caller func(stack string) (repl.CellPos, bool), stack string) (repl.CellPos, bool) {
+	if caller == nil || stack == "" {
+		return repl.CellPos{}, false
+	}
+	return caller(stack)
+}
+
+// openUserComm returns the comm opened by user code for key, opening it if it does
+// not exist yet or has been closed.
+func openUserComm(receipt MsgReceipt, key userCommKey, data json.RawMessage) *Comm {
+	commsMu.Lock()
+	if comm, ok := userComms[key]; ok && !comm.closed {
+		commsMu.Unlock()
+		return comm
+	}
+	commsMu.Unlock()
+
+	comm := openComm(receipt, key.target, data, true)
+
+	commsMu.Lock()
+	defer commsMu.Unlock()
+	userComms[key] = comm
+	return comm
+}
+
+// resetComms forgets all comms, as the frontend does when the kernel restarts.
+func resetComms() {
+	commsMu.Lock()
+	defer commsMu.Unlock()
+
+	comms = make(map[string]*Comm)
+	userComms = make(map[userCommKey]*Comm)
+}
@@ -0,0 +1,91 @@
+// Package comm lets programs run in gopherlab open Jupyter comms to the frontend,
+// e.g. to drive widgets or JupyterLab extensions.
+//
+// Comms are kept by the kernel, so they outlive the program of the cell which
+// opened them. Depending on the evaluator of the session, the statements of
+// previous cells may be run again: by every cell with the "run" evaluator, or when
+// the worker process of the "worker" evaluator is restarted. When a statement of
+// an earlier cell runs again, Open therefore returns the comm it opened before,
+// for the same target and in the same order, instead of a new one, as long as it
+// has not been closed. Running a cell anew opens new comms.
+package comm
+
+import (
+	"encoding/json"
+	"runtime/debug"
+
+	"github.com/fabian-z/gopherlab/ipc"
+)
+
+// Comm is a comm opened by the program.
+type Comm struct {
+	ID     string
+	Target string
+}
+
+// Open opens a comm to the frontend's comm target, sending data with the
+// comm_open message.
+func Open(target string, data interface{}) (*Comm, error) {
+	raw, err := marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply ipc.CommMessage
+	err = ipc.Call("comm_open", ipc.CommMessage{Target: target, Data: raw, Stack: string(debug.Stack())}, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Comm{ID: reply.ID, Target: target}, nil
+}
+
+// Send sends data to the frontend in a comm_msg message.
+func (c *Comm) Send(data interface{}) error {
+	raw, err := marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return ipc.Call("comm_msg", ipc.CommMessage{ID: c.ID, Data: raw}, nil)
+}
+
+// Recv returns the data of the messages the frontend has sent on the comm since
+// the last call, including those received while no program was running.
+func (c *Comm) Recv() ([]map[string]interface{}, error) {
+	var reply ipc.CommMessages
+	err := ipc.Call("comm_recv", ipc.CommMessage{ID: c.ID}, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]map[string]interface{}, 0, len(reply.Messages))
+	for _, raw := range reply.Messages {
+		var data map[string]interface{}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, err
+		}
+		messages = append(messages, data)
+	}
+
+	return messages, nil
+}
+
+// Close closes the comm, sending data with the comm_close message.
+func (c *Comm) Close(data interface{}) error {
+	raw, err := marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return ipc.Call("comm_close", ipc.CommMessage{ID: c.ID, Data: raw}, nil)
+}
+
+// marshal encodes the data of a comm message; Jupyter expects an object even
+// if there is no data.
+func marshal(data interface{}) (json.RawMessage, error) {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return json.Marshal(data)
+}
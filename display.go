@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/fabian-z/gopherlab/ipc"
+	"github.com/fabian-z/gopherlab/protocol"
+)
+
+// HandleDisplayMsg publishes the rich output a program sent on the ipc channel
+// in response to receipt, see package github.com/fabian-z/gopherlab/display.
+// Unless silent, the message is published as display_data, update_display_data
+// or clear_output.
+func HandleDisplayMsg(receipt MsgReceipt, silent bool, conn *ipc.Conn, msg ipc.Message) {
+	var req ipc.DisplayMessage
+	if err := msg.Decode(&req); err != nil {
+		ipc.Reply(conn, nil, err)
+		return
+	}
+
+	if req.Data == nil {
+		req.Data = make(map[string]interface{})
+	}
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]interface{})
+	}
+
+	out := NewMsg(msg.Type, receipt.Msg)
+	switch msg.Type {
+	case "clear_output":
+		out.Content = protocol.ClearOutput{Wait: req.Wait}
+	default:
+		content := protocol.DisplayData{Data: req.Data, Metadata: req.Metadata}
+		if req.DisplayID != "" {
+			content.Transient = map[string]interface{}{"display_id": req.DisplayID}
+		}
+		out.Content = content
+	}
+
+	if !silent {
+		receipt.Publish(out)
+	}
+	ipc.Reply(conn, nil, nil)
+}
@@ -0,0 +1,114 @@
+// Package display lets programs run in gopherlab show rich output in the notebook,
+// such as HTML, images or formulas, next to the text they print.
+//
+//	display.HTML("<b>bold</b>")
+//	display.Markdown("# Title")
+//
+// Data is sent to the kernel on a side channel, so it does not mix with the
+// standard output of the program. Outside of gopherlab, the functions of this
+// package fail.
+//
+// Types can provide rich representations of themselves by implementing HTMLer,
+// PNGer, MIMEBundler or the like, which are used when they are the value of a
+// cell. Other slices, maps and structs are shown as tables and trees.
+package display
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/fabian-z/gopherlab/ipc"
+)
+
+// MIME types of the representations understood by Jupyter frontends.
+const (
+	MIMEPlain    = "text/plain"
+	MIMEHTML     = "text/html"
+	MIMEMarkdown = "text/markdown"
+	MIMELatex    = "text/latex"
+	MIMESVG      = "image/svg+xml"
+	MIMEPNG      = "image/png"
+	MIMEJPEG     = "image/jpeg"
+	MIMEJSON     = "application/json"
+)
+
+// Bundle maps MIME types to representations of the same data. The frontend
+// shows the richest one it supports. Binary data, such as images, has to be
+// base64 encoded.
+type Bundle map[string]interface{}
+
+// Data shows the representations of bundle, with the metadata given for them.
+func Data(bundle Bundle, metadata map[string]interface{}) error {
+	return ipc.Call("display_data", ipc.DisplayMessage{Data: bundle, Metadata: metadata}, nil)
+}
+
+// HTML shows an HTML fragment.
+func HTML(html string) error {
+	return Data(Bundle{MIMEHTML: html}, nil)
+}
+
+// Markdown shows markdown text.
+func Markdown(markdown string) error {
+	return Data(Bundle{MIMEMarkdown: markdown}, nil)
+}
+
+// Latex shows a LaTeX formula, e.g. "$e^{i\pi} + 1 = 0$".
+func Latex(latex string) error {
+	return Data(Bundle{MIMELatex: latex}, nil)
+}
+
+// SVG shows an SVG image.
+func SVG(svg string) error {
+	return Data(Bundle{MIMESVG: svg}, nil)
+}
+
+// PNG shows a PNG encoded image.
+func PNG(png []byte) error {
+	return Data(Bundle{MIMEPNG: base64.StdEncoding.EncodeToString(png)}, nil)
+}
+
+// JPEG shows a JPEG encoded image.
+func JPEG(jpeg []byte) error {
+	return Data(Bundle{MIMEJPEG: base64.StdEncoding.EncodeToString(jpeg)}, nil)
+}
+
+// JSON shows v, which must be encodable as JSON, as an interactive tree.
+func JSON(v interface{}) error {
+	return Data(Bundle{MIMEJSON: v}, nil)
+}
+
+// Clear clears the output of the cell. If wait is set, the output is cleared only
+// when new output arrives, which avoids flickering.
+func Clear(wait bool) error {
+	return ipc.Call("clear_output", ipc.DisplayMessage{Wait: wait}, nil)
+}
+
+// Display is a display whose data can be replaced after it has been shown, e.g.
+// to show progress.
+type Display struct {
+	ID string
+}
+
+// New returns a Display with a new id, which is not shown yet.
+func New() *Display {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return &Display{ID: hex.EncodeToString(id)}
+}
+
+// Show shows the representations of bundle in the display. Showing a display more
+// than once shows it in several places, which are all updated together.
+func (d *Display) Show(bundle Bundle) error {
+	return ipc.Call("display_data", ipc.DisplayMessage{Data: bundle, DisplayID: d.ID}, nil)
+}
+
+// Update replaces the data shown in the display by the representations of bundle.
+func (d *Display) Update(bundle Bundle) error {
+	return ipc.Call("update_display_data", ipc.DisplayMessage{Data: bundle, DisplayID: d.ID}, nil)
+}
+
+// Clear empties the display.
+func (d *Display) Clear() error {
+	return d.Update(Bundle{MIMEPlain: ""})
+}
@@ -0,0 +1,85 @@
+package display
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+)
+
+// ImageOptions control the size at which images are shown. The image is always
+// sent at its full resolution; the frontend scales it.
+type ImageOptions struct {
+	// Width and Height set the size at which the image is shown, in pixels. If
+	// only one of them is set, the aspect ratio is kept.
+	Width, Height int
+
+	// MaxWidth and MaxHeight scale down images which are larger, keeping the
+	// aspect ratio. Zero means no limit.
+	MaxWidth, MaxHeight int
+}
+
+// DefaultImageOptions are used for the images shown as the value of a cell.
+var DefaultImageOptions = ImageOptions{MaxWidth: 800}
+
+// Image shows img as a PNG image, sized according to opts. If opts is nil,
+// DefaultImageOptions are used.
+func Image(img image.Image, opts *ImageOptions) error {
+	bundle, metadata, err := imageBundle(img, opts)
+	if err != nil {
+		return err
+	}
+	return Data(bundle, metadata)
+}
+
+// imageBundle encodes img as PNG, with a text fallback describing it and the
+// display size in the metadata.
+func imageBundle(img image.Image, opts *ImageOptions) (Bundle, map[string]interface{}, error) {
+	if opts == nil {
+		opts = &DefaultImageOptions
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, nil, err
+	}
+
+	bounds := img.Bounds()
+	bundle := Bundle{
+		MIMEPNG:   base64.StdEncoding.EncodeToString(buf.Bytes()),
+		MIMEPlain: fmt.Sprintf("%T %dx%d", img, bounds.Dx(), bounds.Dy()),
+	}
+
+	metadata := make(map[string]interface{})
+	if w, h := opts.size(bounds.Dx(), bounds.Dy()); w != bounds.Dx() || h != bounds.Dy() {
+		metadata[MIMEPNG] = map[string]interface{}{"width": w, "height": h}
+	}
+
+	return bundle, metadata, nil
+}
+
+// size returns the size at which an image of width w and height h is shown.
+func (opts *ImageOptions) size(w, h int) (int, int) {
+	if w == 0 || h == 0 {
+		return w, h
+	}
+
+	switch {
+	case opts.Width > 0 && opts.Height > 0:
+		w, h = opts.Width, opts.Height
+	case opts.Width > 0:
+		w, h = opts.Width, h*opts.Width/w
+	case opts.Height > 0:
+		w, h = w*opts.Height/h, opts.Height
+	}
+
+	if opts.MaxWidth > 0 && w > opts.MaxWidth {
+		w, h = opts.MaxWidth, h*opts.MaxWidth/w
+	}
+	if opts.MaxHeight > 0 && h > opts.MaxHeight {
+		w, h = w*opts.MaxHeight/h, opts.MaxHeight
+	}
+
+	return w, h
+}
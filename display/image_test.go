@@ -0,0 +1,28 @@
+package display
+
+import (
+	"testing"
+)
+
+func TestImageOptions_size(t *testing.T) {
+	tests := []struct {
+		opts ImageOptions
+		w, h int
+	}{
+		{ImageOptions{}, 1600, 1200},
+		{ImageOptions{MaxWidth: 800}, 800, 600},
+		{ImageOptions{MaxWidth: 2000}, 1600, 1200},
+		{ImageOptions{MaxHeight: 300}, 400, 300},
+		{ImageOptions{Width: 400}, 400, 300},
+		{ImageOptions{Height: 600}, 800, 600},
+		{ImageOptions{Width: 100, Height: 100}, 100, 100},
+		{ImageOptions{Width: 1000, MaxWidth: 500}, 500, 375},
+	}
+
+	for _, test := range tests {
+		w, h := test.opts.size(1600, 1200)
+		if w != test.w || h != test.h {
+			t.Errorf("%+v: got %dx%d, want %dx%d", test.opts, w, h, test.w, test.h)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"reflect"
+	"sort"
+)
+
+// MaxDepth limits the nesting of the values shown as trees, and MaxLength the
+// number of elements of slices and maps shown as tables. Zero means no limit.
+// The kernel sets them from its :printer command.
+var (
+	MaxDepth  = 5
+	MaxLength = 1000
+)
+
+// tablePageSize is the number of rows of a table shown at first; the further rows
+// are grouped in collapsed pages of the same size.
+const tablePageSize = 20
+
+// builtinBundle returns the representations the package provides for composite
+// values: slices of structs and maps are shown as tables, structs as collapsible
+// trees, and values encodable as JSON objects or arrays as JSON as well, unless
+// they are larger than MaxDepth and MaxLength allow.
+func builtinBundle(x interface{}) Bundle {
+	switch x.(type) {
+	case fmt.Stringer, error:
+		// their text is what users want to see
+		return nil
+	}
+
+	v := reflect.ValueOf(x)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	bundle := make(Bundle)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if table, ok := tableHTML(v); ok {
+			bundle[MIMEHTML] = table
+		}
+	case reflect.Struct:
+		if len(exportedFields(v.Type())) > 0 {
+			bundle[MIMEHTML] = treeHTML(reflect.ValueOf(x), 0, make(map[uintptr]bool))
+		}
+	default:
+		return nil
+	}
+
+	if withinLimits(reflect.ValueOf(x), 0, make(map[uintptr]bool)) {
+		if raw, err := json.Marshal(x); err == nil && len(raw) > 0 && (raw[0] == '{' || raw[0] == '[') {
+			bundle[MIMEJSON] = json.RawMessage(raw)
+		}
+	}
+
+	if len(bundle) == 0 {
+		return nil
+	}
+	return bundle
+}
+
+// withinLimits reports whether v, at depth in the value shown, is shown whole by
+// the tables and trees: it nests no deeper than MaxDepth, holds no more than
+// MaxLength elements in any of its slices, arrays and maps, and has no cycles.
+// visited holds the pointers to the values v is part of.
+func withinLimits(v reflect.Value, depth int, visited map[uintptr]bool) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return true
+		}
+		if v.Kind() == reflect.Ptr {
+			if visited[v.Pointer()] {
+				return false
+			}
+			visited[v.Pointer()] = true
+			defer delete(visited, v.Pointer())
+		}
+		return withinLimits(v.Elem(), depth, visited)
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+	default:
+		return true
+	}
+
+	if MaxDepth > 0 && depth >= MaxDepth {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !withinLimits(v.Field(i), depth+1, visited) {
+				return false
+			}
+		}
+	case reflect.Map:
+		if MaxLength > 0 && v.Len() > MaxLength {
+			return false
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if !withinLimits(iter.Value(), depth+1, visited) {
+				return false
+			}
+		}
+	default:
+		if MaxLength > 0 && v.Len() > MaxLength {
+			return false
+		}
+		for i := 0; i < v.Len(); i++ {
+			if !withinLimits(v.Index(i), depth+1, visited) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// tableHTML renders a slice or array of structs, or a map, as an HTML table with
+// a row per element.
+func tableHTML(v reflect.Value) (string, bool) {
+	elemType := v.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	var fields []int
+	if elemType.Kind() == reflect.Struct {
+		fields = exportedFields(elemType)
+	}
+	if v.Kind() != reflect.Map && len(fields) == 0 {
+		return "", false
+	}
+
+	var keys []reflect.Value
+	if v.Kind() == reflect.Map {
+		keys = sortedKeys(v)
+	}
+
+	var header bytes.Buffer
+	header.WriteString("<thead><tr><th></th>")
+	if len(fields) > 0 {
+		for _, i := range fields {
+			fmt.Fprintf(&header, "<th>%s</th>", html.EscapeString(elemType.Field(i).Name))
+		}
+	} else {
+		header.WriteString("<th>value</th>")
+	}
+	header.WriteString("</tr></thead>")
+
+	n := v.Len()
+	shown := n
+	if MaxLength > 0 && shown > MaxLength {
+		shown = MaxLength
+	}
+
+	var buf bytes.Buffer
+	for start := 0; start < shown || start == 0; start += tablePageSize {
+		end := start + tablePageSize
+		if end > shown {
+			end = shown
+		}
+
+		if start > 0 {
+			fmt.Fprintf(&buf, "<details><summary>rows %d to %d</summary>", start, end-1)
+		}
+		buf.WriteString("<table>")
+		buf.Write(header.Bytes())
+		buf.WriteString("<tbody>")
+		for i := start; i < end; i++ {
+			var label string
+			var elem reflect.Value
+			if keys != nil {
+				label, elem = cellText(keys[i]), v.MapIndex(keys[i])
+			} else {
+				label, elem = fmt.Sprint(i), v.Index(i)
+			}
+
+			fmt.Fprintf(&buf, "<tr><th>%s</th>", html.EscapeString(label))
+			if len(fields) > 0 {
+				for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+					if elem.IsNil() {
+						break
+					}
+					elem = elem.Elem()
+				}
+				for _, f := range fields {
+					var text string
+					if elem.Kind() == reflect.Struct {
+						text = cellText(elem.Field(f))
+					}
+					fmt.Fprintf(&buf, "<td>%s</td>", html.EscapeString(text))
+				}
+			} else {
+				fmt.Fprintf(&buf, "<td>%s</td>", html.EscapeString(cellText(elem)))
+			}
+			buf.WriteString("</tr>")
+		}
+		buf.WriteString("</tbody></table>")
+		if start > 0 {
+			buf.WriteString("</details>")
+		}
+	}
+
+	if shown < n {
+		fmt.Fprintf(&buf, "<p>%d more rows not shown</p>", n-shown)
+	}
+
+	return buf.String(), true
+}
+
+// treeHTML renders v as a tree of nested lists, whose structs, slices and maps
+// can be collapsed. visited holds the pointers being rendered, to stop at cycles.
+func treeHTML(v reflect.Value, depth int, visited map[uintptr]bool) string {
+	var prefix string
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "nil"
+		}
+		if v.Kind() == reflect.Ptr {
+			if visited[v.Pointer()] {
+				return "&lt;cycle&gt;"
+			}
+			visited[v.Pointer()] = true
+			defer delete(visited, v.Pointer())
+			prefix += "&amp;"
+		}
+		v = v.Elem()
+	}
+
+	var n int
+	var keys []reflect.Value
+	fields := exportedFields(v.Type())
+	switch v.Kind() {
+	case reflect.Struct:
+		n = len(fields)
+	case reflect.Slice, reflect.Array:
+		n = v.Len()
+	case reflect.Map:
+		keys = sortedKeys(v)
+		n = len(keys)
+	default:
+		return html.EscapeString(cellText(v))
+	}
+
+	summary := prefix + html.EscapeString(v.Type().String())
+	if v.Kind() != reflect.Struct {
+		summary += fmt.Sprintf(" (%d)", n)
+	}
+	if MaxDepth > 0 && depth >= MaxDepth {
+		return summary + " …"
+	}
+
+	var buf bytes.Buffer
+	if depth == 0 {
+		buf.WriteString("<details open>")
+	} else {
+		buf.WriteString("<details>")
+	}
+	fmt.Fprintf(&buf, "<summary>%s</summary><ul>", summary)
+	for i := 0; i < n; i++ {
+		if v.Kind() != reflect.Struct && MaxLength > 0 && i >= MaxLength {
+			fmt.Fprintf(&buf, "<li>%d more not shown</li>", n-i)
+			break
+		}
+
+		var label string
+		var child reflect.Value
+		switch v.Kind() {
+		case reflect.Struct:
+			label, child = v.Type().Field(fields[i]).Name, v.Field(fields[i])
+		case reflect.Map:
+			label, child = cellText(keys[i]), v.MapIndex(keys[i])
+		default:
+			label, child = fmt.Sprint(i), v.Index(i)
+		}
+		fmt.Fprintf(&buf, "<li>%s: %s</li>", html.EscapeString(label), treeHTML(child, depth+1, visited))
+	}
+	buf.WriteString("</ul></details>")
+
+	return buf.String()
+}
+
+// exportedFields returns the indices of the exported fields of the struct type t.
+func exportedFields(t reflect.Type) []int {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
+
+// sortedKeys returns the keys of the map v in the order of their text.
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return cellText(keys[i]) < cellText(keys[j])
+	})
+	return keys
+}
+
+// cellText returns the text of a value shown in a table cell or tree leaf.
+func cellText(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if !v.CanInterface() {
+		return "?"
+	}
+	return fmt.Sprint(v.Interface())
+}
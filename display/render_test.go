@@ -0,0 +1,96 @@
+package display
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type renderPoint struct {
+	X, Y   int
+	hidden bool
+}
+
+type renderNode struct {
+	Name  string
+	Child *renderNode
+}
+
+func TestBuiltinBundle_table(t *testing.T) {
+	bundle := builtinBundle([]renderPoint{{1, 2, false}, {3, 4, true}})
+
+	table, _ := bundle[MIMEHTML].(string)
+	for _, want := range []string{"<th>X</th>", "<th>Y</th>", "<td>3</td>", "<td>4</td>"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("table should contain %s: %s", want, table)
+		}
+	}
+	if strings.Contains(table, "hidden") {
+		t.Errorf("table should not show unexported fields: %s", table)
+	}
+
+	raw, _ := json.Marshal(bundle[MIMEJSON])
+	if string(raw) != `[{"X":1,"Y":2},{"X":3,"Y":4}]` {
+		t.Errorf("got JSON %s", raw)
+	}
+}
+
+func TestBuiltinBundle_tablePages(t *testing.T) {
+	defer func(n int) { MaxLength = n }(MaxLength)
+	MaxLength = 50
+
+	points := make([]renderPoint, 100)
+	table, _ := builtinBundle(points)[MIMEHTML].(string)
+
+	if n := strings.Count(table, "<details>"); n != 2 {
+		t.Errorf("rows beyond the first page should be in 2 collapsed pages, got %d: %s", n, table)
+	}
+	if !strings.Contains(table, "50 more rows not shown") {
+		t.Errorf("rows beyond MaxLength should not be shown: %s", table)
+	}
+}
+
+func TestBuiltinBundle_jsonLimits(t *testing.T) {
+	defer func(n int) { MaxLength = n }(MaxLength)
+	MaxLength = 50
+
+	if _, ok := builtinBundle(make([]int, 50))[MIMEJSON]; !ok {
+		t.Errorf("values within MaxLength should be shown as JSON")
+	}
+	if _, ok := builtinBundle(make([]int, 100))[MIMEJSON]; ok {
+		t.Errorf("values beyond MaxLength should not be shown as JSON")
+	}
+	if _, ok := builtinBundle(map[string][]int{"a": make([]int, 100)})[MIMEJSON]; ok {
+		t.Errorf("values holding elements beyond MaxLength should not be shown as JSON")
+	}
+}
+
+func TestBuiltinBundle_map(t *testing.T) {
+	table, _ := builtinBundle(map[string]int{"b": 2, "a": 1})[MIMEHTML].(string)
+
+	if !strings.Contains(table, "<tr><th>a</th><td>1</td></tr><tr><th>b</th><td>2</td></tr>") {
+		t.Errorf("maps should be shown as tables sorted by key: %s", table)
+	}
+}
+
+func TestBuiltinBundle_tree(t *testing.T) {
+	defer func(n int) { MaxDepth = n }(MaxDepth)
+	MaxDepth = 2
+
+	node := &renderNode{Name: "a", Child: &renderNode{Name: "b", Child: &renderNode{Name: "c"}}}
+	node.Child.Child.Child = node
+
+	tree, _ := builtinBundle(node)[MIMEHTML].(string)
+	if !strings.HasPrefix(tree, "<details open><summary>&amp;display.renderNode</summary>") {
+		t.Errorf("structs should be shown as trees: %s", tree)
+	}
+	if !strings.Contains(tree, "Name: b") || strings.Contains(tree, "Name: c") {
+		t.Errorf("trees should be cut at MaxDepth: %s", tree)
+	}
+
+	MaxDepth = 0
+	tree, _ = builtinBundle(node)[MIMEHTML].(string)
+	if !strings.Contains(tree, "Child: &lt;cycle&gt;") {
+		t.Errorf("trees should stop at cycles: %s", tree)
+	}
+}
@@ -0,0 +1,148 @@
+package display
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"strings"
+
+	"github.com/fabian-z/gopherlab/ipc"
+)
+
+// Types implementing one or more of the following interfaces are shown with the
+// representations they provide when they are the value of a cell, instead of as
+// text. All provided representations are sent; the frontend picks the richest.
+
+// HTMLer is implemented by types with an HTML representation.
+type HTMLer interface {
+	HTML() string
+}
+
+// Markdowner is implemented by types with a markdown representation.
+type Markdowner interface {
+	Markdown() string
+}
+
+// Latexer is implemented by types with a LaTeX representation.
+type Latexer interface {
+	Latex() string
+}
+
+// SVGer is implemented by types with an SVG representation.
+type SVGer interface {
+	SVG() string
+}
+
+// PNGer is implemented by types with a PNG encoded representation.
+type PNGer interface {
+	PNG() []byte
+}
+
+// JPEGer is implemented by types with a JPEG encoded representation.
+type JPEGer interface {
+	JPEG() []byte
+}
+
+// MIMEBundler is implemented by types providing representations for arbitrary MIME
+// types, keyed by MIME type. Textual and JSON representations are sent as they
+// are, others are base64 encoded.
+type MIMEBundler interface {
+	MIMEBundle() map[string][]byte
+}
+
+// Value sends the rich representations of x to the kernel, which shows them
+// along with the printed text as the value of the cell. It reports whether they
+// include a text representation, in which case x need not be printed. Images are
+// shown as PNG, sized according to DefaultImageOptions; types implementing one of
+// the interfaces above with the representations they provide; slices, maps and
+// structs as tables, trees and JSON.
+func Value(x interface{}) (hasText bool) {
+	if !ipc.Available() {
+		return false
+	}
+
+	// values whose representation cannot be computed, e.g. nil pointers, are
+	// printed as text only
+	defer func() {
+		if recover() != nil {
+			hasText = false
+		}
+	}()
+
+	bundle, metadata, err := ValueBundle(x)
+	if bundle == nil || err != nil {
+		return false
+	}
+	if err := ipc.SendValueData(ipc.DisplayMessage{Data: bundle, Metadata: metadata}); err != nil {
+		return false
+	}
+
+	_, hasText = bundle[MIMEPlain]
+	return hasText
+}
+
+// ValueBundle returns the rich representations of x which Value sends, or nil if
+// it has none. It may panic for values such as nil pointers.
+func ValueBundle(x interface{}) (Bundle, map[string]interface{}, error) {
+	bundle := make(Bundle)
+	metadata := make(map[string]interface{})
+
+	if x, ok := x.(MIMEBundler); ok {
+		for mimeType, data := range x.MIMEBundle() {
+			bundle[mimeType] = encodeData(mimeType, data)
+		}
+	}
+	if x, ok := x.(HTMLer); ok {
+		bundle[MIMEHTML] = x.HTML()
+	}
+	if x, ok := x.(Markdowner); ok {
+		bundle[MIMEMarkdown] = x.Markdown()
+	}
+	if x, ok := x.(Latexer); ok {
+		bundle[MIMELatex] = x.Latex()
+	}
+	if x, ok := x.(SVGer); ok {
+		bundle[MIMESVG] = x.SVG()
+	}
+	if x, ok := x.(PNGer); ok {
+		bundle[MIMEPNG] = base64.StdEncoding.EncodeToString(x.PNG())
+	}
+	if x, ok := x.(JPEGer); ok {
+		bundle[MIMEJPEG] = base64.StdEncoding.EncodeToString(x.JPEG())
+	}
+
+	if img, ok := x.(image.Image); ok && bundle[MIMEPNG] == nil {
+		imgBundle, imgMetadata, err := imageBundle(img, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		for mimeType, data := range imgBundle {
+			if _, ok := bundle[mimeType]; !ok {
+				bundle[mimeType] = data
+			}
+		}
+		for mimeType, md := range imgMetadata {
+			metadata[mimeType] = md
+		}
+	}
+
+	if len(bundle) == 0 {
+		bundle = builtinBundle(x)
+	}
+	if len(bundle) == 0 {
+		return nil, nil, nil
+	}
+	return bundle, metadata, nil
+}
+
+// encodeData returns the representation of data of mimeType in a bundle.
+func encodeData(mimeType string, data []byte) interface{} {
+	switch {
+	case mimeType == MIMEJSON || strings.HasSuffix(mimeType, "+json"):
+		return json.RawMessage(data)
+	case strings.HasPrefix(mimeType, "text/") || mimeType == MIMESVG:
+		return string(data)
+	default:
+		return base64.StdEncoding.EncodeToString(data)
+	}
+}
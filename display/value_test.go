@@ -0,0 +1,70 @@
+package display
+
+import (
+	"encoding/json"
+	"image"
+	"testing"
+	"time"
+)
+
+type richValue struct{}
+
+func (richValue) HTML() string { return "rich html" }
+
+func (richValue) PNG() []byte { return []byte("png") }
+
+func (richValue) MIMEBundle() map[string][]byte {
+	return map[string][]byte{
+		MIMEJSON:        []byte(`{"rich": true}`),
+		"text/x-custom": []byte("custom"),
+	}
+}
+
+func TestValueBundle(t *testing.T) {
+	bundle, _, err := ValueBundle(richValue{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		MIMEHTML:        `"rich html"`,
+		MIMEPNG:         `"cG5n"`,
+		MIMEJSON:        `{"rich":true}`,
+		"text/x-custom": `"custom"`,
+	}
+	if len(bundle) != len(want) {
+		t.Errorf("got %d representations, want %d: %v", len(bundle), len(want), bundle)
+	}
+	for mimeType, data := range want {
+		raw, err := json.Marshal(bundle[mimeType])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(raw) != data {
+			t.Errorf("%s: got %s, want %s", mimeType, raw, data)
+		}
+	}
+}
+
+func TestValueBundle_image(t *testing.T) {
+	bundle, metadata, err := ValueBundle(image.NewRGBA(image.Rect(0, 0, 1000, 10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bundle[MIMEPNG] == nil || bundle[MIMEPlain] != "*image.RGBA 1000x10" {
+		t.Errorf("images should be sent as PNG: got %v", bundle)
+	}
+	if metadata[MIMEPNG] == nil {
+		t.Errorf("large images should be scaled down: got %v", metadata)
+	}
+}
+
+func TestValueBundle_plain(t *testing.T) {
+	for _, x := range []interface{}{42, "text", []int(nil), time.Second} {
+		bundle, _, err := ValueBundle(x)
+		if bundle != nil || err != nil {
+			t.Errorf("%#v should be printed as text only: got %v, %v", x, bundle, err)
+		}
+	}
+}
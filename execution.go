@@ -1,42 +1,44 @@
 package main
 
 import (
-	"fmt"
+	"github.com/fabian-z/gopherlab/ipc"
+	"github.com/fabian-z/gopherlab/protocol"
 	repl "github.com/fabian-z/gopherlab/replpkg"
+	zmq "github.com/pebbe/zmq4"
 	"go/token"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // REPLSession manages the I/O to/from the notebook
 var REPLSession *repl.Session
 var fset *token.FileSet
 
+// sessionMu guards REPLSession and sessionClosed against the interrupts, which are
+// not serialized by handlerMu; the handlers replacing or closing the session hold it.
+var (
+	sessionMu     sync.Mutex
+	sessionClosed bool
+)
+
 // ExecCounter is incremented each time we run user code in the notebook
 var ExecCounter int
 
 // SetupExecutionEnvironment initializes the REPL session and set of tmp files
 func SetupExecutionEnvironment() {
 
-	var err error
-	REPLSession, err = repl.NewSession()
+	session, err := repl.NewSession()
 	if err != nil {
 		panic(err)
 	}
 
-	fset = token.NewFileSet()
-}
-
-// OutputMsg holds the data for a pyout message.
-type OutputMsg struct {
-	Execcount int                    `json:"execution_count"`
-	Data      map[string]string      `json:"data"`
-	Metadata  map[string]interface{} `json:"metadata"`
-}
+	sessionMu.Lock()
+	REPLSession, sessionClosed = session, false
+	sessionMu.Unlock()
 
-// ErrMsg encodes the traceback of errors output to the notebook
-type ErrMsg struct {
-	EName     string   `json:"ename"`
-	EValue    string   `json:"evalue"`
-	Traceback []string `json:"traceback"`
+	fset = token.NewFileSet()
 }
 
 // HandleExecuteRequest runs code from an execute_request method, and sends the various
@@ -45,72 +47,283 @@ func HandleExecuteRequest(receipt MsgReceipt) {
 
 	// Actual execution handling
 
+	var req protocol.ExecuteRequest
+	if err := receipt.Msg.DecodeContent(&req); err != nil {
+		receipt.ReplyError("execute_reply", err)
+		return
+	}
+
 	reply := NewMsg("execute_reply", receipt.Msg)
-	content := make(map[string]interface{})
-	reqcontent := receipt.Msg.Content.(map[string]interface{})
-	code := reqcontent["code"].(string)
-	silent := reqcontent["silent"].(bool)
-	if !silent {
+	code, silent := req.Code, req.Silent
+	if req.StoreHistory {
 		ExecCounter++
 	}
-	content["execution_count"] = ExecCounter
 
-	// the compilation/execution magic happen here
-	val, err, stderr := REPLSession.Eval(code)
+	// let all frontends know what runs
+	if !silent {
+		input := NewMsg("execute_input", receipt.Msg)
+		input.Content = protocol.ExecuteInput{Code: code, ExecutionCount: ExecCounter}
+		receipt.Publish(input)
+	}
+	atomic.StoreInt32(&interrupted, 0)
 
-	if err == nil {
-		content["status"] = "ok"
-		content["payload"] = make([]map[string]interface{}, 0)
-		content["user_variables"] = make(map[string]string)
-		content["user_expressions"] = make(map[string]string)
-		if (len(val) > 0 || len(REPLSession.StdoutChannel) > 0 || len(REPLSession.StderrChannel) > 0) && !silent {
-			var outContent OutputMsg
-			out := NewMsg("execute_result", receipt.Msg)
-			outContent.Execcount = ExecCounter
-			outContent.Data = make(map[string]string)
-
-			//append output from channel messages
-
-			var stdoutString string
-			var stderrString string
-			var newlineVal, newlineStderr string
-
-			select {
-			case stdout := <-REPLSession.StdoutChannel:
-				stdoutString = stdout + "\n"
-			default:
+	// let the program read from stdin if the frontend supports it
+	if req.AllowStdin {
+		REPLSession.Input = func(prompt string, password bool) (string, error) {
+			return RequestInput(receipt, prompt, password)
+		}
+		defer func() {
+			REPLSession.Input = nil
+		}()
+	}
 
-			}
+	// stream the output of the program while it runs
+	var outStream, errStream *StreamWriter
+	if !silent {
+		outStream = NewStreamWriter(receipt, "stdout")
+		errStream = NewStreamWriter(receipt, "stderr")
+		REPLSession.Stdout, REPLSession.Stderr = outStream, errStream
+		defer func() {
+			REPLSession.Stdout, REPLSession.Stderr = nil, nil
+		}()
+	}
 
-			select {
-			case stderr := <-REPLSession.StderrChannel:
-				stderrString = stderr + "\n"
-			default:
+	// serve the rich output and the comms of the program
+	REPLSession.Handler = ProgramHandler(receipt, silent, outStream, errStream)
+	defer func() {
+		REPLSession.Handler = nil
+	}()
 
+	// drop the cells deleted in the notebook, as JupyterLab reports them; those
+	// the later cells still use are kept
+	if deleted, ok := receipt.Msg.Metadata["deletedCells"].([]interface{}); ok {
+		for _, id := range deleted {
+			if id, ok := id.(string); ok {
+				REPLSession.DropCell(id)
 			}
+		}
+	}
 
-			if len(val) > 0 {
-				newlineVal = "\n"
-			}
-			if len(stderrString) > 0 {
-				newlineStderr = "\n"
-			}
-			
-			outContent.Data["text/plain"] = fmt.Sprint(val + newlineVal + stdoutString + newlineStderr + stderrString)
-			outContent.Metadata = make(map[string]interface{})
-			out.Content = outContent
-			receipt.SendResponse(receipt.Sockets.IOPub_socket, out)
+	// the "run" evaluator evaluates the user expressions along with the cell
+	_, REPLSession.Exprs = userExpressions(req.UserExpressions)
+	defer func() {
+		REPLSession.Exprs = nil
+	}()
+
+	// the compilation/execution magic happen here
+	REPLSession.Cell = ExecCounter
+	REPLSession.CellID, _ = receipt.Msg.Metadata["cellId"].(string)
+	val, err, stderr := REPLSession.Eval(code)
+
+	if !silent {
+		outStream.Flush()
+		errStream.Flush()
+		PublishCommandOutput(receipt)
+	}
+
+	if err == nil {
+		if !silent {
+			PublishValues(receipt, val, REPLSession.Values())
+		}
+
+		// the rich output of the user expressions is not shown
+		REPLSession.Handler = ProgramHandler(receipt, true)
+
+		reply.Content = protocol.ExecuteReply{
+			Status:          "ok",
+			ExecutionCount:  ExecCounter,
+			Payload:         make([]map[string]interface{}, 0),
+			UserExpressions: EvalUserExpressions(req.UserExpressions),
 		}
 	} else {
-		content["ename"] = "ERROR"
-		content["evalue"] = err.Error()
-		content["traceback"] = []string{stderr.String()}
+		execErr := EvalError(err, stderr.String(), silent)
+
 		errormsg := NewMsg("error", receipt.Msg)
-		errormsg.Content = ErrMsg{"Error", err.Error(), []string{stderr.String()}}
-		receipt.SendResponse(receipt.Sockets.IOPub_socket, errormsg)
+		errormsg.Content = execErr
+		receipt.Publish(errormsg)
+
+		reply.Content = protocol.ExecuteErrorReply{
+			Status:         "error",
+			ExecutionCount: ExecCounter,
+			Error:          execErr,
+		}
+
+		if req.StopOnError {
+			abortQueued = true
+		}
 	}
 
 	// send the output back to the notebook
-	reply.Content = content
-	receipt.SendResponse(receipt.Sockets.Shell_socket, reply)
+	receipt.Reply(reply)
+}
+
+// PublishValues publishes the values of the cell run in response to receipt. The
+// printed text val is the execute_result, unless some of the values have rich
+// representations: then every value is published on its own, the last one as the
+// execute_result and the others as display_data.
+func PublishValues(receipt MsgReceipt, val string, values []repl.Value) {
+	var rich bool
+	for _, value := range values {
+		rich = rich || value.Data != nil
+	}
+
+	if !rich {
+		if len(val) > 0 {
+			values = []repl.Value{{Text: strings.TrimSuffix(val, "\n")}}
+		} else {
+			values = nil
+		}
+	}
+
+	for i, value := range values {
+		data, metadata := valueBundle(value)
+
+		if i < len(values)-1 {
+			out := NewMsg("display_data", receipt.Msg)
+			out.Content = protocol.DisplayData{Data: data, Metadata: metadata}
+			receipt.Publish(out)
+			continue
+		}
+
+		out := NewMsg("execute_result", receipt.Msg)
+		out.Content = protocol.ExecuteResult{
+			ExecutionCount: ExecCounter,
+			Data:           data,
+			Metadata:       metadata,
+		}
+		receipt.Publish(out)
+	}
+}
+
+// valueBundle returns the representations of value, with its printed text as
+// text/plain unless it provides one.
+func valueBundle(value repl.Value) (protocol.MIMEBundle, map[string]interface{}) {
+	data := protocol.MIMEBundle{"text/plain": value.Text}
+	for mimeType, repr := range value.Data {
+		data[mimeType] = repr
+	}
+
+	metadata := value.Metadata
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	return data, metadata
+}
+
+// ProgramHandler returns the handler for the ipc messages of the program run in
+// response to receipt. Unless silent, rich output is published, after the output
+// queued in streams.
+func ProgramHandler(receipt MsgReceipt, silent bool, streams ...*StreamWriter) ipc.Handler {
+	comms := CommHandler(receipt, REPLSession.Caller)
+
+	return func(conn *ipc.Conn, msg ipc.Message) {
+		switch msg.Type {
+		case "display_data", "update_display_data", "clear_output":
+			for _, stream := range streams {
+				if stream != nil {
+					stream.Flush()
+				}
+			}
+			HandleDisplayMsg(receipt, silent, conn, msg)
+		default:
+			comms(conn, msg)
+		}
+	}
+}
+
+// EvalUserExpressions evaluates the user_expressions of an execute_request against
+// the session, and returns their results by name.
+func EvalUserExpressions(exprs map[string]string) map[string]protocol.ExpressionResult {
+	results := make(map[string]protocol.ExpressionResult, len(exprs))
+	if len(exprs) == 0 {
+		return results
+	}
+
+	names, codes := userExpressions(exprs)
+	values, errs := REPLSession.EvalExprs(codes)
+	for i, name := range names {
+		if errs[i] != nil {
+			exprErr := EvalError(errs[i], errs[i].Error(), true)
+			results[name] = protocol.ExpressionResult{Status: "error", Error: &exprErr}
+			continue
+		}
+		data, metadata := valueBundle(values[i])
+		results[name] = protocol.ExpressionResult{
+			Status:   "ok",
+			Data:     data,
+			Metadata: metadata,
+		}
+	}
+
+	return results
+}
+
+// abortQueued is set when an execute_request with stop_on_error fails, so that the
+// execute requests queued behind it are aborted.
+var abortQueued bool
+
+// AbortQueuedRequests replies with status "aborted" to the execute requests waiting
+// on the shell socket. Other queued messages are handled as usual.
+func AbortQueuedRequests(sockets SocketGroup) {
+	for {
+		msgparts, err := sockets.Shell_socket.RecvMessageBytes(zmq.DONTWAIT)
+		if err != nil {
+			// nothing queued anymore
+			return
+		}
+		msg, ids, err := WireMsgToComposedMsg(msgparts, sockets.Signer)
+		if err != nil {
+			logger.Println("Skipping shell message:", err)
+			continue
+		}
+
+		receipt := MsgReceipt{msg, ids, sockets, sockets.Shell_socket}
+		if msg.Header.MsgType != "execute_request" {
+			HandleShellMsg(receipt)
+			continue
+		}
+
+		logger.Println("Aborting queued execute_request")
+		HandleWithStatus(receipt, func(receipt MsgReceipt) {
+			reply := NewMsg("execute_reply", receipt.Msg)
+			reply.Content = protocol.AbortedReply{Status: "aborted"}
+			receipt.Reply(reply)
+		})
+	}
+}
+
+// PublishCommandOutput publishes the output of session commands, such as :print,
+// as stream messages.
+func PublishCommandOutput(receipt MsgReceipt) {
+	streams := []struct {
+		name string
+		ch   chan string
+	}{
+		{"stdout", REPLSession.StdoutChannel},
+		{"stderr", REPLSession.StderrChannel},
+	}
+
+	for _, stream := range streams {
+		select {
+		case text := <-stream.ch:
+			msg := NewMsg("stream", receipt.Msg)
+			msg.Content = protocol.Stream{Name: stream.name, Text: text + "\n"}
+			receipt.Publish(msg)
+		default:
+		}
+	}
+}
+
+// userExpressions returns the names and the code of the user_expressions exprs,
+// sorted by name.
+func userExpressions(exprs map[string]string) (names, codes []string) {
+	for name := range exprs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		codes = append(codes, exprs[name])
+	}
+	return names, codes
 }
@@ -3,18 +3,19 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/fabian-z/gopherlab/protocol"
 	zmq "github.com/pebbe/zmq4"
 	"io"
 	"io/ioutil"
 	"log"
 	"os"
+	"os/signal"
 	"runtime"
+	"sync"
 )
 
 var logger *log.Logger
 
-const protocolVersion string = "5.0"
-
 var connectionInfo ConnectionInfo
 
 // ConnectionInfo stores the contents of the kernel connection file created by Jupyter.
@@ -31,13 +32,14 @@ type ConnectionInfo struct {
 }
 
 // SocketGroup holds the sockets needed to communicate with the kernel, and
-// the key for message signing.
+// the signer for message signing.
 type SocketGroup struct {
 	Shell_socket   *zmq.Socket
 	Control_socket *zmq.Socket
 	Stdin_socket   *zmq.Socket
 	IOPub_socket   *zmq.Socket
-	Key            []byte
+	Signer         *Signer
+	Context        *zmq.Context
 }
 
 // PrepareSockets sets up the ZMQ sockets through which the kernel will communicate.
@@ -49,6 +51,7 @@ func PrepareSockets() (sg SocketGroup) {
 	if err != nil {
 		logger.Fatal(err)
 	}
+	sg.Context = context
 	sg.Shell_socket, err = context.NewSocket(zmq.ROUTER)
 	if err != nil {
 		logger.Fatal(err)
@@ -84,8 +87,11 @@ func PrepareSockets() (sg SocketGroup) {
 		logger.Fatal("sg.IOPub_socket bind:", err)
 	}
 
-	// Message signing key
-	sg.Key = []byte(connectionInfo.Key)
+	// Message signing key and scheme
+	sg.Signer, err = NewSigner([]byte(connectionInfo.Key), connectionInfo.Signature_scheme)
+	if err != nil {
+		logger.Fatal(err)
+	}
 
 	// Start the heartbeat device
 	HB_socket, err := context.NewSocket(zmq.REP)
@@ -100,6 +106,10 @@ func PrepareSockets() (sg SocketGroup) {
 
 	go func() {
 		err := zmq.Proxy(HB_socket, HB_socket, nil)
+		if zmq.AsErrno(err) == zmq.ETERM {
+			HB_socket.Close()
+			return
+		}
 		if err != nil {
 			logger.Fatal(err)
 		}
@@ -118,44 +128,40 @@ func HandleShellMsg(receipt MsgReceipt) {
 		HandleWithStatus(receipt, HandleConnectRequest)
 	case "execute_request":
 		HandleWithStatus(receipt, HandleExecuteRequest)
+	case "complete_request":
+		HandleWithStatus(receipt, HandleCompleteRequest)
+	case "inspect_request":
+		HandleWithStatus(receipt, HandleInspectRequest)
+	case "is_complete_request":
+		HandleWithStatus(receipt, HandleIsCompleteRequest)
+	case "comm_open":
+		HandleWithStatus(receipt, HandleCommOpen)
+	case "comm_msg":
+		HandleWithStatus(receipt, HandleCommMsg)
+	case "comm_close":
+		HandleWithStatus(receipt, HandleCommClose)
+	case "comm_info_request":
+		HandleWithStatus(receipt, HandleCommInfoRequest)
 	case "shutdown_request":
 		HandleWithStatus(receipt, HandleShutdownRequest)
 	default:
-		logger.Println("Unhandled shell message:", receipt.Msg.Header.MsgType)
+		HandleWithStatus(receipt, func(receipt MsgReceipt) {
+			logger.Println("Unhandled shell message:", receipt.Msg.Header.MsgType)
+		})
 	}
 
 }
 
-// KernelInfo holds information about the igo kernel, for kernel_info_reply messages.
-type KernelInfo struct {
-	ProtocolVersion       string             `json:"protocol_version"`
-	Implementation        string             `json:"implementation"`
-	ImplementationVersion string             `json:"implementation_version"`
-	LanguageInfo          KernelLanguageInfo `json:"language_info"`
-	Banner                string             `json:"banner"`
-}
-
-type KernelLanguageInfo struct {
-	Name          string `json:"name"`
-	Version       string `json:"version"`
-	Mimetype      string `json:"mimetype"`
-	FileExtension string `json:"file_extension"`
-}
-
-// KernelStatus holds a kernel state, for status broadcast messages.
-type KernelStatus struct {
-	ExecutionState string `json:"execution_state"`
-}
-
 // SendKernelInfo sends a kernel_info_reply message.
 func SendKernelInfo(receipt MsgReceipt) {
 	reply := NewMsg("kernel_info_reply", receipt.Msg)
 
-	reply.Content = KernelInfo{
-		ProtocolVersion:       protocolVersion,
+	reply.Content = protocol.KernelInfoReply{
+		Status:                "ok",
+		ProtocolVersion:       protocol.Version,
 		Implementation:        "gopherlab",
 		ImplementationVersion: "0.1",
-		LanguageInfo: KernelLanguageInfo{
+		LanguageInfo: protocol.LanguageInfo{
 			Name:          "go",
 			Version:       runtime.Version(),
 			Mimetype:      "application/x-golang", // text/plain would be possible, too
@@ -164,44 +170,79 @@ func SendKernelInfo(receipt MsgReceipt) {
 		Banner: "Gopherlab - https://github.com/fabian-z/gopherlab",
 	}
 
-	receipt.SendResponse(receipt.Sockets.Shell_socket, reply)
+	receipt.Reply(reply)
 }
 
-// ShutdownReply encodes a boolean indication of shutdown/restart
-type ShutdownReply struct {
-	Restart bool `json:"restart"`
-}
+// shutdown is closed once a shutdown_request without restart has been answered;
+// shutdownOnce closes it, as another one may come before the kernel stops.
+var (
+	shutdown     = make(chan struct{})
+	shutdownOnce sync.Once
+)
 
-// HandleShutdownRequest sends a "shutdown" message
+// HandleShutdownRequest sends a "shutdown" message. On restart, the session is
+// replaced by a fresh one and the kernel keeps running; otherwise the kernel stops.
+// Either way, the temporary files of the session are removed.
 func HandleShutdownRequest(receipt MsgReceipt) {
+	var req protocol.ShutdownRequest
+	if err := receipt.Msg.DecodeContent(&req); err != nil {
+		receipt.ReplyError("shutdown_reply", err)
+		return
+	}
+	restart := req.Restart
+
 	reply := NewMsg("shutdown_reply", receipt.Msg)
-	content := receipt.Msg.Content.(map[string]interface{})
-	restart := content["restart"].(bool)
-	reply.Content = ShutdownReply{restart}
-	receipt.SendResponse(receipt.Sockets.Shell_socket, reply)
+	reply.Content = protocol.ShutdownReply{Status: "ok", Restart: restart}
+	receipt.Reply(reply)
+
+	sessionMu.Lock()
+	if err := REPLSession.Close(); err != nil {
+		logger.Println("Closing session:", err)
+	}
+	sessionClosed = true
+	sessionMu.Unlock()
+
+	if restart {
+		logger.Println("Restarting in response to shutdown_request")
+		SetupExecutionEnvironment()
+		ExecCounter = 0
+		resetComms()
+		PublishStatus(receipt, "starting")
+		return
+	}
+
 	logger.Println("Shutting down in response to shutdown_request")
-	os.Exit(0)
+	shutdownOnce.Do(func() { close(shutdown) })
 }
 
-// ConnectReply encodes the ports necessary for connecting to the kernel
-type ConnectReply struct {
-	ShellPort int `json:"shell_port"`
-	IOPubPort int `json:"iopub_port"`
-	StdinPort int `json:"stdin_port"`
-	HBPort    int `json:"hb_port"`
+// StopKernel stops the kernel once the message being handled is done: the IOPub
+// publisher sends the queued messages, and the ZMQ context of sockets is terminated.
+// Every goroutine then closes the sockets it owns.
+func StopKernel(sockets SocketGroup) {
+	handlerMu.Lock()
+	handlerMu.Unlock()
+
+	close(stopPublishing)
+	<-publisherDone
+
+	if err := sockets.Context.Term(); err != nil {
+		logger.Println("Terminating ZMQ context:", err)
+	}
 }
 
+// HandleConnectRequest sends a connect_reply with the ports of the kernel.
 func HandleConnectRequest(receipt MsgReceipt) {
 	reply := NewMsg("connect_reply", receipt.Msg)
 
-	reply.Content = ConnectReply{
-		ShellPort: connectionInfo.Shell_port,
-		IOPubPort: connectionInfo.IOPub_port,
-		StdinPort: connectionInfo.Stdin_port,
-		HBPort:    connectionInfo.HB_port,
+	reply.Content = protocol.ConnectReply{
+		ShellPort:   connectionInfo.Shell_port,
+		IOPubPort:   connectionInfo.IOPub_port,
+		StdinPort:   connectionInfo.Stdin_port,
+		HBPort:      connectionInfo.HB_port,
+		ControlPort: connectionInfo.Control_port,
 	}
 
-	receipt.SendResponse(receipt.Sockets.Shell_socket, reply)
+	receipt.Reply(reply)
 }
 
 // RunKernel is the main entry point to start the kernel. This is what is called by the
@@ -226,41 +267,102 @@ func RunKernel(connection_file string, logwriter io.Writer) {
 	// Set up the ZMQ sockets through which the kernel will communicate
 	sockets := PrepareSockets()
 
-	pi := zmq.NewPoller()
+	// Every socket is owned by one goroutine: shell by the loop below, control,
+	// stdin and IOPub by the goroutines started here
+	go PublishMsgs(sockets)
+	go HandleStdinMsgs(sockets)
 
-	pi.Add(sockets.Shell_socket, zmq.POLLIN)
-	pi.Add(sockets.Stdin_socket, zmq.POLLIN)
-	pi.Add(sockets.Control_socket, zmq.POLLIN)
+	// there is no request yet the status could respond to
+	PublishStatus(MsgReceipt{Sockets: sockets}, "starting")
+
+	// Interrupts arrive as interrupt_request on the control socket, or as SIGINT
+	// if the kernel is configured for signal interrupts
+	go HandleControlMsgs(sockets)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	go func() {
+		for range sigs {
+			logger.Println("Interrupting in response to SIGINT")
+			InterruptExecution()
+		}
+	}()
+
+	stopped := make(chan struct{})
+	go func() {
+		<-shutdown
+		StopKernel(sockets)
+		close(stopped)
+	}()
 
-	var msgparts [][]byte
-	var polled []zmq.Polled
 	// Message receiving loop:
 	for {
-		polled, err = pi.Poll(-1)
+		msgparts, err := sockets.Shell_socket.RecvMessageBytes(0)
+		if zmq.AsErrno(err) == zmq.ETERM {
+			sockets.Shell_socket.Close()
+			<-stopped
+			return
+		}
+		if err != nil {
+			logger.Println(err)
+			continue
+		}
+		msg, ids, err := WireMsgToComposedMsg(msgparts, sockets.Signer)
+		if err != nil {
+			logger.Println("Skipping shell message:", err)
+			continue
+		}
+		logger.Println("received shell message: ", msg)
+		handlerMu.Lock()
+		HandleShellMsg(MsgReceipt{msg, ids, sockets, sockets.Shell_socket})
+		if abortQueued {
+			abortQueued = false
+			AbortQueuedRequests(sockets)
+		}
+		handlerMu.Unlock()
+	}
+}
+
+// handlerMu serializes the handling of shell messages and of the control messages
+// changing the session. It is held while a cell runs.
+var handlerMu sync.Mutex
+
+// HandleControlMsgs receives and responds to messages on the control ROUTER socket,
+// independently of the shell socket: a running cell does not delay them.
+func HandleControlMsgs(sockets SocketGroup) {
+	for {
+		msgparts, err := sockets.Control_socket.RecvMessageBytes(0)
+		if zmq.AsErrno(err) == zmq.ETERM {
+			sockets.Control_socket.Close()
+			return
+		}
+		if err != nil {
+			logger.Println(err)
+			continue
+		}
+		msg, ids, err := WireMsgToComposedMsg(msgparts, sockets.Signer)
 		if err != nil {
-			log.Fatalln(err)
+			logger.Println("Skipping control message:", err)
+			continue
 		}
-		switch {
-		case polled[0].Events&zmq.POLLIN != 0: // shell socket
-			msgparts, _ = polled[0].Socket.RecvMessageBytes(0)
-			msg, ids, err := WireMsgToComposedMsg(msgparts, sockets.Key)
-			if err != nil {
-				logger.Println(err)
-				return
-			}
-			logger.Println("received shell message: ", msg)
-			HandleShellMsg(MsgReceipt{msg, ids, sockets})
-		case polled[1].Events&zmq.POLLIN != 0: // stdin socket - not implemented.
-			polled[1].Socket.RecvMessageBytes(0)
-		case polled[2].Events&zmq.POLLIN != 0: // control socket - treat like shell socket.
-			msgparts, _ = polled[2].Socket.RecvMessageBytes(0)
-			msg, ids, err := WireMsgToComposedMsg(msgparts, sockets.Key)
-			if err != nil {
-				logger.Println(err)
-				return
-			}
-			logger.Println("received control message: ", msg)
-			HandleShellMsg(MsgReceipt{msg, ids, sockets})
+		logger.Println("received control message: ", msg)
+
+		receipt := MsgReceipt{msg, ids, sockets, sockets.Control_socket}
+		switch msg.Header.MsgType {
+		case "interrupt_request":
+			HandleWithStatus(receipt, HandleInterruptRequest)
+		case "kernel_info_request":
+			HandleWithStatus(receipt, SendKernelInfo)
+		case "shutdown_request":
+			// stop the running cell rather than waiting for it
+			InterruptExecution()
+			handlerMu.Lock()
+			HandleWithStatus(receipt, HandleShutdownRequest)
+			handlerMu.Unlock()
+		default:
+			HandleWithStatus(receipt, func(receipt MsgReceipt) {
+				logger.Println("Unhandled control message:", receipt.Msg.Header.MsgType)
+			})
 		}
 	}
 }
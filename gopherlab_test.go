@@ -1,7 +1,9 @@
 package main
 
 import (
+	"github.com/fabian-z/gopherlab/protocol"
 	repl "github.com/fabian-z/gopherlab/replpkg"
+	"strings"
 	"testing"
 )
 
@@ -104,3 +106,145 @@ func TestRun_Const(t *testing.T) {
 		noError(t, err)
 	}
 }
+
+// TestCompleteUTF8 makes sure stream chunks are not split within a character
+func TestCompleteUTF8(t *testing.T) {
+	tests := []struct {
+		in string
+		n  int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"gopher ʕ◔ϖ◔ʔ", len("gopher ʕ◔ϖ◔ʔ")},
+		{"ʕ◔ϖ◔ʔ"[:4], 2},
+		{"ʕ◔ϖ◔ʔ"[:3], 2},
+	}
+
+	for _, test := range tests {
+		if n := completeUTF8([]byte(test.in)); n != test.n {
+			t.Errorf("completeUTF8(%q) should be %d: got %d", test.in, test.n, n)
+		}
+	}
+}
+
+// TestSigner tests signing and verifying messages with several schemes
+func TestSigner(t *testing.T) {
+	msg := ComposedMsg{Header: protocol.Header{MsgID: "1", MsgType: "kernel_info_request"}}
+
+	for _, scheme := range []string{"", "hmac-sha1", "hmac-sha256", "hmac-sha512"} {
+		signer, err := NewSigner([]byte("secret"), scheme)
+		noError(t, err)
+
+		msgparts := append([][]byte{[]byte("id"), []byte("<IDS|MSG>")}, msg.ToWireMsg(signer)...)
+		received, _, err := WireMsgToComposedMsg(msgparts, signer)
+		noError(t, err)
+		if received.Header != msg.Header {
+			t.Errorf("%s: header should be %+v: got %+v", scheme, msg.Header, received.Header)
+		}
+
+		if _, _, err := WireMsgToComposedMsg(msgparts, signer); err == nil {
+			t.Errorf("%s: replayed message should be rejected", scheme)
+		}
+
+		other, _ := NewSigner([]byte("other"), scheme)
+		if _, _, err := WireMsgToComposedMsg(msgparts, other); err == nil {
+			t.Errorf("%s: message signed with another key should be rejected", scheme)
+		}
+	}
+
+	if _, err := NewSigner([]byte("secret"), "hmac-foo"); err == nil {
+		t.Error("unknown scheme should be an error")
+	}
+}
+
+// TestWireMsgToComposedMsg_malformed makes sure malformed messages are rejected with an error
+func TestWireMsgToComposedMsg_malformed(t *testing.T) {
+	signer, err := NewSigner(nil, "")
+	noError(t, err)
+
+	header := []byte(`{"msg_id": "1", "msg_type": "execute_request"}`)
+	tests := [][][]byte{
+		{},
+		{[]byte("id")},
+		{[]byte("<IDS|MSG>"), nil, header, []byte("{}"), []byte("{}")},
+		{[]byte("<IDS|MSG>"), nil, []byte("{"), []byte("{}"), []byte("{}"), []byte("{}")},
+		{[]byte("<IDS|MSG>"), nil, header, []byte("{}"), []byte("{}"), []byte("{")},
+		{[]byte("<IDS|MSG>"), nil, []byte("{}"), []byte("{}"), []byte("{}"), []byte("{}")},
+	}
+
+	for _, msgparts := range tests {
+		if _, _, err := WireMsgToComposedMsg(msgparts, signer); err == nil {
+			t.Errorf("%q should be rejected", msgparts)
+		}
+	}
+}
+
+// TestWireMsgToComposedMsg_buffers makes sure binary buffers are kept and sent back out
+func TestWireMsgToComposedMsg_buffers(t *testing.T) {
+	signer, err := NewSigner([]byte("secret"), "")
+	noError(t, err)
+
+	msg := ComposedMsg{
+		Header:  protocol.Header{MsgID: "1", MsgType: "comm_msg"},
+		Buffers: [][]byte{[]byte("\x00\x01"), []byte("buf")},
+	}
+
+	msgparts := append([][]byte{[]byte("<IDS|MSG>")}, msg.ToWireMsg(signer)...)
+	received, _, err := WireMsgToComposedMsg(msgparts, signer)
+	noError(t, err)
+
+	if len(received.Buffers) != 2 || string(received.Buffers[0]) != "\x00\x01" || string(received.Buffers[1]) != "buf" {
+		t.Errorf("buffers should be kept: got %q", received.Buffers)
+	}
+}
+
+func TestCompileErrorTraceback(t *testing.T) {
+	err := &repl.CompileError{Errors: []repl.CellError{
+		{CellPos: repl.CellPos{Cell: 2, Line: 1, Col: 7, Source: "\tx := y"}, Msg: "undefined: y"},
+		{Msg: "too many errors"},
+	}}
+
+	want := []string{
+		"\x1b[0;31mCell [2], line 1:7\x1b[0m: undefined: y",
+		"    \tx := y",
+		"    \t     \x1b[1;31m^\x1b[0m",
+		"\x1b[0;31mtoo many errors\x1b[0m",
+	}
+	got := compileErrorTraceback(err)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEvalError_panic(t *testing.T) {
+	err := &repl.PanicError{Value: "boom", Type: "*errors.errorString", Frames: []repl.Frame{
+		{Func: "runtime.goPanicIndex"},
+		{Func: "main.f", Pos: repl.CellPos{Cell: 1, Line: 2, Source: "\treturn a[i]"}},
+		{Func: "main.main", Pos: repl.CellPos{Cell: 3, Line: 1, Source: "f(5)"}},
+		{Func: "runtime.main"},
+		{Func: "runtime.goexit"},
+	}}
+
+	got := EvalError(err, "", false)
+	if got.EName != "*errors.errorString" || got.EValue != "boom" {
+		t.Errorf("got ename %q, evalue %q", got.EName, got.EValue)
+	}
+
+	want := []string{
+		"\x1b[1;31m*errors.errorString\x1b[0m: boom",
+		"    [1 call in the runtime or packages]",
+		"\x1b[0;31mCell [1], line 2\x1b[0m, in main.f",
+		"    \treturn a[i]",
+		"\x1b[0;31mCell [3], line 1\x1b[0m, in main.main",
+		"    f(5)",
+		"    [2 calls in the runtime or packages]",
+	}
+	if strings.Join(got.Traceback, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q, want %q", got.Traceback, want)
+	}
+
+	deadlock := EvalError(&repl.PanicError{Value: "all goroutines are asleep - deadlock!", Fatal: true}, "", false)
+	if deadlock.EName != "Deadlock" {
+		t.Errorf("got ename %q", deadlock.EName)
+	}
+}
@@ -0,0 +1,149 @@
+package interp
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// builtin calls the builtin function name with the arguments args of call. The
+// arguments of make and new, which may be types, are evaluated by builtin.
+func (fr *frame) builtin(name string, call *ast.CallExpr, args []reflect.Value) reflect.Value {
+	t := fr.code.info.Types[call].Type
+
+	switch name {
+	case "len":
+		x := args[0]
+		if x.Kind() == reflect.Ptr {
+			x = deref(x)
+		}
+		return reflect.ValueOf(x.Len())
+	case "cap":
+		x := args[0]
+		if x.Kind() == reflect.Ptr {
+			x = deref(x)
+		}
+		return reflect.ValueOf(x.Cap())
+	case "append":
+		s := converted(args[0], fr.in.rtype(t))
+		if call.Ellipsis.IsValid() {
+			rest := args[1]
+			if rest.Kind() == reflect.String {
+				rest = rest.Convert(reflect.TypeOf([]byte(nil)))
+			}
+			return reflect.AppendSlice(s, converted(rest, s.Type()))
+		}
+		for _, arg := range args[1:] {
+			s = reflect.Append(s, converted(arg, s.Type().Elem()))
+		}
+		return s
+	case "make":
+		rt := fr.in.rtype(t)
+		var sizes []int
+		for _, arg := range call.Args[1:] {
+			sizes = append(sizes, toInt(fr.eval(arg)))
+		}
+		switch rt.Kind() {
+		case reflect.Slice:
+			n, c := sizes[0], sizes[0]
+			if len(sizes) > 1 {
+				c = sizes[1]
+			}
+			if n < 0 || c < n {
+				_ = make([]struct{}, n, c)
+			}
+			return reflect.MakeSlice(rt, n, c)
+		case reflect.Map:
+			n := 0
+			if len(sizes) > 0 {
+				n = sizes[0]
+			}
+			return reflect.MakeMapWithSize(rt, n)
+		case reflect.Chan:
+			n := 0
+			if len(sizes) > 0 {
+				n = sizes[0]
+			}
+			return reflect.MakeChan(rt, n)
+		}
+	case "new":
+		tv := fr.code.info.Types[call.Args[0]]
+		if tv.IsType() {
+			return reflect.New(fr.in.rtype(tv.Type))
+		}
+		p := reflect.New(fr.in.rtype(t).Elem())
+		setValue(p.Elem(), fr.eval(call.Args[0]))
+		return p
+	case "delete":
+		m := args[0]
+		m.SetMapIndex(converted(args[1], m.Type().Key()), reflect.Value{})
+		return reflect.Value{}
+	case "clear":
+		args[0].Clear()
+		return reflect.Value{}
+	case "copy":
+		dst, src := args[0], args[1]
+		if src.Kind() == reflect.String {
+			src = src.Convert(reflect.TypeOf([]byte(nil)))
+		}
+		return reflect.ValueOf(reflect.Copy(dst, src))
+	case "close":
+		args[0].Close()
+		return reflect.Value{}
+	case "panic":
+		if !args[0].IsValid() {
+			panic(nil)
+		}
+		panic(args[0].Interface())
+	case "recover":
+		v := reflect.New(emptyInterfaceType).Elem()
+		if d := fr.deferring; d != nil && d.panicking && !d.recovered {
+			d.recovered = true
+			fr.in.resetTrace()
+			v.Set(reflect.ValueOf(d.panicValue))
+		}
+		return v
+	case "print", "println":
+		var parts []string
+		for _, arg := range args {
+			parts = append(parts, fmt.Sprint(arg))
+		}
+		sep, end := "", ""
+		if name == "println" {
+			sep, end = " ", "\n"
+		}
+		stderr, _ := fr.in.symbol("os", "Stderr")
+		fmt.Fprint(stderr.Elem().Interface().(io.Writer), strings.Join(parts, sep)+end)
+		return reflect.Value{}
+	case "complex":
+		v := reflect.New(fr.in.rtype(t)).Elem()
+		v.SetComplex(complex(args[0].Float(), args[1].Float()))
+		return v
+	case "real", "imag":
+		v := reflect.New(fr.in.rtype(t)).Elem()
+		c := args[0].Complex()
+		if name == "real" {
+			v.SetFloat(real(c))
+		} else {
+			v.SetFloat(imag(c))
+		}
+		return v
+	case "min", "max":
+		op := token.LSS
+		if name == "max" {
+			op = token.GTR
+		}
+		m := args[0]
+		for _, arg := range args[1:] {
+			if compare(op, arg, m) || isNaN(arg, arg) {
+				m = arg
+			}
+		}
+		return converted(m, fr.in.rtype(t))
+	}
+
+	panic(&UnsupportedError{call.Pos(), "builtin function " + name + " is not supported"})
+}
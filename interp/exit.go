@@ -0,0 +1,100 @@
+package interp
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+)
+
+// exitFunc replaces os.Exit, which would exit the calling process: it stops the
+// statements running instead.
+func exitFunc(code int) {
+	panic(exit(code))
+}
+
+// logFuncs returns the functions of package log, which use the standard logger of
+// the process, using l instead. Those which call os.Exit stop the statements
+// running instead.
+func logFuncs(l *log.Logger) map[string]interface{} {
+	return map[string]interface{}{
+		"Default": func() *log.Logger {
+			return l
+		},
+		"Fatal": func(v ...interface{}) {
+			l.Output(2, fmt.Sprint(v...))
+			panic(exit(1))
+		},
+		"Fatalf": func(format string, v ...interface{}) {
+			l.Output(2, fmt.Sprintf(format, v...))
+			panic(exit(1))
+		},
+		"Fatalln": func(v ...interface{}) {
+			l.Output(2, fmt.Sprintln(v...))
+			panic(exit(1))
+		},
+		"Flags": l.Flags,
+		"Output": func(calldepth int, s string) error {
+			return l.Output(calldepth+1, s)
+		},
+		"Panic": func(v ...interface{}) {
+			s := fmt.Sprint(v...)
+			l.Output(2, s)
+			panic(s)
+		},
+		"Panicf": func(format string, v ...interface{}) {
+			s := fmt.Sprintf(format, v...)
+			l.Output(2, s)
+			panic(s)
+		},
+		"Panicln": func(v ...interface{}) {
+			s := fmt.Sprintln(v...)
+			l.Output(2, s)
+			panic(s)
+		},
+		"Prefix": l.Prefix,
+		"Print": func(v ...interface{}) {
+			l.Output(2, fmt.Sprint(v...))
+		},
+		"Printf": func(format string, v ...interface{}) {
+			l.Output(2, fmt.Sprintf(format, v...))
+		},
+		"Println": func(v ...interface{}) {
+			l.Output(2, fmt.Sprintln(v...))
+		},
+		"SetFlags":  l.SetFlags,
+		"SetOutput": l.SetOutput,
+		"SetPrefix": l.SetPrefix,
+		"Writer":    l.Writer,
+	}
+}
+
+var loggerType = reflect.TypeOf((*log.Logger)(nil))
+
+// exitMethod returns the replacement of the method name of x if it would call
+// os.Exit, as the Fatal methods of log.Logger do.
+func exitMethod(x reflect.Value, name string) (reflect.Value, bool) {
+	if x.Type() != loggerType || !strings.HasPrefix(name, "Fatal") {
+		return reflect.Value{}, false
+	}
+
+	l := x.Interface().(*log.Logger)
+	switch name {
+	case "Fatal":
+		return reflect.ValueOf(func(v ...interface{}) {
+			l.Output(2, fmt.Sprint(v...))
+			panic(exit(1))
+		}), true
+	case "Fatalf":
+		return reflect.ValueOf(func(format string, v ...interface{}) {
+			l.Output(2, fmt.Sprintf(format, v...))
+			panic(exit(1))
+		}), true
+	case "Fatalln":
+		return reflect.ValueOf(func(v ...interface{}) {
+			l.Output(2, fmt.Sprintln(v...))
+			panic(exit(1))
+		}), true
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,553 @@
+package interp
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"unsafe"
+)
+
+// unparen returns expr without enclosing parentheses.
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.X
+	}
+}
+
+// eval returns the value of expr; the zero Value stands for nil. Variables and
+// what they hold are returned addressable, and must be copied to be kept.
+func (fr *frame) eval(expr ast.Expr) reflect.Value {
+	tv := fr.code.info.Types[expr]
+	if tv.Value != nil {
+		return constValue(tv.Value, fr.in.rtype(tv.Type))
+	}
+
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return fr.eval(expr.X)
+	case *ast.Ident:
+		return fr.ident(expr)
+	case *ast.FuncLit:
+		return fr.funcLit(expr)
+	case *ast.CompositeLit:
+		return fr.compositeLit(expr, fr.in.rtype(tv.Type))
+	case *ast.SelectorExpr:
+		return fr.selector(expr)
+	case *ast.IndexExpr:
+		return fr.index(expr)
+	case *ast.SliceExpr:
+		return fr.slice(expr)
+	case *ast.StarExpr:
+		return deref(fr.eval(expr.X))
+	case *ast.TypeAssertExpr:
+		x := fr.eval(expr.X)
+		t := fr.in.rtype(tv.Type)
+		v, ok := fr.assertType(x, t)
+		if !ok {
+			panic(&typeAssertionError{x, t})
+		}
+		return v
+	case *ast.UnaryExpr:
+		return fr.unary(expr, tv.Type)
+	case *ast.BinaryExpr:
+		return fr.binary(expr, tv.Type)
+	case *ast.CallExpr:
+		results := fr.callExpr(expr)
+		if len(results) == 0 {
+			return reflect.Value{}
+		}
+		return results[0]
+	}
+
+	panic(&UnsupportedError{expr.Pos(), "expression is not supported"})
+}
+
+// evalMulti returns the values of expr, which may have several: the results of a
+// call, or the value and whether there is one, of a map index, type assertion or
+// receive operation.
+func (fr *frame) evalMulti(expr ast.Expr) []reflect.Value {
+	expr = unparen(expr)
+	if call, ok := expr.(*ast.CallExpr); ok {
+		return fr.callExpr(call)
+	}
+
+	if _, ok := fr.code.info.Types[expr].Type.(*types.Tuple); !ok {
+		return []reflect.Value{fr.eval(expr)}
+	}
+
+	switch expr := expr.(type) {
+	case *ast.IndexExpr:
+		m := fr.eval(expr.X)
+		v := m.MapIndex(converted(fr.eval(expr.Index), m.Type().Key()))
+		if !v.IsValid() {
+			return []reflect.Value{reflect.Zero(m.Type().Elem()), reflect.ValueOf(false)}
+		}
+		return []reflect.Value{v, reflect.ValueOf(true)}
+	case *ast.TypeAssertExpr:
+		t := fr.in.rtype(fr.code.info.Types[expr].Type.(*types.Tuple).At(0).Type())
+		v, ok := fr.assertType(fr.eval(expr.X), t)
+		if !ok {
+			v = reflect.Zero(t)
+		}
+		return []reflect.Value{v, reflect.ValueOf(ok)}
+	case *ast.UnaryExpr:
+		v, ok := fr.recv(fr.eval(expr.X))
+		return []reflect.Value{v, reflect.ValueOf(ok)}
+	}
+
+	panic(&UnsupportedError{expr.Pos(), "expression is not supported"})
+}
+
+func (fr *frame) ident(ident *ast.Ident) reflect.Value {
+	switch obj := fr.code.info.Uses[ident].(type) {
+	case *types.Var:
+		return fr.variable(obj)
+	case *types.Nil:
+		return reflect.Value{}
+	case *types.Func:
+		if v, ok := fr.in.symbol("main", obj.Name()); ok {
+			return v
+		}
+	}
+
+	panic(&UnsupportedError{ident.Pos(), ident.Name + " is not supported"})
+}
+
+// selector returns the member of a package, field or method selected by sel.
+func (fr *frame) selector(sel *ast.SelectorExpr) reflect.Value {
+	if ident, ok := sel.X.(*ast.Ident); ok {
+		if pkgName, ok := fr.code.info.Uses[ident].(*types.PkgName); ok {
+			v, _ := fr.in.symbol(pkgName.Imported().Path(), sel.Sel.Name)
+			if _, ok := fr.code.info.Uses[sel.Sel].(*types.Var); ok {
+				return v.Elem()
+			}
+			return v
+		}
+	}
+
+	selection := fr.code.info.Selections[sel]
+	switch selection.Kind() {
+	case types.FieldVal:
+		x := fr.eval(sel.X)
+		for _, i := range selection.Index() {
+			x = field(deref(x), i)
+		}
+		return x
+	case types.MethodVal:
+		return fr.method(sel, selection)
+	}
+
+	// a method expression
+	t := fr.in.rtype(selection.Recv())
+	m, ok := t.MethodByName(sel.Sel.Name)
+	if !ok || !m.Func.IsValid() {
+		panic(&UnsupportedError{sel.Pos(), "method expression is not supported"})
+	}
+	return m.Func
+}
+
+// method returns the method value selected by sel.
+func (fr *frame) method(sel *ast.SelectorExpr, selection *types.Selection) reflect.Value {
+	x := fr.eval(sel.X)
+	index := selection.Index()
+	for _, i := range index[:len(index)-1] {
+		x = field(deref(x), i)
+	}
+
+	name := sel.Sel.Name
+	if x.Kind() == reflect.Interface && x.IsNil() {
+		nilDereference()
+	}
+	if m, ok := exitMethod(x, name); ok {
+		return m
+	}
+	if m := x.MethodByName(name); m.IsValid() {
+		return m
+	}
+	if x.Kind() == reflect.Ptr {
+		return deref(x).MethodByName(name)
+	}
+	if !x.CanAddr() {
+		p := reflect.New(x.Type())
+		p.Elem().Set(x)
+		x = p.Elem()
+	}
+	return x.Addr().MethodByName(name)
+}
+
+// field returns the field i of the struct x, which can be set if x is addressable,
+// and used as any value if it is unexported.
+func field(x reflect.Value, i int) reflect.Value {
+	f := x.Field(i)
+	if f.CanInterface() {
+		return f
+	}
+
+	if !x.CanAddr() {
+		p := reflect.New(x.Type())
+		p.Elem().Set(x)
+		x = p.Elem()
+		f = x.Field(i)
+	}
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
+
+// deref returns the value x points to, or x if it is no pointer.
+func deref(x reflect.Value) reflect.Value {
+	if x.Kind() != reflect.Ptr {
+		return x
+	}
+	if x.IsNil() {
+		nilDereference()
+	}
+	return x.Elem()
+}
+
+func (fr *frame) index(expr *ast.IndexExpr) reflect.Value {
+	x := fr.eval(expr.X)
+	if x.Kind() == reflect.Ptr {
+		x = deref(x)
+	}
+
+	if x.Kind() == reflect.Map {
+		v := x.MapIndex(converted(fr.eval(expr.Index), x.Type().Key()))
+		if !v.IsValid() {
+			return reflect.Zero(x.Type().Elem())
+		}
+		return v
+	}
+
+	i := toInt(fr.eval(expr.Index))
+	checkIndex(i, x.Len())
+	return x.Index(i)
+}
+
+func (fr *frame) slice(expr *ast.SliceExpr) reflect.Value {
+	x := fr.eval(expr.X)
+	if x.Kind() == reflect.Ptr {
+		x = deref(x)
+	}
+	if x.Kind() == reflect.Array && !x.CanAddr() {
+		p := reflect.New(x.Type())
+		p.Elem().Set(x)
+		x = p.Elem()
+	}
+
+	low, high, max := 0, x.Len(), -1
+	if x.Kind() != reflect.String {
+		max = x.Cap()
+	}
+	if expr.Low != nil {
+		low = toInt(fr.eval(expr.Low))
+	}
+	if expr.High != nil {
+		high = toInt(fr.eval(expr.High))
+	}
+	if expr.Slice3 {
+		max = toInt(fr.eval(expr.Max))
+	}
+
+	if x.Kind() == reflect.String {
+		checkSliceString(low, high, x.Len())
+		return x.Slice(low, high)
+	}
+	if expr.Slice3 {
+		checkSlice3(low, high, max, x.Cap())
+		return x.Slice3(low, high, max)
+	}
+	checkSlice(low, high, x.Cap())
+	return x.Slice(low, high)
+}
+
+func (fr *frame) unary(expr *ast.UnaryExpr, t types.Type) reflect.Value {
+	switch expr.Op {
+	case token.AND:
+		if lit, ok := unparen(expr.X).(*ast.CompositeLit); ok {
+			v := fr.eval(lit)
+			p := reflect.New(v.Type())
+			p.Elem().Set(v)
+			return p
+		}
+		x := fr.eval(expr.X)
+		if !x.CanAddr() {
+			panic(&UnsupportedError{expr.Pos(), "cannot take the address"})
+		}
+		return x.Addr()
+	case token.ARROW:
+		v, _ := fr.recv(fr.eval(expr.X))
+		return v
+	}
+
+	return unaryOp(expr.Op, fr.eval(expr.X), fr.in.rtype(t))
+}
+
+func (fr *frame) binary(expr *ast.BinaryExpr, t types.Type) reflect.Value {
+	rt := fr.in.rtype(t)
+	switch expr.Op {
+	case token.LAND:
+		if !fr.eval(expr.X).Bool() {
+			return reflect.Zero(rt)
+		}
+		return converted(fr.eval(expr.Y), rt)
+	case token.LOR:
+		if fr.eval(expr.X).Bool() {
+			return converted(reflect.ValueOf(true), rt)
+		}
+		return converted(fr.eval(expr.Y), rt)
+	}
+
+	x, y := fr.eval(expr.X), fr.eval(expr.Y)
+	if isComparison(expr.Op) {
+		return converted(reflect.ValueOf(compare(expr.Op, x, y)), rt)
+	}
+	return binaryOp(expr.Op, x, y, rt)
+}
+
+// recv receives from the channel ch, unless the statements are interrupted.
+func (fr *frame) recv(ch reflect.Value) (reflect.Value, bool) {
+	chosen, v, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(fr.in.done.Load().(chan struct{}))},
+	})
+	if chosen == 1 {
+		panic(interruption{})
+	}
+	return v, ok
+}
+
+// send sends v on the channel ch, unless the statements are interrupted.
+func (fr *frame) send(ch, v reflect.Value) {
+	chosen, _, _ := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: ch, Send: v},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(fr.in.done.Load().(chan struct{}))},
+	})
+	if chosen == 1 {
+		panic(interruption{})
+	}
+}
+
+// assertType returns x, an interface value, as a value of type t, and whether its
+// dynamic type is t or, for interfaces, implements it.
+func (fr *frame) assertType(x reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if !x.IsValid() || x.IsNil() {
+		return reflect.Zero(t), false
+	}
+
+	dynamic := x.Elem()
+	if t.Kind() == reflect.Interface {
+		if !dynamic.Type().Implements(t) {
+			return reflect.Zero(t), false
+		}
+		return converted(dynamic, t), true
+	}
+	if dynamic.Type() != t {
+		return reflect.Zero(t), false
+	}
+	return dynamic, true
+}
+
+// typeAssertionError is the value of the panic of a failed type assertion.
+type typeAssertionError struct {
+	x reflect.Value
+	t reflect.Type
+}
+
+func (e *typeAssertionError) RuntimeError() {}
+
+func (e *typeAssertionError) Error() string {
+	if !e.x.IsValid() || e.x.IsNil() {
+		return "interface conversion: interface is nil, not " + e.t.String()
+	}
+	if e.t.Kind() == reflect.Interface {
+		return "interface conversion: " + e.x.Elem().Type().String() + " is not " + e.t.String() + ": missing method"
+	}
+	return "interface conversion: interface {} is " + e.x.Elem().Type().String() + ", not " + e.t.String()
+}
+
+// compositeLit returns the value of lit, of type t.
+func (fr *frame) compositeLit(lit *ast.CompositeLit, t reflect.Type) reflect.Value {
+	if t.Kind() == reflect.Ptr {
+		// an element &T{...} written as {...}, whose type is recorded as *T
+		p := reflect.New(t.Elem())
+		p.Elem().Set(fr.compositeLit(lit, t.Elem()))
+		return p
+	}
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Struct:
+		for i, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				f, _ := t.FieldByName(kv.Key.(*ast.Ident).Name)
+				setValue(field(v, f.Index[0]), converted(fr.eval(kv.Value), f.Type))
+				continue
+			}
+			setValue(field(v, i), converted(fr.eval(elt), t.Field(i).Type))
+		}
+	case reflect.Array, reflect.Slice:
+		// the elements are set at increasing indices unless given
+		indices := make([]int, len(lit.Elts))
+		n, i := 0, 0
+		for j, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				i = toInt(fr.eval(kv.Key))
+			}
+			indices[j] = i
+			i++
+			if i > n {
+				n = i
+			}
+		}
+		if t.Kind() == reflect.Slice {
+			v = reflect.MakeSlice(t, n, n)
+		}
+		for j, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			setValue(v.Index(indices[j]), converted(fr.eval(elt), t.Elem()))
+		}
+	case reflect.Map:
+		v = reflect.MakeMapWithSize(t, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			kv := elt.(*ast.KeyValueExpr)
+			v.SetMapIndex(converted(fr.eval(kv.Key), t.Key()), converted(fr.eval(kv.Value), t.Elem()))
+		}
+	}
+	return v
+}
+
+// callExpr returns the results of call, which may be a conversion or a call of a
+// builtin function.
+func (fr *frame) callExpr(call *ast.CallExpr) []reflect.Value {
+	if tv := fr.code.info.Types[call.Fun]; tv.IsType() {
+		t := fr.in.rtype(tv.Type)
+		return []reflect.Value{convert(fr.eval(call.Args[0]), t)}
+	}
+
+	if id, ok := unparen(call.Fun).(*ast.Ident); ok {
+		if b, ok := fr.code.info.Uses[id].(*types.Builtin); ok {
+			var args []reflect.Value
+			if b.Name() != "make" && b.Name() != "new" {
+				args = fr.args(call, nil)
+			}
+			v := fr.builtin(b.Name(), call, args)
+			if !v.IsValid() {
+				return nil
+			}
+			return []reflect.Value{v}
+		}
+	}
+
+	fn := fr.eval(call.Fun)
+	if !fn.IsValid() || fn.IsNil() {
+		nilDereference()
+	}
+	return callFunc(fn, fr.args(call, fn.Type()), call.Ellipsis.IsValid())
+}
+
+// args evaluates the arguments of call, converted to the parameters of the
+// function type ftype if it is not nil.
+func (fr *frame) args(call *ast.CallExpr, ftype reflect.Type) []reflect.Value {
+	var args []reflect.Value
+	if len(call.Args) == 1 {
+		if _, ok := fr.code.info.Types[call.Args[0]].Type.(*types.Tuple); ok {
+			args = fr.evalMulti(call.Args[0])
+		}
+	}
+	if args == nil {
+		for _, arg := range call.Args {
+			v := fr.eval(arg)
+			if v.IsValid() && v.CanAddr() {
+				// the argument is a copy of the variable
+				copied := reflect.New(v.Type()).Elem()
+				copied.Set(v)
+				v = copied
+			}
+			args = append(args, v)
+		}
+	}
+
+	if ftype == nil {
+		return args
+	}
+	for i := range args {
+		var pt reflect.Type
+		switch {
+		case ftype.IsVariadic() && i >= ftype.NumIn()-1:
+			pt = ftype.In(ftype.NumIn() - 1)
+			if !call.Ellipsis.IsValid() {
+				pt = pt.Elem()
+			}
+		default:
+			pt = ftype.In(i)
+		}
+		args[i] = converted(args[i], pt)
+	}
+	return args
+}
+
+// callFunc calls fn with args, the last of which holds the variadic arguments if
+// ellipsis is set.
+func callFunc(fn reflect.Value, args []reflect.Value, ellipsis bool) []reflect.Value {
+	if ellipsis {
+		return fn.CallSlice(args)
+	}
+	return fn.Call(args)
+}
+
+// lvalue is the operand of an assignment.
+type lvalue struct {
+	v reflect.Value // the variable, unless it is an entry of a map or blank
+	// m and key are the map and key of an entry
+	m, key reflect.Value
+	t      reflect.Type
+}
+
+func (fr *frame) lvalue(expr ast.Expr) lvalue {
+	expr = unparen(expr)
+	if ident, ok := expr.(*ast.Ident); ok && ident.Name == "_" {
+		return lvalue{}
+	}
+
+	if index, ok := expr.(*ast.IndexExpr); ok {
+		if m := fr.eval(index.X); m.Kind() == reflect.Map {
+			key := converted(fr.eval(index.Index), m.Type().Key())
+			return lvalue{m: m, key: key, t: m.Type().Elem()}
+		}
+	}
+
+	v := fr.eval(expr)
+	if !v.CanSet() {
+		panic(&UnsupportedError{expr.Pos(), "cannot assign"})
+	}
+	return lvalue{v: v, t: v.Type()}
+}
+
+func (lv lvalue) typ() reflect.Type {
+	return lv.t
+}
+
+func (lv lvalue) get() reflect.Value {
+	if lv.m.IsValid() {
+		v := lv.m.MapIndex(lv.key)
+		if !v.IsValid() {
+			return reflect.Zero(lv.t)
+		}
+		return v
+	}
+	return lv.v
+}
+
+func (lv lvalue) set(v reflect.Value) {
+	switch {
+	case lv.m.IsValid():
+		lv.m.SetMapIndex(lv.key, converted(v, lv.t))
+	case lv.v.IsValid():
+		setValue(lv.v, v)
+	}
+}
@@ -0,0 +1,144 @@
+// +build ignore
+
+// gen.go writes symbols.go, the table of the packages available to the
+// interpreter. Run it with go generate after changing the list of packages.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+)
+
+// packages are the packages made available, by import path.
+var packages = []string{
+	"bufio",
+	"bytes",
+	"container/heap",
+	"container/list",
+	"context",
+	"crypto/md5",
+	"crypto/sha1",
+	"crypto/sha256",
+	"encoding/base64",
+	"encoding/csv",
+	"encoding/hex",
+	"encoding/json",
+	"errors",
+	"fmt",
+	"hash",
+	"hash/crc32",
+	"html",
+	"image",
+	"image/color",
+	"image/draw",
+	"image/png",
+	"io",
+	"io/fs",
+	"io/ioutil",
+	"log",
+	"math",
+	"math/big",
+	"math/bits",
+	"math/cmplx",
+	"math/rand",
+	"net/url",
+	"os",
+	"path",
+	"path/filepath",
+	"regexp",
+	"sort",
+	"strconv",
+	"strings",
+	"sync",
+	"sync/atomic",
+	"text/tabwriter",
+	"text/template",
+	"time",
+	"unicode",
+	"unicode/utf16",
+	"unicode/utf8",
+
+	"github.com/fabian-z/gopherlab/display",
+	"github.com/fabian-z/gopherlab/ipc",
+}
+
+func main() {
+	imp := importer.ForCompiler(token.NewFileSet(), "source", nil)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go. DO NOT EDIT.\n\npackage interp\n\nimport (\n\t\"reflect\"\n\n")
+	for _, path := range packages {
+		fmt.Fprintf(&buf, "\t%s %q\n", alias(path), path)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// symbols holds the exported functions, variables and types of the packages\n")
+	buf.WriteString("// the interpreter can use, by package path and name. Functions are held as\n")
+	buf.WriteString("// they are, variables as pointers to them and types as nil pointers.\n")
+	buf.WriteString("var symbols = map[string]map[string]reflect.Value{\n")
+	for _, path := range packages {
+		pkg, err := imp.Import(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Fprintf(&buf, "\t%q: {\n", path)
+		names := pkg.Scope().Names()
+		sort.Strings(names)
+		for _, name := range names {
+			if expr := symbol(pkg.Scope().Lookup(name), alias(path)+"."+name); expr != "" {
+				fmt.Fprintf(&buf, "\t\t%q: %s,\n", name, expr)
+			}
+		}
+		buf.WriteString("\t},\n")
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("symbols.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// alias returns the name under which the package path is imported.
+func alias(path string) string {
+	return strings.NewReplacer("/", "_", ".", "_", "-", "_").Replace(path)
+}
+
+// symbol returns the entry of the table for obj, which is qualified, or "" if
+// the interpreter cannot use it.
+func symbol(obj types.Object, qualified string) string {
+	if !obj.Exported() {
+		return ""
+	}
+
+	switch obj := obj.(type) {
+	case *types.Func:
+		if obj.Type().(*types.Signature).TypeParams().Len() > 0 {
+			return ""
+		}
+		return fmt.Sprintf("reflect.ValueOf(%s)", qualified)
+	case *types.Var:
+		return fmt.Sprintf("reflect.ValueOf(&%s)", qualified)
+	case *types.TypeName:
+		if named, ok := types.Unalias(obj.Type()).(*types.Named); ok && named.TypeParams().Len() > 0 {
+			return ""
+		}
+		if alias, ok := obj.Type().(*types.Alias); ok && alias.TypeArgs().Len() > 0 {
+			return ""
+		}
+		return fmt.Sprintf("reflect.ValueOf((*%s)(nil))", qualified)
+	}
+	return ""
+}
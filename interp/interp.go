@@ -0,0 +1,353 @@
+// Package interp interprets the statements of the main function of a Go program
+// in the calling process, as the gopherlab kernel does for the cells of a session
+// it runs without compiling them. The variables the statements declare are kept,
+// so that statements run later can use them.
+//
+// The statements are type checked by the caller; constants, types and the fields
+// and methods selected are taken from the types.Info of the check. Values are held
+// as reflect.Values, and the packages listed in gen.go are used by reflection.
+//
+// Not supported are: packages not listed there, generic functions and types,
+// functions, methods and types declared outside main, interface types with methods
+// declared in the program, and goto. Types declared in main are represented by
+// their underlying types. Run returns an *UnsupportedError for statements using
+// any of these, without running them.
+package interp
+
+//go:generate go run gen.go
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrInterrupted is returned by Run if it has been interrupted.
+var ErrInterrupted = errors.New("interrupted")
+
+// UnsupportedError is returned by Run for statements the interpreter cannot run.
+type UnsupportedError struct {
+	Pos token.Pos
+	Msg string
+}
+
+func (e *UnsupportedError) Error() string {
+	return e.Msg
+}
+
+// Panic is returned by Run if the statements panicked.
+type Panic struct {
+	Value interface{}
+	// Frames are the calls of interpreted functions on the stack of the panic,
+	// innermost first.
+	Frames []Frame
+}
+
+func (p *Panic) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+// Frame is the call of an interpreted function, at the statement it was running.
+type Frame struct {
+	Func string
+	Pos  token.Position
+}
+
+// ExitError is returned by Run if the statements called os.Exit or log.Fatal,
+// which do not exit the calling process.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// Interpreter runs statements of the main function of a program, one call to Run
+// after another.
+type Interpreter struct {
+	// Stderr receives the reports of panics of goroutines, started by statements
+	// run before, which happen when Run is not running. If nil, they are dropped.
+	Stderr io.Writer
+
+	// Log is the standard logger of the statements run, which the functions of
+	// package log use instead of the one of the process. It writes to os.Stderr
+	// unless its output is set.
+	Log *log.Logger
+
+	globals *scope // the variables of main, by name
+
+	definedMu sync.Mutex
+	defined   map[string]map[string]reflect.Value // by package path and name, see Define
+
+	typesMu sync.Mutex
+	types   map[types.Type]reflect.Type
+
+	runMu   sync.Mutex // guards running
+	running bool
+	done    atomic.Value // chan struct{}, closed to interrupt the statements running
+	failed  atomic.Value // chan error, receiving the panics of goroutines while Run runs
+	traceMu sync.Mutex
+	trace   *trace
+}
+
+// New returns an interpreter which has not run any statements yet.
+func New() *Interpreter {
+	in := &Interpreter{
+		Log:     log.New(os.Stderr, "", log.LstdFlags),
+		globals: newScope(nil),
+		defined: make(map[string]map[string]reflect.Value),
+		types:   make(map[types.Type]reflect.Type),
+	}
+	in.done.Store(make(chan struct{}))
+	in.failed.Store(make(chan error, 1))
+
+	in.Define("os", "Exit", exitFunc)
+	for name, fn := range logFuncs(in.Log) {
+		in.Define("log", name, fn)
+	}
+	return in
+}
+
+// Define makes the statements run from now on use v as the member name of the
+// package path, instead of the one of the package: a function, or a pointer to
+// a variable. Functions declared outside main, in the package "main", can be
+// provided likewise.
+func (in *Interpreter) Define(path, name string, v interface{}) {
+	in.definedMu.Lock()
+	defer in.definedMu.Unlock()
+
+	if in.defined[path] == nil {
+		in.defined[path] = make(map[string]reflect.Value)
+	}
+	in.defined[path][name] = reflect.ValueOf(v)
+}
+
+// Rename renames the variable name of main, declared by earlier calls to Run, to
+// newName, which the statements run from now on refer to it by. A variable newName
+// is replaced.
+func (in *Interpreter) Rename(name, newName string) {
+	in.globals.rename(name, newName)
+}
+
+// symbol returns the member name of the package path, as held by symbols, and
+// whether it is available.
+func (in *Interpreter) symbol(path, name string) (reflect.Value, bool) {
+	in.definedMu.Lock()
+	v, ok := in.defined[path][name]
+	in.definedMu.Unlock()
+	if ok {
+		return v, true
+	}
+
+	v, ok = symbols[path][name]
+	return v, ok
+}
+
+// code is the type checked code of a call to Run, which the functions defined by
+// it keep referring to.
+type code struct {
+	fset *token.FileSet
+	info *types.Info
+	// main is the scope of the main function, whose variables are the globals of
+	// the interpreter.
+	main      *types.Scope
+	funcNames map[*ast.FuncLit]string
+}
+
+// Run runs stmts, statements of the function main of a program checked with the
+// types recorded in info, which has to hold Types, Defs, Uses, Implicits, Scopes
+// and Selections. The variables of main declared by earlier calls are used by
+// name. Run returns a *Panic if the statements panicked, and ErrInterrupted right
+// away if Interrupt is called, leaving the statements running behind.
+func (in *Interpreter) Run(fset *token.FileSet, info *types.Info, main *ast.FuncDecl, stmts []ast.Stmt) error {
+	c := &code{
+		fset:      fset,
+		info:      info,
+		main:      info.Scopes[main.Type],
+		funcNames: make(map[*ast.FuncLit]string),
+	}
+	if c.main == nil {
+		return &UnsupportedError{main.Pos(), "the scope of main is not known"}
+	}
+
+	// function literals are named like those of the compiler
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(node ast.Node) bool {
+			if lit, ok := node.(*ast.FuncLit); ok {
+				c.funcNames[lit] = fmt.Sprintf("main.main.func%d", len(c.funcNames)+1)
+			}
+			return true
+		})
+	}
+
+	if err := in.check(c, stmts); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	failed := make(chan error, 1)
+	in.done.Store(done)
+	in.failed.Store(failed)
+	in.resetTrace()
+	in.setRunning(true)
+	defer in.setRunning(false)
+	defer in.failed.Store(make(chan error, 1))
+
+	result := make(chan error, 1)
+	go func() {
+		result <- in.runMain(c, stmts)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case err := <-failed:
+		return err
+	case <-done:
+		return ErrInterrupted
+	}
+}
+
+// runMain runs the statements of main.
+func (in *Interpreter) runMain(c *code, stmts []ast.Stmt) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = in.failure(r)
+		}
+	}()
+
+	fr := &frame{in: in, code: c, name: "main.main", scope: in.globals}
+	fr.run(stmts)
+	return nil
+}
+
+// failure returns the error for the value r of a panic ending the statements run.
+func (in *Interpreter) failure(r interface{}) error {
+	switch r := r.(type) {
+	case interruption:
+		return ErrInterrupted
+	case exit:
+		return &ExitError{Code: int(r)}
+	}
+	return &Panic{Value: r, Frames: in.takeTrace(r)}
+}
+
+func (in *Interpreter) setRunning(running bool) {
+	in.runMu.Lock()
+	defer in.runMu.Unlock()
+
+	in.running = running
+}
+
+// Interrupt stops the statements running, along with the goroutines they started,
+// and makes Run return ErrInterrupted. It reports whether Run was running.
+func (in *Interpreter) Interrupt() bool {
+	in.runMu.Lock()
+	defer in.runMu.Unlock()
+
+	if !in.running {
+		return false
+	}
+	done := in.done.Load().(chan struct{})
+	select {
+	case <-done:
+	default:
+		close(done)
+	}
+	return true
+}
+
+// interrupted panics with an interruption if the statements running are to stop.
+func (in *Interpreter) interrupted() {
+	select {
+	case <-in.done.Load().(chan struct{}):
+		panic(interruption{})
+	default:
+	}
+}
+
+// interruption is the value of the panic stopping the statements on Interrupt.
+type interruption struct{}
+
+// exit is the value of the panic stopping the statements on os.Exit, with the
+// status code.
+type exit int
+
+// goroutinePanic reports the panic of a goroutine started by the statements, which
+// ends the statements running, if any, as it would end the program.
+func (in *Interpreter) goroutinePanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if _, ok := r.(interruption); ok {
+		return
+	}
+
+	err := in.failure(r)
+	select {
+	case in.failed.Load().(chan error) <- err:
+	default:
+		if in.Stderr != nil {
+			fmt.Fprintf(in.Stderr, "%s [in a goroutine]\n", err)
+		}
+	}
+}
+
+// trace is the trace of the stack of a panic, as it unwinds the interpreted
+// functions.
+type trace struct {
+	value  interface{}
+	frames []Frame
+}
+
+func (in *Interpreter) resetTrace() {
+	in.traceMu.Lock()
+	defer in.traceMu.Unlock()
+
+	in.trace = nil
+}
+
+// tracePanic adds fr to the trace of the panic r unwinding it. The trace starts
+// over if r is not the value of the panic traced so far.
+func (in *Interpreter) tracePanic(fr *frame, r interface{}) {
+	in.traceMu.Lock()
+	defer in.traceMu.Unlock()
+
+	if in.trace == nil || !samePanic(in.trace.value, r) {
+		in.trace = &trace{value: r}
+	}
+	in.trace.frames = append(in.trace.frames, Frame{Func: fr.name, Pos: fr.code.fset.Position(fr.pos)})
+}
+
+// takeTrace returns the frames traced for the panic r, and forgets them.
+func (in *Interpreter) takeTrace(r interface{}) []Frame {
+	in.traceMu.Lock()
+	defer in.traceMu.Unlock()
+
+	t := in.trace
+	in.trace = nil
+	if t == nil || !samePanic(t.value, r) {
+		return nil
+	}
+	return t.frames
+}
+
+// samePanic reports whether a and b are the same panic value, as far as can be told.
+func samePanic(a, b interface{}) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = reflect.TypeOf(a) == reflect.TypeOf(b)
+		}
+	}()
+	return a == b
+}
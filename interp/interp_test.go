@@ -0,0 +1,316 @@
+package interp
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+// session runs cells like the kernel: the statements of all cells are checked as
+// the body of main, and those of the last cell are run.
+type session struct {
+	t     *testing.T
+	in    *Interpreter
+	out   bytes.Buffer
+	stmts []string
+}
+
+// header is the source preceding the statements of the cells.
+const header = `package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _, _, _, _ = errors.New, fmt.Println, math.Abs, os.Exit
+var _, _, _, _, _ = sort.Ints, strconv.Itoa, strings.ToUpper, sync.NewCond, time.Now
+
+func main() {
+`
+
+func newSession(t *testing.T) *session {
+	s := &session{t: t, in: New()}
+	s.in.Define("fmt", "Println", func(a ...interface{}) (int, error) {
+		return fmt.Fprintln(&s.out, a...)
+	})
+	return s
+}
+
+func (s *session) run(cell string) error {
+	n := len(s.stmts)
+	first := strings.Count(header, "\n") + 1 // the line of the first statement of the cell
+	for _, stmt := range s.stmts {
+		first += strings.Count(stmt, "\n") + 1
+	}
+	s.stmts = append(s.stmts, cell)
+	src := header + strings.Join(s.stmts, "\n") + "\n}\n"
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, 0)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Scopes:     make(map[ast.Node]*types.Scope),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	config := types.Config{Importer: importer.Default(), Error: func(error) {}}
+	if _, err := config.Check("main", fset, []*ast.File{f}, info); err != nil && !strings.Contains(err.Error(), "declared and not used") {
+		s.t.Fatal(err)
+	}
+
+	main := f.Decls[len(f.Decls)-1].(*ast.FuncDecl)
+	var stmts []ast.Stmt
+	for _, stmt := range main.Body.List {
+		if fset.Position(stmt.Pos()).Line >= first {
+			stmts = append(stmts, stmt)
+		}
+	}
+
+	s.out.Reset()
+	err = s.in.Run(fset, info, main, stmts)
+	if err != nil {
+		s.stmts = s.stmts[:n]
+	}
+	return err
+}
+
+func (s *session) expect(cell, out string) {
+	s.t.Helper()
+	if err := s.run(cell); err != nil {
+		s.t.Fatalf("%s: %s", cell, err)
+	}
+	if got := s.out.String(); got != out {
+		s.t.Errorf("%s: got %q, want %q", cell, got, out)
+	}
+}
+
+func TestRun_state(t *testing.T) {
+	s := newSession(t)
+
+	s.expect("x := 40", "")
+	s.expect("inc := func(d int) int { x += d; return x }", "")
+	s.expect("fmt.Println(inc(1), inc(1), x)", "41 42 42\n")
+	s.expect("x, y := x*2, 1.5", "")
+	s.expect("fmt.Println(x, y, x/3, -x%5, uint8(x)+200)", "84 1.5 28 -4 28\n")
+	s.expect(`m := map[string][]int{"a": {1, 2}}; m["b"] = append(m["b"], 3)`, "")
+	s.expect(`fmt.Println(len(m), m["a"][1:], m["b"], strings.ToUpper("ok"))`, "2 [2] [3] OK\n")
+}
+
+func TestInterpreter_Rename(t *testing.T) {
+	s := newSession(t)
+
+	s.expect("x := 1", "")
+	s.in.Rename("x", "old")
+	s.stmts[0] = "old := 1"
+	s.expect(`x := "s"; fmt.Println(x, old)`, "s 1\n")
+}
+
+func TestRun_statements(t *testing.T) {
+	s := newSession(t)
+
+	s.expect(`type point struct{ X, y int }
+ps := []*point{{1, 2}, {X: 3}}
+var funcs []func() int
+for i, p := range ps {
+	p.y += 10
+	funcs = append(funcs, func() int { return i*100 + p.X + p.y })
+}
+for _, f := range funcs {
+	fmt.Println(f())
+}`, "13\n113\n")
+
+	s.expect(`var sum int
+outer:
+for i := 0; ; i++ {
+	switch {
+	case i%2 == 0:
+		continue
+	case i > 7:
+		break outer
+	}
+	sum += i
+}
+fmt.Println(sum)`, "16\n")
+
+	s.expect(`var v interface{} = ps[0]
+switch v := v.(type) {
+case int, string:
+	fmt.Println("no")
+case *point:
+	fmt.Println(v.y)
+}
+_, ok := v.(error)
+fmt.Println(ok)`, "12\nfalse\n")
+
+	s.expect(`type T struct {
+	a int
+	B string
+}
+arr := [3]T{{1, "x"}, 2: {B: "y"}}
+sl := append(arr[:1], T{a: 2})
+fmt.Println(arr, sl, cap(sl), &arr[2], arr[1] == T{2, ""})`, "[{1 x} {2 } {0 y}] [{1 x} {2 }] 3 &{0 y} true\n")
+
+	s.expect(`div := func(a, b int) (q, r int) {
+	defer func() { q *= 10 }()
+	return a / b, a % b
+}
+for i, r := range "hé" {
+	fmt.Println(i, string(r))
+}
+switch q, _ := div(7, 2); q {
+case 30:
+	fmt.Println(q)
+	fallthrough
+case 3:
+	fmt.Println(3)
+}`, "0 h\n1 é\n30\n3\n")
+}
+
+func TestRun_packages(t *testing.T) {
+	s := newSession(t)
+
+	s.expect(`var b strings.Builder
+b.WriteString("hi")
+write := b.WriteString
+write("!")
+fmt.Println(b.String(), b.Len())`, "hi! 3\n")
+
+	s.expect(`xs := []int{3, 1, 2}
+sort.Slice(xs, func(i, j int) bool { return xs[i] < xs[j] })
+d := 1500 * time.Millisecond
+var str fmt.Stringer = d
+fmt.Println(xs, d.Seconds(), str.String(), strconv.Itoa(12)+"!", math.Sqrt(16))`, "[1 2 3] 1.5 1.5s 12! 4\n")
+
+	s.expect(`e := errors.New("boom")
+w := fmt.Errorf("wrap: %w", e)
+fmt.Println(w, errors.Is(w, e), errors.Unwrap(w) == e)`, "wrap: boom true true\n")
+
+	s.expect(`var wg sync.WaitGroup
+var mu sync.Mutex
+n := 0
+for i := 0; i < 10; i++ {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		mu.Lock()
+		n += i
+		mu.Unlock()
+	}()
+}
+wg.Wait()
+fmt.Println(n)`, "45\n")
+}
+
+func TestRun_goroutines(t *testing.T) {
+	s := newSession(t)
+
+	s.expect(`ch := make(chan int)
+done := make(chan struct{})
+go func() {
+	defer close(done)
+	for v := range ch {
+		fmt.Println(v)
+	}
+}()
+for i := 0; i < 3; i++ {
+	ch <- i
+}
+close(ch)
+<-done`, "0\n1\n2\n")
+
+	s.expect(`select {
+case v, ok := <-ch:
+	fmt.Println(v, ok)
+default:
+	fmt.Println("blocked")
+}`, "0 false\n")
+}
+
+func TestRun_panic(t *testing.T) {
+	s := newSession(t)
+
+	s.expect(`f := func() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered: %v", r)
+		}
+	}()
+	var a []int
+	_ = a[3]
+	return nil
+}
+fmt.Println(f())`, "recovered: runtime error: index out of range [3] with length 0\n")
+
+	err := s.run(`var p *struct{ X int }
+g := func() int { return p.X }
+fmt.Println("before")
+g()`)
+	pe, ok := err.(*Panic)
+	if !ok {
+		t.Fatalf("should panic: %v", err)
+	}
+	if !strings.Contains(fmt.Sprint(pe.Value), "nil pointer dereference") {
+		t.Errorf("got panic value %v", pe.Value)
+	}
+	if len(pe.Frames) != 2 || pe.Frames[0].Func != "main.main.func1" || pe.Frames[1].Func != "main.main" || pe.Frames[1].Pos.Line != pe.Frames[0].Pos.Line+2 {
+		t.Errorf("got frames %+v", pe.Frames)
+	}
+	if s.out.String() != "before\n" {
+		t.Errorf("got output %q", s.out.String())
+	}
+
+	if err := s.run(`os.Exit(3)`); err == nil || err.Error() != "exit status 3" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestRun_interrupt(t *testing.T) {
+	s := newSession(t)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		s.in.Interrupt()
+	}()
+	if err := s.run(`for {}`); err != ErrInterrupted {
+		t.Errorf("got %v", err)
+	}
+	s.expect(`fmt.Println("still running")`, "still running\n")
+}
+
+func TestRun_unsupported(t *testing.T) {
+	s := newSession(t)
+
+	for _, cell := range []string{
+		`var b strings.Builder; b.WriteString("x")`,
+		`goto end; end:`,
+	} {
+		err := s.run(cell)
+		if cell[0] == 'g' {
+			if _, ok := err.(*UnsupportedError); !ok {
+				t.Errorf("%s: should not be supported: %v", cell, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: %v", cell, err)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package interp
+
+import (
+	"go/constant"
+	"go/token"
+	"math"
+	"reflect"
+)
+
+// constValue returns the constant val as a value of type t.
+func constValue(val constant.Value, t reflect.Type) reflect.Value {
+	if t.Kind() == reflect.Interface {
+		// untyped constants are passed to interfaces with their default types
+		switch val.Kind() {
+		case constant.Bool:
+			t = reflect.TypeOf(false)
+		case constant.String:
+			t = reflect.TypeOf("")
+		case constant.Int:
+			t = reflect.TypeOf(0)
+		case constant.Float:
+			t = reflect.TypeOf(0.0)
+		case constant.Complex:
+			t = reflect.TypeOf(0i)
+		}
+	}
+
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(constant.BoolVal(val))
+	case reflect.String:
+		v.SetString(constant.StringVal(val))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, _ := constant.Int64Val(constant.ToInt(val))
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, _ := constant.Uint64Val(constant.ToInt(val))
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, _ := constant.Float64Val(constant.ToFloat(val))
+		v.SetFloat(f)
+	case reflect.Complex64, reflect.Complex128:
+		c := constant.ToComplex(val)
+		re, _ := constant.Float64Val(constant.Real(c))
+		im, _ := constant.Float64Val(constant.Imag(c))
+		v.SetComplex(complex(re, im))
+	}
+	return v
+}
+
+// setValue sets the variable dst to v, converting it to the type of dst. The zero
+// Value sets it to nil.
+func setValue(dst, v reflect.Value) {
+	dst.Set(converted(v, dst.Type()))
+}
+
+// converted returns v as a value of type t to which it is assignable, or which
+// represents the same type of the program; the zero Value is nil of type t.
+func converted(v reflect.Value, t reflect.Type) reflect.Value {
+	switch {
+	case !v.IsValid():
+		return reflect.Zero(t)
+	case v.Type() == t:
+		return v
+	case v.Type().AssignableTo(t):
+		c := reflect.New(t).Elem()
+		c.Set(v)
+		return c
+	}
+	return v.Convert(t)
+}
+
+// convert returns v converted to t, as by a conversion of the program.
+func convert(v reflect.Value, t reflect.Type) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Zero(t)
+	}
+	if t.Kind() == reflect.Interface {
+		return converted(v, t)
+	}
+	return v.Convert(t)
+}
+
+func isComparison(op token.Token) bool {
+	switch op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return true
+	}
+	return false
+}
+
+// compare returns the result of the comparison op of x and y.
+func compare(op token.Token, x, y reflect.Value) bool {
+	switch op {
+	case token.EQL:
+		return equal(x, y)
+	case token.NEQ:
+		return !equal(x, y)
+	}
+
+	var less, eq bool
+	switch x.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		a, b := x.Int(), y.Int()
+		less, eq = a < b, a == b
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		a, b := x.Uint(), y.Uint()
+		less, eq = a < b, a == b
+	case reflect.Float32, reflect.Float64:
+		a, b := x.Float(), y.Float()
+		less, eq = a < b, a == b
+	case reflect.String:
+		a, b := x.String(), y.String()
+		less, eq = a < b, a == b
+	}
+
+	switch op {
+	case token.LSS:
+		return less
+	case token.LEQ:
+		return less || eq
+	case token.GTR:
+		return !less && !eq && !isNaN(x, y)
+	}
+	return !less && !isNaN(x, y)
+}
+
+// isNaN reports whether x or y is a float which is not a number.
+func isNaN(x, y reflect.Value) bool {
+	if x.Kind() != reflect.Float32 && x.Kind() != reflect.Float64 {
+		return false
+	}
+	return math.IsNaN(x.Float()) || math.IsNaN(y.Float())
+}
+
+// equal reports whether x and y are equal, either of which may be nil.
+func equal(x, y reflect.Value) bool {
+	switch {
+	case !x.IsValid() && !y.IsValid():
+		return true
+	case !x.IsValid():
+		return y.IsNil()
+	case !y.IsValid():
+		return x.IsNil()
+	}
+
+	if x.Kind() == reflect.Interface || y.Kind() == reflect.Interface {
+		// compared as the runtime does, panicking for uncomparable dynamic types
+		return x.Interface() == y.Interface()
+	}
+	if x.Type() != y.Type() {
+		y = y.Convert(x.Type())
+	}
+	return x.Equal(y)
+}
+
+// unaryOp returns the result of the unary operator op applied to x, of type t.
+func unaryOp(op token.Token, x reflect.Value, t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+	switch op {
+	case token.ADD:
+		v.Set(converted(x, t))
+	case token.NOT:
+		v.SetBool(!x.Bool())
+	case token.SUB:
+		switch x.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v.SetInt(-x.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			v.SetUint(-x.Uint())
+		case reflect.Float32, reflect.Float64:
+			v.SetFloat(-x.Float())
+		case reflect.Complex64, reflect.Complex128:
+			v.SetComplex(-x.Complex())
+		}
+	case token.XOR:
+		switch x.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v.SetInt(^x.Int())
+		default:
+			v.SetUint(^x.Uint())
+		}
+	}
+	return v
+}
+
+// binaryOp returns the result of the arithmetic operator op applied to x and y,
+// of type t. Integers wrap around as the type of the result is set.
+func binaryOp(op token.Token, x, y reflect.Value, t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+
+	switch x.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		a := x.Int()
+		if op == token.SHL || op == token.SHR {
+			v.SetInt(shiftInt(op, a, y))
+			break
+		}
+		b := y.Int()
+		switch op {
+		case token.ADD:
+			v.SetInt(a + b)
+		case token.SUB:
+			v.SetInt(a - b)
+		case token.MUL:
+			v.SetInt(a * b)
+		case token.QUO:
+			v.SetInt(a / b)
+		case token.REM:
+			v.SetInt(a % b)
+		case token.AND:
+			v.SetInt(a & b)
+		case token.OR:
+			v.SetInt(a | b)
+		case token.XOR:
+			v.SetInt(a ^ b)
+		case token.AND_NOT:
+			v.SetInt(a &^ b)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		a := x.Uint()
+		if op == token.SHL || op == token.SHR {
+			v.SetUint(shiftUint(op, a, y))
+			break
+		}
+		b := y.Uint()
+		switch op {
+		case token.ADD:
+			v.SetUint(a + b)
+		case token.SUB:
+			v.SetUint(a - b)
+		case token.MUL:
+			v.SetUint(a * b)
+		case token.QUO:
+			v.SetUint(a / b)
+		case token.REM:
+			v.SetUint(a % b)
+		case token.AND:
+			v.SetUint(a & b)
+		case token.OR:
+			v.SetUint(a | b)
+		case token.XOR:
+			v.SetUint(a ^ b)
+		case token.AND_NOT:
+			v.SetUint(a &^ b)
+		}
+	case reflect.Float32, reflect.Float64:
+		a, b := x.Float(), y.Float()
+		switch op {
+		case token.ADD:
+			v.SetFloat(a + b)
+		case token.SUB:
+			v.SetFloat(a - b)
+		case token.MUL:
+			v.SetFloat(a * b)
+		case token.QUO:
+			v.SetFloat(a / b)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		a, b := x.Complex(), y.Complex()
+		switch op {
+		case token.ADD:
+			v.SetComplex(a + b)
+		case token.SUB:
+			v.SetComplex(a - b)
+		case token.MUL:
+			v.SetComplex(a * b)
+		case token.QUO:
+			v.SetComplex(a / b)
+		}
+	case reflect.String:
+		v.SetString(x.String() + y.String())
+	}
+
+	return v
+}
+
+// shiftInt shifts a by the count y, panicking as the runtime does if negative.
+func shiftInt(op token.Token, a int64, y reflect.Value) int64 {
+	if y.Kind() >= reflect.Int && y.Kind() <= reflect.Int64 {
+		if op == token.SHL {
+			return a << y.Int()
+		}
+		return a >> y.Int()
+	}
+	if op == token.SHL {
+		return a << y.Uint()
+	}
+	return a >> y.Uint()
+}
+
+func shiftUint(op token.Token, a uint64, y reflect.Value) uint64 {
+	if y.Kind() >= reflect.Int && y.Kind() <= reflect.Int64 {
+		if op == token.SHL {
+			return a << y.Int()
+		}
+		return a >> y.Int()
+	}
+	if op == token.SHL {
+		return a << y.Uint()
+	}
+	return a >> y.Uint()
+}
+
+// toInt returns the integer v as an int.
+func toInt(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int())
+	case reflect.Float32, reflect.Float64:
+		return int(v.Float())
+	}
+	return int(v.Uint())
+}
+
+// The following functions panic with the errors of the runtime, by doing what
+// fails in the same way, if the operation checked would fail.
+
+func checkIndex(i, n int) {
+	if i < 0 || i >= n {
+		_ = make([]struct{}, n)[i]
+	}
+}
+
+func checkSlice(low, high, max int) {
+	if low < 0 || high < low || high > max {
+		_ = make([]struct{}, max)[low:high]
+	}
+}
+
+func checkSlice3(low, high, max, capacity int) {
+	if low < 0 || high < low || max < high || max > capacity {
+		_ = make([]struct{}, capacity)[low:high:max]
+	}
+}
+
+func checkSliceString(low, high, n int) {
+	if low < 0 || high < low || high > n {
+		_ = string(make([]byte, n))[low:high]
+	}
+}
+
+func nilDereference() {
+	var p *struct{ x int }
+	_ = p.x
+}
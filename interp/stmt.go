@@ -0,0 +1,790 @@
+package interp
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sync"
+)
+
+// scope holds the variables of a block. Those of main are held by name, as main is
+// checked anew for every call to Run, and others by their objects.
+type scope struct {
+	mu     sync.RWMutex
+	vars   map[interface{}]reflect.Value
+	parent *scope
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{vars: make(map[interface{}]reflect.Value), parent: parent}
+}
+
+func (s *scope) lookup(key interface{}) (reflect.Value, bool) {
+	for ; s != nil; s = s.parent {
+		s.mu.RLock()
+		v, ok := s.vars[key]
+		s.mu.RUnlock()
+		if ok {
+			return v, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func (s *scope) define(key interface{}, v reflect.Value) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.vars[key] = v
+}
+
+// rename moves the variable key, if any, to newKey.
+func (s *scope) rename(key, newKey interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if v, ok := s.vars[key]; ok {
+		s.vars[newKey] = v
+		delete(s.vars, key)
+	}
+}
+
+// frame is the call of an interpreted function, or of main for the statements
+// passed to Run.
+type frame struct {
+	in   *Interpreter
+	code *code
+	name string
+
+	scope   *scope
+	results []reflect.Value // the result variables
+	pos     token.Pos       // the statement running
+
+	defers     []func()
+	panicking  bool
+	panicValue interface{}
+	recovered  bool
+	// deferring is the frame whose deferred call this one is, if it is a function
+	// literal deferred directly, in which recover stops the panic of deferring.
+	deferring *frame
+}
+
+// branch is how the statements of a block are left.
+type branch int
+
+const (
+	normal branch = iota
+	breakBranch
+	continueBranch
+	returnBranch
+	fallthroughBranch
+)
+
+// key returns the key of the variable obj in the scopes.
+func (fr *frame) key(obj types.Object) interface{} {
+	if obj.Parent() == fr.code.main {
+		return obj.Name()
+	}
+	return obj
+}
+
+// declare declares the variable ident, of the type given by its object, set to v.
+func (fr *frame) declare(ident *ast.Ident, v reflect.Value) {
+	obj := fr.code.info.Defs[ident]
+	if obj == nil {
+		obj = fr.code.info.Implicits[ident]
+	}
+	if obj == nil || obj.Name() == "_" {
+		return
+	}
+	fr.declareObj(obj, v)
+}
+
+func (fr *frame) declareObj(obj types.Object, v reflect.Value) reflect.Value {
+	variable := reflect.New(fr.in.rtype(obj.Type())).Elem()
+	setValue(variable, v)
+	fr.scope.define(fr.key(obj), variable)
+	return variable
+}
+
+// variable returns the variable obj.
+func (fr *frame) variable(obj types.Object) reflect.Value {
+	v, ok := fr.scope.lookup(fr.key(obj))
+	if !ok {
+		panic(&UnsupportedError{obj.Pos(), "variable " + obj.Name() + " has not been declared"})
+	}
+	return v
+}
+
+// run runs the body of the function of fr, followed by the deferred calls.
+func (fr *frame) run(stmts []ast.Stmt) {
+	defer fr.runDefers()
+	fr.execList(stmts)
+}
+
+// runDefers runs the deferred calls of fr when it returns or panics, and panics
+// on unless one of them recovers.
+func (fr *frame) runDefers() {
+	if r := recover(); r != nil {
+		switch r.(type) {
+		case interruption, exit:
+			panic(r)
+		}
+		fr.in.tracePanic(fr, r)
+		fr.panicking, fr.panicValue = true, r
+	}
+
+	for len(fr.defers) > 0 {
+		call := fr.defers[len(fr.defers)-1]
+		fr.defers = fr.defers[:len(fr.defers)-1]
+		fr.runDeferred(call)
+	}
+
+	if fr.panicking && !fr.recovered {
+		panic(fr.panicValue)
+	}
+}
+
+// runDeferred runs the deferred call; a panic replaces the one fr panics with.
+func (fr *frame) runDeferred(call func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r.(type) {
+			case interruption, exit:
+				panic(r)
+			}
+			fr.panicking, fr.panicValue, fr.recovered = true, r, false
+		}
+	}()
+	call()
+}
+
+func (fr *frame) execList(stmts []ast.Stmt) (branch, string) {
+	for _, stmt := range stmts {
+		if b, label := fr.exec(stmt); b != normal {
+			return b, label
+		}
+	}
+	return normal, ""
+}
+
+// execBlock runs stmts in a scope of their own.
+func (fr *frame) execBlock(stmts []ast.Stmt) (branch, string) {
+	outer := fr.scope
+	fr.scope = newScope(outer)
+	b, label := fr.execList(stmts)
+	fr.scope = outer
+	return b, label
+}
+
+// exec runs stmt, and returns how it leaves the enclosing statements, along with
+// the label of a break or continue.
+func (fr *frame) exec(stmt ast.Stmt) (branch, string) {
+	fr.in.interrupted()
+	fr.pos = stmt.Pos()
+
+	switch stmt := stmt.(type) {
+	case *ast.EmptyStmt, nil:
+	case *ast.ExprStmt:
+		fr.evalMulti(stmt.X)
+	case *ast.AssignStmt:
+		fr.assign(stmt)
+	case *ast.IncDecStmt:
+		lv := fr.lvalue(stmt.X)
+		one := reflect.ValueOf(1).Convert(lv.typ())
+		op := token.ADD
+		if stmt.Tok == token.DEC {
+			op = token.SUB
+		}
+		lv.set(binaryOp(op, lv.get(), one, lv.typ()))
+	case *ast.DeclStmt:
+		fr.declStmt(stmt.Decl.(*ast.GenDecl))
+	case *ast.BlockStmt:
+		return fr.execBlock(stmt.List)
+	case *ast.IfStmt:
+		return fr.ifStmt(stmt)
+	case *ast.ForStmt:
+		return fr.forStmt(stmt, "")
+	case *ast.RangeStmt:
+		return fr.rangeStmt(stmt, "")
+	case *ast.SwitchStmt:
+		return fr.switchStmt(stmt, "")
+	case *ast.TypeSwitchStmt:
+		return fr.typeSwitchStmt(stmt, "")
+	case *ast.SelectStmt:
+		return fr.selectStmt(stmt, "")
+	case *ast.LabeledStmt:
+		return fr.labeledStmt(stmt)
+	case *ast.BranchStmt:
+		label := ""
+		if stmt.Label != nil {
+			label = stmt.Label.Name
+		}
+		switch stmt.Tok {
+		case token.BREAK:
+			return breakBranch, label
+		case token.CONTINUE:
+			return continueBranch, label
+		case token.FALLTHROUGH:
+			return fallthroughBranch, ""
+		}
+		panic(&UnsupportedError{stmt.Pos(), stmt.Tok.String() + " is not supported"})
+	case *ast.ReturnStmt:
+		fr.returnStmt(stmt)
+		return returnBranch, ""
+	case *ast.GoStmt:
+		call := fr.deferredCall(stmt.Call)
+		in := fr.in
+		go func() {
+			defer in.goroutinePanic()
+			call()
+		}()
+	case *ast.DeferStmt:
+		fr.defers = append(fr.defers, fr.deferredCall(stmt.Call))
+	case *ast.SendStmt:
+		ch := fr.eval(stmt.Chan)
+		fr.send(ch, converted(fr.eval(stmt.Value), ch.Type().Elem()))
+	default:
+		panic(&UnsupportedError{stmt.Pos(), "statement is not supported"})
+	}
+
+	return normal, ""
+}
+
+func (fr *frame) labeledStmt(stmt *ast.LabeledStmt) (branch, string) {
+	label := stmt.Label.Name
+
+	var b branch
+	var to string
+	switch s := stmt.Stmt.(type) {
+	case *ast.ForStmt:
+		b, to = fr.forStmt(s, label)
+	case *ast.RangeStmt:
+		b, to = fr.rangeStmt(s, label)
+	case *ast.SwitchStmt:
+		b, to = fr.switchStmt(s, label)
+	case *ast.TypeSwitchStmt:
+		b, to = fr.typeSwitchStmt(s, label)
+	case *ast.SelectStmt:
+		b, to = fr.selectStmt(s, label)
+	default:
+		b, to = fr.exec(s)
+	}
+
+	if b == breakBranch && to == label {
+		return normal, ""
+	}
+	return b, to
+}
+
+// loopBranch tells how a loop labeled label continues after its body left with b
+// and to: whether it stops, and how the loop itself is left then.
+func loopBranch(b branch, to, label string) (stop bool, lb branch, lto string) {
+	switch {
+	case b == normal:
+		return false, normal, ""
+	case b == breakBranch && (to == "" || to == label):
+		return true, normal, ""
+	case b == continueBranch && (to == "" || to == label):
+		return false, normal, ""
+	}
+	return true, b, to
+}
+
+func (fr *frame) assign(stmt *ast.AssignStmt) {
+	switch stmt.Tok {
+	case token.DEFINE:
+		values := fr.values(len(stmt.Lhs), stmt.Rhs)
+		for i, lhs := range stmt.Lhs {
+			ident := lhs.(*ast.Ident)
+			if ident.Name == "_" {
+				continue
+			}
+			if obj := fr.code.info.Defs[ident]; obj != nil {
+				fr.declareObj(obj, values[i])
+			} else {
+				setValue(fr.variable(fr.code.info.Uses[ident]), values[i])
+			}
+		}
+	case token.ASSIGN:
+		lvalues := make([]lvalue, len(stmt.Lhs))
+		for i, lhs := range stmt.Lhs {
+			lvalues[i] = fr.lvalue(lhs)
+		}
+		values := fr.values(len(stmt.Lhs), stmt.Rhs)
+		for i, lv := range lvalues {
+			lv.set(values[i])
+		}
+	default:
+		lv := fr.lvalue(stmt.Lhs[0])
+		y := fr.eval(stmt.Rhs[0])
+		lv.set(binaryOp(assignOps[stmt.Tok], lv.get(), y, lv.typ()))
+	}
+}
+
+// assignOps maps the assignment operators to their binary operators.
+var assignOps = map[token.Token]token.Token{
+	token.ADD_ASSIGN:     token.ADD,
+	token.SUB_ASSIGN:     token.SUB,
+	token.MUL_ASSIGN:     token.MUL,
+	token.QUO_ASSIGN:     token.QUO,
+	token.REM_ASSIGN:     token.REM,
+	token.AND_ASSIGN:     token.AND,
+	token.OR_ASSIGN:      token.OR,
+	token.XOR_ASSIGN:     token.XOR,
+	token.SHL_ASSIGN:     token.SHL,
+	token.SHR_ASSIGN:     token.SHR,
+	token.AND_NOT_ASSIGN: token.AND_NOT,
+}
+
+// values evaluates the n values of exprs, the right hand side of an assignment.
+func (fr *frame) values(n int, exprs []ast.Expr) []reflect.Value {
+	if len(exprs) == 1 && n > 1 {
+		return fr.evalMulti(exprs[0])
+	}
+
+	values := make([]reflect.Value, len(exprs))
+	for i, expr := range exprs {
+		values[i] = fr.eval(expr)
+	}
+	return values
+}
+
+func (fr *frame) declStmt(decl *ast.GenDecl) {
+	if decl.Tok != token.VAR {
+		// constants and types are taken from the types of the check
+		return
+	}
+
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ValueSpec)
+		var values []reflect.Value
+		if len(spec.Values) > 0 {
+			values = fr.values(len(spec.Names), spec.Values)
+		}
+		for i, name := range spec.Names {
+			var v reflect.Value
+			if values != nil {
+				v = values[i]
+			}
+			fr.declare(name, v)
+		}
+	}
+}
+
+func (fr *frame) ifStmt(stmt *ast.IfStmt) (branch, string) {
+	outer := fr.scope
+	fr.scope = newScope(outer)
+	defer func() { fr.scope = outer }()
+
+	if stmt.Init != nil {
+		fr.exec(stmt.Init)
+	}
+	if fr.eval(stmt.Cond).Bool() {
+		return fr.execBlock(stmt.Body.List)
+	}
+	if stmt.Else != nil {
+		return fr.exec(stmt.Else)
+	}
+	return normal, ""
+}
+
+func (fr *frame) forStmt(stmt *ast.ForStmt, label string) (branch, string) {
+	outer := fr.scope
+	defer func() { fr.scope = outer }()
+
+	fr.scope = newScope(outer)
+	if stmt.Init != nil {
+		fr.exec(stmt.Init)
+	}
+
+	for {
+		fr.in.interrupted()
+		if stmt.Cond != nil && !fr.eval(stmt.Cond).Bool() {
+			return normal, ""
+		}
+
+		b, to := fr.execBlock(stmt.Body.List)
+		if stop, lb, lto := loopBranch(b, to, label); stop {
+			return lb, lto
+		}
+
+		// every iteration has variables of its own, set to those of the last one
+		// before the post statement
+		next := newScope(outer)
+		fr.scope.mu.RLock()
+		for key, v := range fr.scope.vars {
+			copied := reflect.New(v.Type()).Elem()
+			copied.Set(v)
+			next.vars[key] = copied
+		}
+		fr.scope.mu.RUnlock()
+		fr.scope = next
+
+		if stmt.Post != nil {
+			fr.exec(stmt.Post)
+		}
+	}
+}
+
+func (fr *frame) rangeStmt(stmt *ast.RangeStmt, label string) (branch, string) {
+	outer := fr.scope
+	defer func() { fr.scope = outer }()
+
+	x := fr.eval(stmt.X)
+
+	// body runs the body of the loop for the key and value k and v
+	body := func(k, v reflect.Value) (bool, branch, string) {
+		fr.in.interrupted()
+		fr.scope = newScope(outer)
+		if stmt.Tok == token.DEFINE {
+			if stmt.Key != nil {
+				fr.declare(stmt.Key.(*ast.Ident), k)
+			}
+			if stmt.Value != nil {
+				fr.declare(stmt.Value.(*ast.Ident), v)
+			}
+		} else if stmt.Tok == token.ASSIGN {
+			if stmt.Key != nil {
+				fr.lvalue(stmt.Key).set(k)
+			}
+			if stmt.Value != nil {
+				fr.lvalue(stmt.Value).set(v)
+			}
+		}
+
+		b, to := fr.execBlock(stmt.Body.List)
+		return loopBranch(b, to, label)
+	}
+
+	if x.Kind() == reflect.Ptr {
+		x = x.Elem()
+	}
+
+	switch x.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := toInt(x)
+		for i := 0; i < n; i++ {
+			k := reflect.New(x.Type()).Elem()
+			setValue(k, reflect.ValueOf(i).Convert(x.Type()))
+			if stop, b, to := body(k, reflect.Value{}); stop {
+				return b, to
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		n := x.Len()
+		for i := 0; i < n; i++ {
+			var v reflect.Value
+			if stmt.Value != nil {
+				v = x.Index(i)
+			}
+			if stop, b, to := body(reflect.ValueOf(i), v); stop {
+				return b, to
+			}
+		}
+	case reflect.String:
+		s := x.String()
+		for i, r := range s {
+			if stop, b, to := body(reflect.ValueOf(i), reflect.ValueOf(r)); stop {
+				return b, to
+			}
+		}
+	case reflect.Map:
+		iter := x.MapRange()
+		for iter.Next() {
+			if stop, b, to := body(iter.Key(), iter.Value()); stop {
+				return b, to
+			}
+		}
+	case reflect.Chan:
+		for {
+			v, ok := fr.recv(x)
+			if !ok {
+				break
+			}
+			if stop, b, to := body(v, reflect.Value{}); stop {
+				return b, to
+			}
+		}
+	}
+
+	return normal, ""
+}
+
+func (fr *frame) switchStmt(stmt *ast.SwitchStmt, label string) (branch, string) {
+	outer := fr.scope
+	fr.scope = newScope(outer)
+	defer func() { fr.scope = outer }()
+
+	if stmt.Init != nil {
+		fr.exec(stmt.Init)
+	}
+	tag := reflect.ValueOf(true)
+	if stmt.Tag != nil {
+		tag = fr.eval(stmt.Tag)
+	}
+
+	clauses := stmt.Body.List
+	matched := -1
+	for i, clause := range clauses {
+		for _, expr := range clause.(*ast.CaseClause).List {
+			if equal(tag, fr.eval(expr)) {
+				matched = i
+				break
+			}
+		}
+		if matched >= 0 {
+			break
+		}
+	}
+	if matched < 0 {
+		for i, clause := range clauses {
+			if clause.(*ast.CaseClause).List == nil {
+				matched = i
+			}
+		}
+	}
+	if matched < 0 {
+		return normal, ""
+	}
+
+	for i := matched; i < len(clauses); i++ {
+		b, to := fr.execBlock(clauses[i].(*ast.CaseClause).Body)
+		switch {
+		case b == fallthroughBranch:
+			continue
+		case b == breakBranch && (to == "" || to == label):
+			return normal, ""
+		}
+		return b, to
+	}
+	return normal, ""
+}
+
+func (fr *frame) typeSwitchStmt(stmt *ast.TypeSwitchStmt, label string) (branch, string) {
+	outer := fr.scope
+	fr.scope = newScope(outer)
+	defer func() { fr.scope = outer }()
+
+	if stmt.Init != nil {
+		fr.exec(stmt.Init)
+	}
+
+	var assert *ast.TypeAssertExpr
+	switch s := stmt.Assign.(type) {
+	case *ast.ExprStmt:
+		assert = s.X.(*ast.TypeAssertExpr)
+	case *ast.AssignStmt:
+		assert = s.Rhs[0].(*ast.TypeAssertExpr)
+	}
+	x := fr.eval(assert.X)
+
+	matched, matchedType := fr.typeCase(x, stmt.Body.List)
+	if matched == nil {
+		return normal, ""
+	}
+
+	fr.scope = newScope(fr.scope)
+	if obj := fr.code.info.Implicits[matched]; obj != nil {
+		v := x
+		if matchedType != nil {
+			v, _ = fr.assertType(x, fr.in.rtype(matchedType))
+		}
+		fr.declareObj(obj, v)
+	}
+
+	b, to := fr.execList(matched.Body)
+	if b == breakBranch && (to == "" || to == label) {
+		return normal, ""
+	}
+	return b, to
+}
+
+// typeCase returns the clause of a type switch matching the dynamic type of x, and
+// the type it lists if it is the only one, or the default clause.
+func (fr *frame) typeCase(x reflect.Value, clauses []ast.Stmt) (*ast.CaseClause, types.Type) {
+	var dflt *ast.CaseClause
+	for _, clause := range clauses {
+		clause := clause.(*ast.CaseClause)
+		if clause.List == nil {
+			dflt = clause
+		}
+		for _, expr := range clause.List {
+			tv := fr.code.info.Types[expr]
+			var ok bool
+			if tv.IsNil() {
+				ok = x.IsNil()
+			} else {
+				_, ok = fr.assertType(x, fr.in.rtype(tv.Type))
+			}
+			if !ok {
+				continue
+			}
+			if len(clause.List) > 1 || tv.IsNil() {
+				return clause, nil
+			}
+			return clause, tv.Type
+		}
+	}
+	return dflt, nil
+}
+
+func (fr *frame) selectStmt(stmt *ast.SelectStmt, label string) (branch, string) {
+	var cases []reflect.SelectCase
+	var clauses []*ast.CommClause
+	hasDefault := false
+	for _, clause := range stmt.Body.List {
+		clause := clause.(*ast.CommClause)
+		switch comm := clause.Comm.(type) {
+		case nil:
+			hasDefault = true
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+		case *ast.SendStmt:
+			ch := fr.eval(comm.Chan)
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: ch, Send: converted(fr.eval(comm.Value), ch.Type().Elem())})
+		case *ast.ExprStmt:
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: fr.eval(unparen(comm.X).(*ast.UnaryExpr).X)})
+		case *ast.AssignStmt:
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: fr.eval(unparen(comm.Rhs[0]).(*ast.UnaryExpr).X)})
+		}
+		clauses = append(clauses, clause)
+	}
+	if !hasDefault {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(fr.in.done.Load().(chan struct{}))})
+	}
+
+	chosen, v, ok := reflect.Select(cases)
+	if chosen == len(clauses) {
+		panic(interruption{})
+	}
+
+	clause := clauses[chosen]
+	outer := fr.scope
+	fr.scope = newScope(outer)
+	defer func() { fr.scope = outer }()
+
+	if assign, isAssign := clause.Comm.(*ast.AssignStmt); isAssign {
+		values := []reflect.Value{v, reflect.ValueOf(ok)}
+		for i, lhs := range assign.Lhs {
+			if assign.Tok == token.DEFINE {
+				fr.declare(lhs.(*ast.Ident), values[i])
+			} else {
+				fr.lvalue(lhs).set(values[i])
+			}
+		}
+	}
+
+	b, to := fr.execList(clause.Body)
+	if b == breakBranch && (to == "" || to == label) {
+		return normal, ""
+	}
+	return b, to
+}
+
+func (fr *frame) returnStmt(stmt *ast.ReturnStmt) {
+	if len(stmt.Results) == 0 {
+		return
+	}
+
+	values := fr.values(len(fr.results), stmt.Results)
+	for i, v := range values {
+		setValue(fr.results[i], v)
+	}
+}
+
+// call calls the function literal lit of the code c, whose variables are declared
+// in parent, with args.
+func (in *Interpreter) call(c *code, parent *scope, lit *ast.FuncLit, args []reflect.Value, deferring *frame) []reflect.Value {
+	fr := &frame{
+		in:        in,
+		code:      c,
+		name:      c.funcNames[lit],
+		scope:     newScope(parent),
+		pos:       lit.Pos(),
+		deferring: deferring,
+	}
+
+	i := 0
+	for _, field := range lit.Type.Params.List {
+		if len(field.Names) == 0 {
+			i++
+			continue
+		}
+		for _, name := range field.Names {
+			fr.declare(name, args[i])
+			i++
+		}
+	}
+
+	sig := c.info.TypeOf(lit).(*types.Signature)
+	for i := 0; i < sig.Results().Len(); i++ {
+		result := sig.Results().At(i)
+		if result.Name() != "" && result.Name() != "_" {
+			fr.results = append(fr.results, fr.declareObj(result, reflect.Value{}))
+		} else {
+			fr.results = append(fr.results, reflect.New(in.rtype(result.Type())).Elem())
+		}
+	}
+
+	fr.run(lit.Body.List)
+
+	results := make([]reflect.Value, len(fr.results))
+	for i, v := range fr.results {
+		results[i] = reflect.New(v.Type()).Elem()
+		results[i].Set(v)
+	}
+	return results
+}
+
+// funcLit returns the function value of lit.
+func (fr *frame) funcLit(lit *ast.FuncLit) reflect.Value {
+	in, c, parent := fr.in, fr.code, fr.scope
+	return reflect.MakeFunc(in.rtype(c.info.TypeOf(lit)), func(args []reflect.Value) []reflect.Value {
+		return in.call(c, parent, lit, args, nil)
+	})
+}
+
+// deferredCall evaluates the function and arguments of call, and returns the
+// function making the call, for go and defer statements.
+func (fr *frame) deferredCall(call *ast.CallExpr) func() {
+	if id, ok := unparen(call.Fun).(*ast.Ident); ok {
+		if b, ok := fr.code.info.Uses[id].(*types.Builtin); ok {
+			args := fr.args(call, nil)
+			return func() {
+				fr.builtin(b.Name(), call, args)
+			}
+		}
+	}
+
+	if lit, ok := unparen(call.Fun).(*ast.FuncLit); ok {
+		ftype := fr.in.rtype(fr.code.info.TypeOf(lit))
+		args := fr.args(call, ftype)
+		in, c, parent := fr.in, fr.code, fr.scope
+		return func() {
+			in.call(c, parent, lit, variadicArgs(ftype, args, call.Ellipsis.IsValid()), fr)
+		}
+	}
+
+	fn := fr.eval(call.Fun)
+	args := fr.args(call, fn.Type())
+	return func() {
+		callFunc(fn, args, call.Ellipsis.IsValid())
+	}
+}
+
+// variadicArgs returns args, passed to a function of type ftype, with the
+// variadic ones in a slice, as MakeFunc passes them.
+func variadicArgs(ftype reflect.Type, args []reflect.Value, ellipsis bool) []reflect.Value {
+	if !ftype.IsVariadic() || ellipsis {
+		return args
+	}
+
+	n := ftype.NumIn() - 1
+	rest := reflect.MakeSlice(ftype.In(n), 0, len(args)-n)
+	for _, arg := range args[n:] {
+		rest = reflect.Append(rest, converted(arg, ftype.In(n).Elem()))
+	}
+	return append(args[:n:n], rest)
+}
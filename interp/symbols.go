@@ -0,0 +1,1532 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package interp
+
+import (
+	"reflect"
+
+	bufio "bufio"
+	bytes "bytes"
+	container_heap "container/heap"
+	container_list "container/list"
+	context "context"
+	crypto_md5 "crypto/md5"
+	crypto_sha1 "crypto/sha1"
+	crypto_sha256 "crypto/sha256"
+	encoding_base64 "encoding/base64"
+	encoding_csv "encoding/csv"
+	encoding_hex "encoding/hex"
+	encoding_json "encoding/json"
+	errors "errors"
+	fmt "fmt"
+	github_com_fabian_z_gopherlab_display "github.com/fabian-z/gopherlab/display"
+	github_com_fabian_z_gopherlab_ipc "github.com/fabian-z/gopherlab/ipc"
+	hash "hash"
+	hash_crc32 "hash/crc32"
+	html "html"
+	image "image"
+	image_color "image/color"
+	image_draw "image/draw"
+	image_png "image/png"
+	io "io"
+	io_fs "io/fs"
+	io_ioutil "io/ioutil"
+	log "log"
+	math "math"
+	math_big "math/big"
+	math_bits "math/bits"
+	math_cmplx "math/cmplx"
+	math_rand "math/rand"
+	net_url "net/url"
+	os "os"
+	path "path"
+	path_filepath "path/filepath"
+	regexp "regexp"
+	sort "sort"
+	strconv "strconv"
+	strings "strings"
+	sync "sync"
+	sync_atomic "sync/atomic"
+	text_tabwriter "text/tabwriter"
+	text_template "text/template"
+	time "time"
+	unicode "unicode"
+	unicode_utf16 "unicode/utf16"
+	unicode_utf8 "unicode/utf8"
+)
+
+// symbols holds the exported functions, variables and types of the packages
+// the interpreter can use, by package path and name. Functions are held as
+// they are, variables as pointers to them and types as nil pointers.
+var symbols = map[string]map[string]reflect.Value{
+	"bufio": {
+		"ErrAdvanceTooFar":     reflect.ValueOf(&bufio.ErrAdvanceTooFar),
+		"ErrBadReadCount":      reflect.ValueOf(&bufio.ErrBadReadCount),
+		"ErrBufferFull":        reflect.ValueOf(&bufio.ErrBufferFull),
+		"ErrFinalToken":        reflect.ValueOf(&bufio.ErrFinalToken),
+		"ErrInvalidUnreadByte": reflect.ValueOf(&bufio.ErrInvalidUnreadByte),
+		"ErrInvalidUnreadRune": reflect.ValueOf(&bufio.ErrInvalidUnreadRune),
+		"ErrNegativeAdvance":   reflect.ValueOf(&bufio.ErrNegativeAdvance),
+		"ErrNegativeCount":     reflect.ValueOf(&bufio.ErrNegativeCount),
+		"ErrTooLong":           reflect.ValueOf(&bufio.ErrTooLong),
+		"NewReadWriter":        reflect.ValueOf(bufio.NewReadWriter),
+		"NewReader":            reflect.ValueOf(bufio.NewReader),
+		"NewReaderSize":        reflect.ValueOf(bufio.NewReaderSize),
+		"NewScanner":           reflect.ValueOf(bufio.NewScanner),
+		"NewWriter":            reflect.ValueOf(bufio.NewWriter),
+		"NewWriterSize":        reflect.ValueOf(bufio.NewWriterSize),
+		"ReadWriter":           reflect.ValueOf((*bufio.ReadWriter)(nil)),
+		"Reader":               reflect.ValueOf((*bufio.Reader)(nil)),
+		"ScanBytes":            reflect.ValueOf(bufio.ScanBytes),
+		"ScanLines":            reflect.ValueOf(bufio.ScanLines),
+		"ScanRunes":            reflect.ValueOf(bufio.ScanRunes),
+		"ScanWords":            reflect.ValueOf(bufio.ScanWords),
+		"Scanner":              reflect.ValueOf((*bufio.Scanner)(nil)),
+		"SplitFunc":            reflect.ValueOf((*bufio.SplitFunc)(nil)),
+		"Writer":               reflect.ValueOf((*bufio.Writer)(nil)),
+	},
+	"bytes": {
+		"Buffer":          reflect.ValueOf((*bytes.Buffer)(nil)),
+		"Clone":           reflect.ValueOf(bytes.Clone),
+		"Compare":         reflect.ValueOf(bytes.Compare),
+		"Contains":        reflect.ValueOf(bytes.Contains),
+		"ContainsAny":     reflect.ValueOf(bytes.ContainsAny),
+		"ContainsFunc":    reflect.ValueOf(bytes.ContainsFunc),
+		"ContainsRune":    reflect.ValueOf(bytes.ContainsRune),
+		"Count":           reflect.ValueOf(bytes.Count),
+		"Cut":             reflect.ValueOf(bytes.Cut),
+		"CutLast":         reflect.ValueOf(bytes.CutLast),
+		"CutPrefix":       reflect.ValueOf(bytes.CutPrefix),
+		"CutSuffix":       reflect.ValueOf(bytes.CutSuffix),
+		"Equal":           reflect.ValueOf(bytes.Equal),
+		"EqualFold":       reflect.ValueOf(bytes.EqualFold),
+		"ErrTooLarge":     reflect.ValueOf(&bytes.ErrTooLarge),
+		"Fields":          reflect.ValueOf(bytes.Fields),
+		"FieldsFunc":      reflect.ValueOf(bytes.FieldsFunc),
+		"FieldsFuncSeq":   reflect.ValueOf(bytes.FieldsFuncSeq),
+		"FieldsSeq":       reflect.ValueOf(bytes.FieldsSeq),
+		"HasPrefix":       reflect.ValueOf(bytes.HasPrefix),
+		"HasSuffix":       reflect.ValueOf(bytes.HasSuffix),
+		"Index":           reflect.ValueOf(bytes.Index),
+		"IndexAny":        reflect.ValueOf(bytes.IndexAny),
+		"IndexByte":       reflect.ValueOf(bytes.IndexByte),
+		"IndexFunc":       reflect.ValueOf(bytes.IndexFunc),
+		"IndexRune":       reflect.ValueOf(bytes.IndexRune),
+		"Join":            reflect.ValueOf(bytes.Join),
+		"LastIndex":       reflect.ValueOf(bytes.LastIndex),
+		"LastIndexAny":    reflect.ValueOf(bytes.LastIndexAny),
+		"LastIndexByte":   reflect.ValueOf(bytes.LastIndexByte),
+		"LastIndexFunc":   reflect.ValueOf(bytes.LastIndexFunc),
+		"Lines":           reflect.ValueOf(bytes.Lines),
+		"Map":             reflect.ValueOf(bytes.Map),
+		"NewBuffer":       reflect.ValueOf(bytes.NewBuffer),
+		"NewBufferString": reflect.ValueOf(bytes.NewBufferString),
+		"NewReader":       reflect.ValueOf(bytes.NewReader),
+		"Reader":          reflect.ValueOf((*bytes.Reader)(nil)),
+		"Repeat":          reflect.ValueOf(bytes.Repeat),
+		"Replace":         reflect.ValueOf(bytes.Replace),
+		"ReplaceAll":      reflect.ValueOf(bytes.ReplaceAll),
+		"Runes":           reflect.ValueOf(bytes.Runes),
+		"Split":           reflect.ValueOf(bytes.Split),
+		"SplitAfter":      reflect.ValueOf(bytes.SplitAfter),
+		"SplitAfterN":     reflect.ValueOf(bytes.SplitAfterN),
+		"SplitAfterSeq":   reflect.ValueOf(bytes.SplitAfterSeq),
+		"SplitN":          reflect.ValueOf(bytes.SplitN),
+		"SplitSeq":        reflect.ValueOf(bytes.SplitSeq),
+		"Title":           reflect.ValueOf(bytes.Title),
+		"ToLower":         reflect.ValueOf(bytes.ToLower),
+		"ToLowerSpecial":  reflect.ValueOf(bytes.ToLowerSpecial),
+		"ToTitle":         reflect.ValueOf(bytes.ToTitle),
+		"ToTitleSpecial":  reflect.ValueOf(bytes.ToTitleSpecial),
+		"ToUpper":         reflect.ValueOf(bytes.ToUpper),
+		"ToUpperSpecial":  reflect.ValueOf(bytes.ToUpperSpecial),
+		"ToValidUTF8":     reflect.ValueOf(bytes.ToValidUTF8),
+		"Trim":            reflect.ValueOf(bytes.Trim),
+		"TrimFunc":        reflect.ValueOf(bytes.TrimFunc),
+		"TrimLeft":        reflect.ValueOf(bytes.TrimLeft),
+		"TrimLeftFunc":    reflect.ValueOf(bytes.TrimLeftFunc),
+		"TrimPrefix":      reflect.ValueOf(bytes.TrimPrefix),
+		"TrimRight":       reflect.ValueOf(bytes.TrimRight),
+		"TrimRightFunc":   reflect.ValueOf(bytes.TrimRightFunc),
+		"TrimSpace":       reflect.ValueOf(bytes.TrimSpace),
+		"TrimSuffix":      reflect.ValueOf(bytes.TrimSuffix),
+	},
+	"container/heap": {
+		"Fix":       reflect.ValueOf(container_heap.Fix),
+		"Init":      reflect.ValueOf(container_heap.Init),
+		"Interface": reflect.ValueOf((*container_heap.Interface)(nil)),
+		"Pop":       reflect.ValueOf(container_heap.Pop),
+		"Push":      reflect.ValueOf(container_heap.Push),
+		"Remove":    reflect.ValueOf(container_heap.Remove),
+	},
+	"container/list": {
+		"Element": reflect.ValueOf((*container_list.Element)(nil)),
+		"List":    reflect.ValueOf((*container_list.List)(nil)),
+		"New":     reflect.ValueOf(container_list.New),
+	},
+	"context": {
+		"AfterFunc":         reflect.ValueOf(context.AfterFunc),
+		"Background":        reflect.ValueOf(context.Background),
+		"CancelCauseFunc":   reflect.ValueOf((*context.CancelCauseFunc)(nil)),
+		"CancelFunc":        reflect.ValueOf((*context.CancelFunc)(nil)),
+		"Canceled":          reflect.ValueOf(&context.Canceled),
+		"Cause":             reflect.ValueOf(context.Cause),
+		"Context":           reflect.ValueOf((*context.Context)(nil)),
+		"DeadlineExceeded":  reflect.ValueOf(&context.DeadlineExceeded),
+		"TODO":              reflect.ValueOf(context.TODO),
+		"WithCancel":        reflect.ValueOf(context.WithCancel),
+		"WithCancelCause":   reflect.ValueOf(context.WithCancelCause),
+		"WithDeadline":      reflect.ValueOf(context.WithDeadline),
+		"WithDeadlineCause": reflect.ValueOf(context.WithDeadlineCause),
+		"WithTimeout":       reflect.ValueOf(context.WithTimeout),
+		"WithTimeoutCause":  reflect.ValueOf(context.WithTimeoutCause),
+		"WithValue":         reflect.ValueOf(context.WithValue),
+		"WithoutCancel":     reflect.ValueOf(context.WithoutCancel),
+	},
+	"crypto/md5": {
+		"New": reflect.ValueOf(crypto_md5.New),
+		"Sum": reflect.ValueOf(crypto_md5.Sum),
+	},
+	"crypto/sha1": {
+		"New": reflect.ValueOf(crypto_sha1.New),
+		"Sum": reflect.ValueOf(crypto_sha1.Sum),
+	},
+	"crypto/sha256": {
+		"New":    reflect.ValueOf(crypto_sha256.New),
+		"New224": reflect.ValueOf(crypto_sha256.New224),
+		"Sum224": reflect.ValueOf(crypto_sha256.Sum224),
+		"Sum256": reflect.ValueOf(crypto_sha256.Sum256),
+	},
+	"encoding/base64": {
+		"CorruptInputError": reflect.ValueOf((*encoding_base64.CorruptInputError)(nil)),
+		"Encoding":          reflect.ValueOf((*encoding_base64.Encoding)(nil)),
+		"NewDecoder":        reflect.ValueOf(encoding_base64.NewDecoder),
+		"NewEncoder":        reflect.ValueOf(encoding_base64.NewEncoder),
+		"NewEncoding":       reflect.ValueOf(encoding_base64.NewEncoding),
+		"RawStdEncoding":    reflect.ValueOf(&encoding_base64.RawStdEncoding),
+		"RawURLEncoding":    reflect.ValueOf(&encoding_base64.RawURLEncoding),
+		"StdEncoding":       reflect.ValueOf(&encoding_base64.StdEncoding),
+		"URLEncoding":       reflect.ValueOf(&encoding_base64.URLEncoding),
+	},
+	"encoding/csv": {
+		"ErrBareQuote":     reflect.ValueOf(&encoding_csv.ErrBareQuote),
+		"ErrFieldCount":    reflect.ValueOf(&encoding_csv.ErrFieldCount),
+		"ErrQuote":         reflect.ValueOf(&encoding_csv.ErrQuote),
+		"ErrTrailingComma": reflect.ValueOf(&encoding_csv.ErrTrailingComma),
+		"NewReader":        reflect.ValueOf(encoding_csv.NewReader),
+		"NewWriter":        reflect.ValueOf(encoding_csv.NewWriter),
+		"ParseError":       reflect.ValueOf((*encoding_csv.ParseError)(nil)),
+		"Reader":           reflect.ValueOf((*encoding_csv.Reader)(nil)),
+		"Writer":           reflect.ValueOf((*encoding_csv.Writer)(nil)),
+	},
+	"encoding/hex": {
+		"AppendDecode":     reflect.ValueOf(encoding_hex.AppendDecode),
+		"AppendEncode":     reflect.ValueOf(encoding_hex.AppendEncode),
+		"Decode":           reflect.ValueOf(encoding_hex.Decode),
+		"DecodeString":     reflect.ValueOf(encoding_hex.DecodeString),
+		"DecodedLen":       reflect.ValueOf(encoding_hex.DecodedLen),
+		"Dump":             reflect.ValueOf(encoding_hex.Dump),
+		"Dumper":           reflect.ValueOf(encoding_hex.Dumper),
+		"Encode":           reflect.ValueOf(encoding_hex.Encode),
+		"EncodeToString":   reflect.ValueOf(encoding_hex.EncodeToString),
+		"EncodedLen":       reflect.ValueOf(encoding_hex.EncodedLen),
+		"ErrLength":        reflect.ValueOf(&encoding_hex.ErrLength),
+		"InvalidByteError": reflect.ValueOf((*encoding_hex.InvalidByteError)(nil)),
+		"NewDecoder":       reflect.ValueOf(encoding_hex.NewDecoder),
+		"NewEncoder":       reflect.ValueOf(encoding_hex.NewEncoder),
+	},
+	"encoding/json": {
+		"CallMethodsWithLegacySemantics":  reflect.ValueOf(encoding_json.CallMethodsWithLegacySemantics),
+		"Compact":                         reflect.ValueOf(encoding_json.Compact),
+		"Decoder":                         reflect.ValueOf((*encoding_json.Decoder)(nil)),
+		"DefaultOptionsV1":                reflect.ValueOf(encoding_json.DefaultOptionsV1),
+		"Delim":                           reflect.ValueOf((*encoding_json.Delim)(nil)),
+		"Encoder":                         reflect.ValueOf((*encoding_json.Encoder)(nil)),
+		"FormatByteArrayAsArray":          reflect.ValueOf(encoding_json.FormatByteArrayAsArray),
+		"FormatBytesWithLegacySemantics":  reflect.ValueOf(encoding_json.FormatBytesWithLegacySemantics),
+		"FormatDurationAsNano":            reflect.ValueOf(encoding_json.FormatDurationAsNano),
+		"HTMLEscape":                      reflect.ValueOf(encoding_json.HTMLEscape),
+		"Indent":                          reflect.ValueOf(encoding_json.Indent),
+		"InvalidUTF8Error":                reflect.ValueOf((*encoding_json.InvalidUTF8Error)(nil)),
+		"InvalidUnmarshalError":           reflect.ValueOf((*encoding_json.InvalidUnmarshalError)(nil)),
+		"Marshal":                         reflect.ValueOf(encoding_json.Marshal),
+		"MarshalIndent":                   reflect.ValueOf(encoding_json.MarshalIndent),
+		"Marshaler":                       reflect.ValueOf((*encoding_json.Marshaler)(nil)),
+		"MarshalerError":                  reflect.ValueOf((*encoding_json.MarshalerError)(nil)),
+		"MatchCaseSensitiveDelimiter":     reflect.ValueOf(encoding_json.MatchCaseSensitiveDelimiter),
+		"MergeWithLegacySemantics":        reflect.ValueOf(encoding_json.MergeWithLegacySemantics),
+		"NewDecoder":                      reflect.ValueOf(encoding_json.NewDecoder),
+		"NewEncoder":                      reflect.ValueOf(encoding_json.NewEncoder),
+		"Number":                          reflect.ValueOf((*encoding_json.Number)(nil)),
+		"OmitEmptyWithLegacySemantics":    reflect.ValueOf(encoding_json.OmitEmptyWithLegacySemantics),
+		"Options":                         reflect.ValueOf((*encoding_json.Options)(nil)),
+		"ParseBytesWithLooseRFC4648":      reflect.ValueOf(encoding_json.ParseBytesWithLooseRFC4648),
+		"ParseTimeWithLooseRFC3339":       reflect.ValueOf(encoding_json.ParseTimeWithLooseRFC3339),
+		"RawMessage":                      reflect.ValueOf((*encoding_json.RawMessage)(nil)),
+		"ReportErrorsWithLegacySemantics": reflect.ValueOf(encoding_json.ReportErrorsWithLegacySemantics),
+		"StringifyWithLegacySemantics":    reflect.ValueOf(encoding_json.StringifyWithLegacySemantics),
+		"SyntaxError":                     reflect.ValueOf((*encoding_json.SyntaxError)(nil)),
+		"Token":                           reflect.ValueOf((*encoding_json.Token)(nil)),
+		"Unmarshal":                       reflect.ValueOf(encoding_json.Unmarshal),
+		"UnmarshalArrayFromAnyLength":     reflect.ValueOf(encoding_json.UnmarshalArrayFromAnyLength),
+		"UnmarshalFieldError":             reflect.ValueOf((*encoding_json.UnmarshalFieldError)(nil)),
+		"UnmarshalTypeError":              reflect.ValueOf((*encoding_json.UnmarshalTypeError)(nil)),
+		"Unmarshaler":                     reflect.ValueOf((*encoding_json.Unmarshaler)(nil)),
+		"UnsupportedTypeError":            reflect.ValueOf((*encoding_json.UnsupportedTypeError)(nil)),
+		"UnsupportedValueError":           reflect.ValueOf((*encoding_json.UnsupportedValueError)(nil)),
+		"Valid":                           reflect.ValueOf(encoding_json.Valid),
+	},
+	"errors": {
+		"As":             reflect.ValueOf(errors.As),
+		"ErrUnsupported": reflect.ValueOf(&errors.ErrUnsupported),
+		"Is":             reflect.ValueOf(errors.Is),
+		"Join":           reflect.ValueOf(errors.Join),
+		"New":            reflect.ValueOf(errors.New),
+		"Unwrap":         reflect.ValueOf(errors.Unwrap),
+	},
+	"fmt": {
+		"Append":       reflect.ValueOf(fmt.Append),
+		"Appendf":      reflect.ValueOf(fmt.Appendf),
+		"Appendln":     reflect.ValueOf(fmt.Appendln),
+		"Errorf":       reflect.ValueOf(fmt.Errorf),
+		"FormatString": reflect.ValueOf(fmt.FormatString),
+		"Formatter":    reflect.ValueOf((*fmt.Formatter)(nil)),
+		"Fprint":       reflect.ValueOf(fmt.Fprint),
+		"Fprintf":      reflect.ValueOf(fmt.Fprintf),
+		"Fprintln":     reflect.ValueOf(fmt.Fprintln),
+		"Fscan":        reflect.ValueOf(fmt.Fscan),
+		"Fscanf":       reflect.ValueOf(fmt.Fscanf),
+		"Fscanln":      reflect.ValueOf(fmt.Fscanln),
+		"GoStringer":   reflect.ValueOf((*fmt.GoStringer)(nil)),
+		"Print":        reflect.ValueOf(fmt.Print),
+		"Printf":       reflect.ValueOf(fmt.Printf),
+		"Println":      reflect.ValueOf(fmt.Println),
+		"Scan":         reflect.ValueOf(fmt.Scan),
+		"ScanState":    reflect.ValueOf((*fmt.ScanState)(nil)),
+		"Scanf":        reflect.ValueOf(fmt.Scanf),
+		"Scanln":       reflect.ValueOf(fmt.Scanln),
+		"Scanner":      reflect.ValueOf((*fmt.Scanner)(nil)),
+		"Sprint":       reflect.ValueOf(fmt.Sprint),
+		"Sprintf":      reflect.ValueOf(fmt.Sprintf),
+		"Sprintln":     reflect.ValueOf(fmt.Sprintln),
+		"Sscan":        reflect.ValueOf(fmt.Sscan),
+		"Sscanf":       reflect.ValueOf(fmt.Sscanf),
+		"Sscanln":      reflect.ValueOf(fmt.Sscanln),
+		"State":        reflect.ValueOf((*fmt.State)(nil)),
+		"Stringer":     reflect.ValueOf((*fmt.Stringer)(nil)),
+	},
+	"hash": {
+		"Cloner": reflect.ValueOf((*hash.Cloner)(nil)),
+		"Hash":   reflect.ValueOf((*hash.Hash)(nil)),
+		"Hash32": reflect.ValueOf((*hash.Hash32)(nil)),
+		"Hash64": reflect.ValueOf((*hash.Hash64)(nil)),
+		"XOF":    reflect.ValueOf((*hash.XOF)(nil)),
+	},
+	"hash/crc32": {
+		"Checksum":     reflect.ValueOf(hash_crc32.Checksum),
+		"ChecksumIEEE": reflect.ValueOf(hash_crc32.ChecksumIEEE),
+		"IEEETable":    reflect.ValueOf(&hash_crc32.IEEETable),
+		"MakeTable":    reflect.ValueOf(hash_crc32.MakeTable),
+		"New":          reflect.ValueOf(hash_crc32.New),
+		"NewIEEE":      reflect.ValueOf(hash_crc32.NewIEEE),
+		"Table":        reflect.ValueOf((*hash_crc32.Table)(nil)),
+		"Update":       reflect.ValueOf(hash_crc32.Update),
+	},
+	"html": {
+		"EscapeString":   reflect.ValueOf(html.EscapeString),
+		"UnescapeString": reflect.ValueOf(html.UnescapeString),
+	},
+	"image": {
+		"Alpha":               reflect.ValueOf((*image.Alpha)(nil)),
+		"Alpha16":             reflect.ValueOf((*image.Alpha16)(nil)),
+		"Black":               reflect.ValueOf(&image.Black),
+		"CMYK":                reflect.ValueOf((*image.CMYK)(nil)),
+		"Config":              reflect.ValueOf((*image.Config)(nil)),
+		"Decode":              reflect.ValueOf(image.Decode),
+		"DecodeConfig":        reflect.ValueOf(image.DecodeConfig),
+		"ErrFormat":           reflect.ValueOf(&image.ErrFormat),
+		"Gray":                reflect.ValueOf((*image.Gray)(nil)),
+		"Gray16":              reflect.ValueOf((*image.Gray16)(nil)),
+		"Image":               reflect.ValueOf((*image.Image)(nil)),
+		"NRGBA":               reflect.ValueOf((*image.NRGBA)(nil)),
+		"NRGBA64":             reflect.ValueOf((*image.NRGBA64)(nil)),
+		"NYCbCrA":             reflect.ValueOf((*image.NYCbCrA)(nil)),
+		"NewAlpha":            reflect.ValueOf(image.NewAlpha),
+		"NewAlpha16":          reflect.ValueOf(image.NewAlpha16),
+		"NewCMYK":             reflect.ValueOf(image.NewCMYK),
+		"NewGray":             reflect.ValueOf(image.NewGray),
+		"NewGray16":           reflect.ValueOf(image.NewGray16),
+		"NewNRGBA":            reflect.ValueOf(image.NewNRGBA),
+		"NewNRGBA64":          reflect.ValueOf(image.NewNRGBA64),
+		"NewNYCbCrA":          reflect.ValueOf(image.NewNYCbCrA),
+		"NewPaletted":         reflect.ValueOf(image.NewPaletted),
+		"NewRGBA":             reflect.ValueOf(image.NewRGBA),
+		"NewRGBA64":           reflect.ValueOf(image.NewRGBA64),
+		"NewUniform":          reflect.ValueOf(image.NewUniform),
+		"NewYCbCr":            reflect.ValueOf(image.NewYCbCr),
+		"Opaque":              reflect.ValueOf(&image.Opaque),
+		"Paletted":            reflect.ValueOf((*image.Paletted)(nil)),
+		"PalettedImage":       reflect.ValueOf((*image.PalettedImage)(nil)),
+		"Point":               reflect.ValueOf((*image.Point)(nil)),
+		"Pt":                  reflect.ValueOf(image.Pt),
+		"RGBA":                reflect.ValueOf((*image.RGBA)(nil)),
+		"RGBA64":              reflect.ValueOf((*image.RGBA64)(nil)),
+		"RGBA64Image":         reflect.ValueOf((*image.RGBA64Image)(nil)),
+		"Rect":                reflect.ValueOf(image.Rect),
+		"Rectangle":           reflect.ValueOf((*image.Rectangle)(nil)),
+		"RegisterFormat":      reflect.ValueOf(image.RegisterFormat),
+		"Transparent":         reflect.ValueOf(&image.Transparent),
+		"Uniform":             reflect.ValueOf((*image.Uniform)(nil)),
+		"White":               reflect.ValueOf(&image.White),
+		"YCbCr":               reflect.ValueOf((*image.YCbCr)(nil)),
+		"YCbCrSubsampleRatio": reflect.ValueOf((*image.YCbCrSubsampleRatio)(nil)),
+		"ZP":                  reflect.ValueOf(&image.ZP),
+		"ZR":                  reflect.ValueOf(&image.ZR),
+	},
+	"image/color": {
+		"Alpha":        reflect.ValueOf((*image_color.Alpha)(nil)),
+		"Alpha16":      reflect.ValueOf((*image_color.Alpha16)(nil)),
+		"Alpha16Model": reflect.ValueOf(&image_color.Alpha16Model),
+		"AlphaModel":   reflect.ValueOf(&image_color.AlphaModel),
+		"Black":        reflect.ValueOf(&image_color.Black),
+		"CMYK":         reflect.ValueOf((*image_color.CMYK)(nil)),
+		"CMYKModel":    reflect.ValueOf(&image_color.CMYKModel),
+		"CMYKToRGB":    reflect.ValueOf(image_color.CMYKToRGB),
+		"Color":        reflect.ValueOf((*image_color.Color)(nil)),
+		"Gray":         reflect.ValueOf((*image_color.Gray)(nil)),
+		"Gray16":       reflect.ValueOf((*image_color.Gray16)(nil)),
+		"Gray16Model":  reflect.ValueOf(&image_color.Gray16Model),
+		"GrayModel":    reflect.ValueOf(&image_color.GrayModel),
+		"Model":        reflect.ValueOf((*image_color.Model)(nil)),
+		"ModelFunc":    reflect.ValueOf(image_color.ModelFunc),
+		"NRGBA":        reflect.ValueOf((*image_color.NRGBA)(nil)),
+		"NRGBA64":      reflect.ValueOf((*image_color.NRGBA64)(nil)),
+		"NRGBA64Model": reflect.ValueOf(&image_color.NRGBA64Model),
+		"NRGBAModel":   reflect.ValueOf(&image_color.NRGBAModel),
+		"NYCbCrA":      reflect.ValueOf((*image_color.NYCbCrA)(nil)),
+		"NYCbCrAModel": reflect.ValueOf(&image_color.NYCbCrAModel),
+		"Opaque":       reflect.ValueOf(&image_color.Opaque),
+		"Palette":      reflect.ValueOf((*image_color.Palette)(nil)),
+		"RGBA":         reflect.ValueOf((*image_color.RGBA)(nil)),
+		"RGBA64":       reflect.ValueOf((*image_color.RGBA64)(nil)),
+		"RGBA64Model":  reflect.ValueOf(&image_color.RGBA64Model),
+		"RGBAModel":    reflect.ValueOf(&image_color.RGBAModel),
+		"RGBToCMYK":    reflect.ValueOf(image_color.RGBToCMYK),
+		"RGBToYCbCr":   reflect.ValueOf(image_color.RGBToYCbCr),
+		"Transparent":  reflect.ValueOf(&image_color.Transparent),
+		"White":        reflect.ValueOf(&image_color.White),
+		"YCbCr":        reflect.ValueOf((*image_color.YCbCr)(nil)),
+		"YCbCrModel":   reflect.ValueOf(&image_color.YCbCrModel),
+		"YCbCrToRGB":   reflect.ValueOf(image_color.YCbCrToRGB),
+	},
+	"image/draw": {
+		"Draw":           reflect.ValueOf(image_draw.Draw),
+		"DrawMask":       reflect.ValueOf(image_draw.DrawMask),
+		"Drawer":         reflect.ValueOf((*image_draw.Drawer)(nil)),
+		"FloydSteinberg": reflect.ValueOf(&image_draw.FloydSteinberg),
+		"Image":          reflect.ValueOf((*image_draw.Image)(nil)),
+		"Op":             reflect.ValueOf((*image_draw.Op)(nil)),
+		"Quantizer":      reflect.ValueOf((*image_draw.Quantizer)(nil)),
+		"RGBA64Image":    reflect.ValueOf((*image_draw.RGBA64Image)(nil)),
+	},
+	"image/png": {
+		"CompressionLevel":  reflect.ValueOf((*image_png.CompressionLevel)(nil)),
+		"Decode":            reflect.ValueOf(image_png.Decode),
+		"DecodeConfig":      reflect.ValueOf(image_png.DecodeConfig),
+		"Encode":            reflect.ValueOf(image_png.Encode),
+		"Encoder":           reflect.ValueOf((*image_png.Encoder)(nil)),
+		"EncoderBuffer":     reflect.ValueOf((*image_png.EncoderBuffer)(nil)),
+		"EncoderBufferPool": reflect.ValueOf((*image_png.EncoderBufferPool)(nil)),
+		"FormatError":       reflect.ValueOf((*image_png.FormatError)(nil)),
+		"UnsupportedError":  reflect.ValueOf((*image_png.UnsupportedError)(nil)),
+	},
+	"io": {
+		"ByteReader":       reflect.ValueOf((*io.ByteReader)(nil)),
+		"ByteScanner":      reflect.ValueOf((*io.ByteScanner)(nil)),
+		"ByteWriter":       reflect.ValueOf((*io.ByteWriter)(nil)),
+		"Closer":           reflect.ValueOf((*io.Closer)(nil)),
+		"Copy":             reflect.ValueOf(io.Copy),
+		"CopyBuffer":       reflect.ValueOf(io.CopyBuffer),
+		"CopyN":            reflect.ValueOf(io.CopyN),
+		"Discard":          reflect.ValueOf(&io.Discard),
+		"EOF":              reflect.ValueOf(&io.EOF),
+		"ErrClosedPipe":    reflect.ValueOf(&io.ErrClosedPipe),
+		"ErrNoProgress":    reflect.ValueOf(&io.ErrNoProgress),
+		"ErrShortBuffer":   reflect.ValueOf(&io.ErrShortBuffer),
+		"ErrShortWrite":    reflect.ValueOf(&io.ErrShortWrite),
+		"ErrUnexpectedEOF": reflect.ValueOf(&io.ErrUnexpectedEOF),
+		"LimitReader":      reflect.ValueOf(io.LimitReader),
+		"LimitedReader":    reflect.ValueOf((*io.LimitedReader)(nil)),
+		"MultiReader":      reflect.ValueOf(io.MultiReader),
+		"MultiWriter":      reflect.ValueOf(io.MultiWriter),
+		"NewOffsetWriter":  reflect.ValueOf(io.NewOffsetWriter),
+		"NewSectionReader": reflect.ValueOf(io.NewSectionReader),
+		"NopCloser":        reflect.ValueOf(io.NopCloser),
+		"OffsetWriter":     reflect.ValueOf((*io.OffsetWriter)(nil)),
+		"Pipe":             reflect.ValueOf(io.Pipe),
+		"PipeReader":       reflect.ValueOf((*io.PipeReader)(nil)),
+		"PipeWriter":       reflect.ValueOf((*io.PipeWriter)(nil)),
+		"ReadAll":          reflect.ValueOf(io.ReadAll),
+		"ReadAtLeast":      reflect.ValueOf(io.ReadAtLeast),
+		"ReadCloser":       reflect.ValueOf((*io.ReadCloser)(nil)),
+		"ReadFull":         reflect.ValueOf(io.ReadFull),
+		"ReadSeekCloser":   reflect.ValueOf((*io.ReadSeekCloser)(nil)),
+		"ReadSeeker":       reflect.ValueOf((*io.ReadSeeker)(nil)),
+		"ReadWriteCloser":  reflect.ValueOf((*io.ReadWriteCloser)(nil)),
+		"ReadWriteSeeker":  reflect.ValueOf((*io.ReadWriteSeeker)(nil)),
+		"ReadWriter":       reflect.ValueOf((*io.ReadWriter)(nil)),
+		"Reader":           reflect.ValueOf((*io.Reader)(nil)),
+		"ReaderAt":         reflect.ValueOf((*io.ReaderAt)(nil)),
+		"ReaderFrom":       reflect.ValueOf((*io.ReaderFrom)(nil)),
+		"RuneReader":       reflect.ValueOf((*io.RuneReader)(nil)),
+		"RuneScanner":      reflect.ValueOf((*io.RuneScanner)(nil)),
+		"SectionReader":    reflect.ValueOf((*io.SectionReader)(nil)),
+		"Seeker":           reflect.ValueOf((*io.Seeker)(nil)),
+		"StringWriter":     reflect.ValueOf((*io.StringWriter)(nil)),
+		"TeeReader":        reflect.ValueOf(io.TeeReader),
+		"WriteCloser":      reflect.ValueOf((*io.WriteCloser)(nil)),
+		"WriteSeeker":      reflect.ValueOf((*io.WriteSeeker)(nil)),
+		"WriteString":      reflect.ValueOf(io.WriteString),
+		"Writer":           reflect.ValueOf((*io.Writer)(nil)),
+		"WriterAt":         reflect.ValueOf((*io.WriterAt)(nil)),
+		"WriterTo":         reflect.ValueOf((*io.WriterTo)(nil)),
+	},
+	"io/fs": {
+		"DirEntry":           reflect.ValueOf((*io_fs.DirEntry)(nil)),
+		"ErrClosed":          reflect.ValueOf(&io_fs.ErrClosed),
+		"ErrExist":           reflect.ValueOf(&io_fs.ErrExist),
+		"ErrInvalid":         reflect.ValueOf(&io_fs.ErrInvalid),
+		"ErrNotExist":        reflect.ValueOf(&io_fs.ErrNotExist),
+		"ErrPermission":      reflect.ValueOf(&io_fs.ErrPermission),
+		"FS":                 reflect.ValueOf((*io_fs.FS)(nil)),
+		"File":               reflect.ValueOf((*io_fs.File)(nil)),
+		"FileInfo":           reflect.ValueOf((*io_fs.FileInfo)(nil)),
+		"FileInfoToDirEntry": reflect.ValueOf(io_fs.FileInfoToDirEntry),
+		"FileMode":           reflect.ValueOf((*io_fs.FileMode)(nil)),
+		"FormatDirEntry":     reflect.ValueOf(io_fs.FormatDirEntry),
+		"FormatFileInfo":     reflect.ValueOf(io_fs.FormatFileInfo),
+		"Glob":               reflect.ValueOf(io_fs.Glob),
+		"GlobFS":             reflect.ValueOf((*io_fs.GlobFS)(nil)),
+		"Lstat":              reflect.ValueOf(io_fs.Lstat),
+		"PathError":          reflect.ValueOf((*io_fs.PathError)(nil)),
+		"ReadDir":            reflect.ValueOf(io_fs.ReadDir),
+		"ReadDirFS":          reflect.ValueOf((*io_fs.ReadDirFS)(nil)),
+		"ReadDirFile":        reflect.ValueOf((*io_fs.ReadDirFile)(nil)),
+		"ReadFile":           reflect.ValueOf(io_fs.ReadFile),
+		"ReadFileFS":         reflect.ValueOf((*io_fs.ReadFileFS)(nil)),
+		"ReadLink":           reflect.ValueOf(io_fs.ReadLink),
+		"ReadLinkFS":         reflect.ValueOf((*io_fs.ReadLinkFS)(nil)),
+		"SkipAll":            reflect.ValueOf(&io_fs.SkipAll),
+		"SkipDir":            reflect.ValueOf(&io_fs.SkipDir),
+		"Stat":               reflect.ValueOf(io_fs.Stat),
+		"StatFS":             reflect.ValueOf((*io_fs.StatFS)(nil)),
+		"Sub":                reflect.ValueOf(io_fs.Sub),
+		"SubFS":              reflect.ValueOf((*io_fs.SubFS)(nil)),
+		"ValidPath":          reflect.ValueOf(io_fs.ValidPath),
+		"WalkDir":            reflect.ValueOf(io_fs.WalkDir),
+		"WalkDirFunc":        reflect.ValueOf((*io_fs.WalkDirFunc)(nil)),
+	},
+	"io/ioutil": {
+		"Discard":   reflect.ValueOf(&io_ioutil.Discard),
+		"NopCloser": reflect.ValueOf(io_ioutil.NopCloser),
+		"ReadAll":   reflect.ValueOf(io_ioutil.ReadAll),
+		"ReadDir":   reflect.ValueOf(io_ioutil.ReadDir),
+		"ReadFile":  reflect.ValueOf(io_ioutil.ReadFile),
+		"TempDir":   reflect.ValueOf(io_ioutil.TempDir),
+		"TempFile":  reflect.ValueOf(io_ioutil.TempFile),
+		"WriteFile": reflect.ValueOf(io_ioutil.WriteFile),
+	},
+	"log": {
+		"Default":   reflect.ValueOf(log.Default),
+		"Fatal":     reflect.ValueOf(log.Fatal),
+		"Fatalf":    reflect.ValueOf(log.Fatalf),
+		"Fatalln":   reflect.ValueOf(log.Fatalln),
+		"Flags":     reflect.ValueOf(log.Flags),
+		"Logger":    reflect.ValueOf((*log.Logger)(nil)),
+		"New":       reflect.ValueOf(log.New),
+		"Output":    reflect.ValueOf(log.Output),
+		"Panic":     reflect.ValueOf(log.Panic),
+		"Panicf":    reflect.ValueOf(log.Panicf),
+		"Panicln":   reflect.ValueOf(log.Panicln),
+		"Prefix":    reflect.ValueOf(log.Prefix),
+		"Print":     reflect.ValueOf(log.Print),
+		"Printf":    reflect.ValueOf(log.Printf),
+		"Println":   reflect.ValueOf(log.Println),
+		"SetFlags":  reflect.ValueOf(log.SetFlags),
+		"SetOutput": reflect.ValueOf(log.SetOutput),
+		"SetPrefix": reflect.ValueOf(log.SetPrefix),
+		"Writer":    reflect.ValueOf(log.Writer),
+	},
+	"math": {
+		"Abs":             reflect.ValueOf(math.Abs),
+		"Acos":            reflect.ValueOf(math.Acos),
+		"Acosh":           reflect.ValueOf(math.Acosh),
+		"Asin":            reflect.ValueOf(math.Asin),
+		"Asinh":           reflect.ValueOf(math.Asinh),
+		"Atan":            reflect.ValueOf(math.Atan),
+		"Atan2":           reflect.ValueOf(math.Atan2),
+		"Atanh":           reflect.ValueOf(math.Atanh),
+		"Cbrt":            reflect.ValueOf(math.Cbrt),
+		"Ceil":            reflect.ValueOf(math.Ceil),
+		"Copysign":        reflect.ValueOf(math.Copysign),
+		"Cos":             reflect.ValueOf(math.Cos),
+		"Cosh":            reflect.ValueOf(math.Cosh),
+		"Dim":             reflect.ValueOf(math.Dim),
+		"Erf":             reflect.ValueOf(math.Erf),
+		"Erfc":            reflect.ValueOf(math.Erfc),
+		"Erfcinv":         reflect.ValueOf(math.Erfcinv),
+		"Erfinv":          reflect.ValueOf(math.Erfinv),
+		"Exp":             reflect.ValueOf(math.Exp),
+		"Exp2":            reflect.ValueOf(math.Exp2),
+		"Expm1":           reflect.ValueOf(math.Expm1),
+		"FMA":             reflect.ValueOf(math.FMA),
+		"Float32bits":     reflect.ValueOf(math.Float32bits),
+		"Float32frombits": reflect.ValueOf(math.Float32frombits),
+		"Float64bits":     reflect.ValueOf(math.Float64bits),
+		"Float64frombits": reflect.ValueOf(math.Float64frombits),
+		"Floor":           reflect.ValueOf(math.Floor),
+		"Frexp":           reflect.ValueOf(math.Frexp),
+		"Gamma":           reflect.ValueOf(math.Gamma),
+		"Hypot":           reflect.ValueOf(math.Hypot),
+		"Ilogb":           reflect.ValueOf(math.Ilogb),
+		"Inf":             reflect.ValueOf(math.Inf),
+		"IsInf":           reflect.ValueOf(math.IsInf),
+		"IsNaN":           reflect.ValueOf(math.IsNaN),
+		"J0":              reflect.ValueOf(math.J0),
+		"J1":              reflect.ValueOf(math.J1),
+		"Jn":              reflect.ValueOf(math.Jn),
+		"Ldexp":           reflect.ValueOf(math.Ldexp),
+		"Lgamma":          reflect.ValueOf(math.Lgamma),
+		"Log":             reflect.ValueOf(math.Log),
+		"Log10":           reflect.ValueOf(math.Log10),
+		"Log1p":           reflect.ValueOf(math.Log1p),
+		"Log2":            reflect.ValueOf(math.Log2),
+		"Logb":            reflect.ValueOf(math.Logb),
+		"Max":             reflect.ValueOf(math.Max),
+		"Min":             reflect.ValueOf(math.Min),
+		"Mod":             reflect.ValueOf(math.Mod),
+		"Modf":            reflect.ValueOf(math.Modf),
+		"NaN":             reflect.ValueOf(math.NaN),
+		"Nextafter":       reflect.ValueOf(math.Nextafter),
+		"Nextafter32":     reflect.ValueOf(math.Nextafter32),
+		"Pow":             reflect.ValueOf(math.Pow),
+		"Pow10":           reflect.ValueOf(math.Pow10),
+		"Remainder":       reflect.ValueOf(math.Remainder),
+		"Round":           reflect.ValueOf(math.Round),
+		"RoundToEven":     reflect.ValueOf(math.RoundToEven),
+		"Signbit":         reflect.ValueOf(math.Signbit),
+		"Sin":             reflect.ValueOf(math.Sin),
+		"Sincos":          reflect.ValueOf(math.Sincos),
+		"Sinh":            reflect.ValueOf(math.Sinh),
+		"Sqrt":            reflect.ValueOf(math.Sqrt),
+		"Tan":             reflect.ValueOf(math.Tan),
+		"Tanh":            reflect.ValueOf(math.Tanh),
+		"Trunc":           reflect.ValueOf(math.Trunc),
+		"Y0":              reflect.ValueOf(math.Y0),
+		"Y1":              reflect.ValueOf(math.Y1),
+		"Yn":              reflect.ValueOf(math.Yn),
+	},
+	"math/big": {
+		"Accuracy":     reflect.ValueOf((*math_big.Accuracy)(nil)),
+		"ErrNaN":       reflect.ValueOf((*math_big.ErrNaN)(nil)),
+		"Float":        reflect.ValueOf((*math_big.Float)(nil)),
+		"Int":          reflect.ValueOf((*math_big.Int)(nil)),
+		"Jacobi":       reflect.ValueOf(math_big.Jacobi),
+		"NewFloat":     reflect.ValueOf(math_big.NewFloat),
+		"NewInt":       reflect.ValueOf(math_big.NewInt),
+		"NewRat":       reflect.ValueOf(math_big.NewRat),
+		"ParseFloat":   reflect.ValueOf(math_big.ParseFloat),
+		"Rat":          reflect.ValueOf((*math_big.Rat)(nil)),
+		"RoundingMode": reflect.ValueOf((*math_big.RoundingMode)(nil)),
+		"Word":         reflect.ValueOf((*math_big.Word)(nil)),
+	},
+	"math/bits": {
+		"Add":             reflect.ValueOf(math_bits.Add),
+		"Add32":           reflect.ValueOf(math_bits.Add32),
+		"Add64":           reflect.ValueOf(math_bits.Add64),
+		"Div":             reflect.ValueOf(math_bits.Div),
+		"Div32":           reflect.ValueOf(math_bits.Div32),
+		"Div64":           reflect.ValueOf(math_bits.Div64),
+		"LeadingZeros":    reflect.ValueOf(math_bits.LeadingZeros),
+		"LeadingZeros16":  reflect.ValueOf(math_bits.LeadingZeros16),
+		"LeadingZeros32":  reflect.ValueOf(math_bits.LeadingZeros32),
+		"LeadingZeros64":  reflect.ValueOf(math_bits.LeadingZeros64),
+		"LeadingZeros8":   reflect.ValueOf(math_bits.LeadingZeros8),
+		"Len":             reflect.ValueOf(math_bits.Len),
+		"Len16":           reflect.ValueOf(math_bits.Len16),
+		"Len32":           reflect.ValueOf(math_bits.Len32),
+		"Len64":           reflect.ValueOf(math_bits.Len64),
+		"Len8":            reflect.ValueOf(math_bits.Len8),
+		"Mul":             reflect.ValueOf(math_bits.Mul),
+		"Mul32":           reflect.ValueOf(math_bits.Mul32),
+		"Mul64":           reflect.ValueOf(math_bits.Mul64),
+		"OnesCount":       reflect.ValueOf(math_bits.OnesCount),
+		"OnesCount16":     reflect.ValueOf(math_bits.OnesCount16),
+		"OnesCount32":     reflect.ValueOf(math_bits.OnesCount32),
+		"OnesCount64":     reflect.ValueOf(math_bits.OnesCount64),
+		"OnesCount8":      reflect.ValueOf(math_bits.OnesCount8),
+		"Rem":             reflect.ValueOf(math_bits.Rem),
+		"Rem32":           reflect.ValueOf(math_bits.Rem32),
+		"Rem64":           reflect.ValueOf(math_bits.Rem64),
+		"Reverse":         reflect.ValueOf(math_bits.Reverse),
+		"Reverse16":       reflect.ValueOf(math_bits.Reverse16),
+		"Reverse32":       reflect.ValueOf(math_bits.Reverse32),
+		"Reverse64":       reflect.ValueOf(math_bits.Reverse64),
+		"Reverse8":        reflect.ValueOf(math_bits.Reverse8),
+		"ReverseBytes":    reflect.ValueOf(math_bits.ReverseBytes),
+		"ReverseBytes16":  reflect.ValueOf(math_bits.ReverseBytes16),
+		"ReverseBytes32":  reflect.ValueOf(math_bits.ReverseBytes32),
+		"ReverseBytes64":  reflect.ValueOf(math_bits.ReverseBytes64),
+		"RotateLeft":      reflect.ValueOf(math_bits.RotateLeft),
+		"RotateLeft16":    reflect.ValueOf(math_bits.RotateLeft16),
+		"RotateLeft32":    reflect.ValueOf(math_bits.RotateLeft32),
+		"RotateLeft64":    reflect.ValueOf(math_bits.RotateLeft64),
+		"RotateLeft8":     reflect.ValueOf(math_bits.RotateLeft8),
+		"Sub":             reflect.ValueOf(math_bits.Sub),
+		"Sub32":           reflect.ValueOf(math_bits.Sub32),
+		"Sub64":           reflect.ValueOf(math_bits.Sub64),
+		"TrailingZeros":   reflect.ValueOf(math_bits.TrailingZeros),
+		"TrailingZeros16": reflect.ValueOf(math_bits.TrailingZeros16),
+		"TrailingZeros32": reflect.ValueOf(math_bits.TrailingZeros32),
+		"TrailingZeros64": reflect.ValueOf(math_bits.TrailingZeros64),
+		"TrailingZeros8":  reflect.ValueOf(math_bits.TrailingZeros8),
+	},
+	"math/cmplx": {
+		"Abs":   reflect.ValueOf(math_cmplx.Abs),
+		"Acos":  reflect.ValueOf(math_cmplx.Acos),
+		"Acosh": reflect.ValueOf(math_cmplx.Acosh),
+		"Asin":  reflect.ValueOf(math_cmplx.Asin),
+		"Asinh": reflect.ValueOf(math_cmplx.Asinh),
+		"Atan":  reflect.ValueOf(math_cmplx.Atan),
+		"Atanh": reflect.ValueOf(math_cmplx.Atanh),
+		"Conj":  reflect.ValueOf(math_cmplx.Conj),
+		"Cos":   reflect.ValueOf(math_cmplx.Cos),
+		"Cosh":  reflect.ValueOf(math_cmplx.Cosh),
+		"Cot":   reflect.ValueOf(math_cmplx.Cot),
+		"Exp":   reflect.ValueOf(math_cmplx.Exp),
+		"Inf":   reflect.ValueOf(math_cmplx.Inf),
+		"IsInf": reflect.ValueOf(math_cmplx.IsInf),
+		"IsNaN": reflect.ValueOf(math_cmplx.IsNaN),
+		"Log":   reflect.ValueOf(math_cmplx.Log),
+		"Log10": reflect.ValueOf(math_cmplx.Log10),
+		"NaN":   reflect.ValueOf(math_cmplx.NaN),
+		"Phase": reflect.ValueOf(math_cmplx.Phase),
+		"Polar": reflect.ValueOf(math_cmplx.Polar),
+		"Pow":   reflect.ValueOf(math_cmplx.Pow),
+		"Rect":  reflect.ValueOf(math_cmplx.Rect),
+		"Sin":   reflect.ValueOf(math_cmplx.Sin),
+		"Sinh":  reflect.ValueOf(math_cmplx.Sinh),
+		"Sqrt":  reflect.ValueOf(math_cmplx.Sqrt),
+		"Tan":   reflect.ValueOf(math_cmplx.Tan),
+		"Tanh":  reflect.ValueOf(math_cmplx.Tanh),
+	},
+	"math/rand": {
+		"ExpFloat64":  reflect.ValueOf(math_rand.ExpFloat64),
+		"Float32":     reflect.ValueOf(math_rand.Float32),
+		"Float64":     reflect.ValueOf(math_rand.Float64),
+		"Int":         reflect.ValueOf(math_rand.Int),
+		"Int31":       reflect.ValueOf(math_rand.Int31),
+		"Int31n":      reflect.ValueOf(math_rand.Int31n),
+		"Int63":       reflect.ValueOf(math_rand.Int63),
+		"Int63n":      reflect.ValueOf(math_rand.Int63n),
+		"Intn":        reflect.ValueOf(math_rand.Intn),
+		"New":         reflect.ValueOf(math_rand.New),
+		"NewSource":   reflect.ValueOf(math_rand.NewSource),
+		"NewZipf":     reflect.ValueOf(math_rand.NewZipf),
+		"NormFloat64": reflect.ValueOf(math_rand.NormFloat64),
+		"Perm":        reflect.ValueOf(math_rand.Perm),
+		"Rand":        reflect.ValueOf((*math_rand.Rand)(nil)),
+		"Read":        reflect.ValueOf(math_rand.Read),
+		"Seed":        reflect.ValueOf(math_rand.Seed),
+		"Shuffle":     reflect.ValueOf(math_rand.Shuffle),
+		"Source":      reflect.ValueOf((*math_rand.Source)(nil)),
+		"Source64":    reflect.ValueOf((*math_rand.Source64)(nil)),
+		"Uint32":      reflect.ValueOf(math_rand.Uint32),
+		"Uint64":      reflect.ValueOf(math_rand.Uint64),
+		"Zipf":        reflect.ValueOf((*math_rand.Zipf)(nil)),
+	},
+	"net/url": {
+		"Error":            reflect.ValueOf((*net_url.Error)(nil)),
+		"EscapeError":      reflect.ValueOf((*net_url.EscapeError)(nil)),
+		"InvalidHostError": reflect.ValueOf((*net_url.InvalidHostError)(nil)),
+		"JoinPath":         reflect.ValueOf(net_url.JoinPath),
+		"Parse":            reflect.ValueOf(net_url.Parse),
+		"ParseQuery":       reflect.ValueOf(net_url.ParseQuery),
+		"ParseRequestURI":  reflect.ValueOf(net_url.ParseRequestURI),
+		"PathEscape":       reflect.ValueOf(net_url.PathEscape),
+		"PathUnescape":     reflect.ValueOf(net_url.PathUnescape),
+		"QueryEscape":      reflect.ValueOf(net_url.QueryEscape),
+		"QueryUnescape":    reflect.ValueOf(net_url.QueryUnescape),
+		"URL":              reflect.ValueOf((*net_url.URL)(nil)),
+		"User":             reflect.ValueOf(net_url.User),
+		"UserPassword":     reflect.ValueOf(net_url.UserPassword),
+		"Userinfo":         reflect.ValueOf((*net_url.Userinfo)(nil)),
+		"Values":           reflect.ValueOf((*net_url.Values)(nil)),
+	},
+	"os": {
+		"Args":                reflect.ValueOf(&os.Args),
+		"Chdir":               reflect.ValueOf(os.Chdir),
+		"Chmod":               reflect.ValueOf(os.Chmod),
+		"Chown":               reflect.ValueOf(os.Chown),
+		"Chtimes":             reflect.ValueOf(os.Chtimes),
+		"Clearenv":            reflect.ValueOf(os.Clearenv),
+		"CopyFS":              reflect.ValueOf(os.CopyFS),
+		"Create":              reflect.ValueOf(os.Create),
+		"CreateTemp":          reflect.ValueOf(os.CreateTemp),
+		"DirEntry":            reflect.ValueOf((*os.DirEntry)(nil)),
+		"DirFS":               reflect.ValueOf(os.DirFS),
+		"Environ":             reflect.ValueOf(os.Environ),
+		"ErrClosed":           reflect.ValueOf(&os.ErrClosed),
+		"ErrDeadlineExceeded": reflect.ValueOf(&os.ErrDeadlineExceeded),
+		"ErrExist":            reflect.ValueOf(&os.ErrExist),
+		"ErrInvalid":          reflect.ValueOf(&os.ErrInvalid),
+		"ErrNoDeadline":       reflect.ValueOf(&os.ErrNoDeadline),
+		"ErrNoHandle":         reflect.ValueOf(&os.ErrNoHandle),
+		"ErrNotExist":         reflect.ValueOf(&os.ErrNotExist),
+		"ErrPermission":       reflect.ValueOf(&os.ErrPermission),
+		"ErrProcessDone":      reflect.ValueOf(&os.ErrProcessDone),
+		"Executable":          reflect.ValueOf(os.Executable),
+		"Exit":                reflect.ValueOf(os.Exit),
+		"Expand":              reflect.ValueOf(os.Expand),
+		"ExpandEnv":           reflect.ValueOf(os.ExpandEnv),
+		"File":                reflect.ValueOf((*os.File)(nil)),
+		"FileInfo":            reflect.ValueOf((*os.FileInfo)(nil)),
+		"FileMode":            reflect.ValueOf((*os.FileMode)(nil)),
+		"FindProcess":         reflect.ValueOf(os.FindProcess),
+		"Getegid":             reflect.ValueOf(os.Getegid),
+		"Getenv":              reflect.ValueOf(os.Getenv),
+		"Geteuid":             reflect.ValueOf(os.Geteuid),
+		"Getgid":              reflect.ValueOf(os.Getgid),
+		"Getgroups":           reflect.ValueOf(os.Getgroups),
+		"Getpagesize":         reflect.ValueOf(os.Getpagesize),
+		"Getpid":              reflect.ValueOf(os.Getpid),
+		"Getppid":             reflect.ValueOf(os.Getppid),
+		"Getuid":              reflect.ValueOf(os.Getuid),
+		"Getwd":               reflect.ValueOf(os.Getwd),
+		"Hostname":            reflect.ValueOf(os.Hostname),
+		"Interrupt":           reflect.ValueOf(&os.Interrupt),
+		"IsExist":             reflect.ValueOf(os.IsExist),
+		"IsNotExist":          reflect.ValueOf(os.IsNotExist),
+		"IsPathSeparator":     reflect.ValueOf(os.IsPathSeparator),
+		"IsPermission":        reflect.ValueOf(os.IsPermission),
+		"IsTimeout":           reflect.ValueOf(os.IsTimeout),
+		"Kill":                reflect.ValueOf(&os.Kill),
+		"Lchown":              reflect.ValueOf(os.Lchown),
+		"Link":                reflect.ValueOf(os.Link),
+		"LinkError":           reflect.ValueOf((*os.LinkError)(nil)),
+		"LookupEnv":           reflect.ValueOf(os.LookupEnv),
+		"Lstat":               reflect.ValueOf(os.Lstat),
+		"Mkdir":               reflect.ValueOf(os.Mkdir),
+		"MkdirAll":            reflect.ValueOf(os.MkdirAll),
+		"MkdirTemp":           reflect.ValueOf(os.MkdirTemp),
+		"NewFile":             reflect.ValueOf(os.NewFile),
+		"NewSyscallError":     reflect.ValueOf(os.NewSyscallError),
+		"Open":                reflect.ValueOf(os.Open),
+		"OpenFile":            reflect.ValueOf(os.OpenFile),
+		"OpenInRoot":          reflect.ValueOf(os.OpenInRoot),
+		"OpenRoot":            reflect.ValueOf(os.OpenRoot),
+		"PathError":           reflect.ValueOf((*os.PathError)(nil)),
+		"Pipe":                reflect.ValueOf(os.Pipe),
+		"ProcAttr":            reflect.ValueOf((*os.ProcAttr)(nil)),
+		"Process":             reflect.ValueOf((*os.Process)(nil)),
+		"ProcessState":        reflect.ValueOf((*os.ProcessState)(nil)),
+		"ReadDir":             reflect.ValueOf(os.ReadDir),
+		"ReadFile":            reflect.ValueOf(os.ReadFile),
+		"Readlink":            reflect.ValueOf(os.Readlink),
+		"Remove":              reflect.ValueOf(os.Remove),
+		"RemoveAll":           reflect.ValueOf(os.RemoveAll),
+		"Rename":              reflect.ValueOf(os.Rename),
+		"Root":                reflect.ValueOf((*os.Root)(nil)),
+		"SameFile":            reflect.ValueOf(os.SameFile),
+		"Setenv":              reflect.ValueOf(os.Setenv),
+		"Signal":              reflect.ValueOf((*os.Signal)(nil)),
+		"StartProcess":        reflect.ValueOf(os.StartProcess),
+		"Stat":                reflect.ValueOf(os.Stat),
+		"Stderr":              reflect.ValueOf(&os.Stderr),
+		"Stdin":               reflect.ValueOf(&os.Stdin),
+		"Stdout":              reflect.ValueOf(&os.Stdout),
+		"Symlink":             reflect.ValueOf(os.Symlink),
+		"SyscallError":        reflect.ValueOf((*os.SyscallError)(nil)),
+		"TempDir":             reflect.ValueOf(os.TempDir),
+		"Truncate":            reflect.ValueOf(os.Truncate),
+		"Unsetenv":            reflect.ValueOf(os.Unsetenv),
+		"UserCacheDir":        reflect.ValueOf(os.UserCacheDir),
+		"UserConfigDir":       reflect.ValueOf(os.UserConfigDir),
+		"UserHomeDir":         reflect.ValueOf(os.UserHomeDir),
+		"WriteFile":           reflect.ValueOf(os.WriteFile),
+	},
+	"path": {
+		"Base":          reflect.ValueOf(path.Base),
+		"Clean":         reflect.ValueOf(path.Clean),
+		"Dir":           reflect.ValueOf(path.Dir),
+		"ErrBadPattern": reflect.ValueOf(&path.ErrBadPattern),
+		"Ext":           reflect.ValueOf(path.Ext),
+		"IsAbs":         reflect.ValueOf(path.IsAbs),
+		"Join":          reflect.ValueOf(path.Join),
+		"Match":         reflect.ValueOf(path.Match),
+		"Split":         reflect.ValueOf(path.Split),
+	},
+	"path/filepath": {
+		"Abs":           reflect.ValueOf(path_filepath.Abs),
+		"Base":          reflect.ValueOf(path_filepath.Base),
+		"Clean":         reflect.ValueOf(path_filepath.Clean),
+		"Dir":           reflect.ValueOf(path_filepath.Dir),
+		"ErrBadPattern": reflect.ValueOf(&path_filepath.ErrBadPattern),
+		"EvalSymlinks":  reflect.ValueOf(path_filepath.EvalSymlinks),
+		"Ext":           reflect.ValueOf(path_filepath.Ext),
+		"FromSlash":     reflect.ValueOf(path_filepath.FromSlash),
+		"Glob":          reflect.ValueOf(path_filepath.Glob),
+		"HasPrefix":     reflect.ValueOf(path_filepath.HasPrefix),
+		"IsAbs":         reflect.ValueOf(path_filepath.IsAbs),
+		"IsLocal":       reflect.ValueOf(path_filepath.IsLocal),
+		"Join":          reflect.ValueOf(path_filepath.Join),
+		"Localize":      reflect.ValueOf(path_filepath.Localize),
+		"Match":         reflect.ValueOf(path_filepath.Match),
+		"Rel":           reflect.ValueOf(path_filepath.Rel),
+		"SkipAll":       reflect.ValueOf(&path_filepath.SkipAll),
+		"SkipDir":       reflect.ValueOf(&path_filepath.SkipDir),
+		"Split":         reflect.ValueOf(path_filepath.Split),
+		"SplitList":     reflect.ValueOf(path_filepath.SplitList),
+		"ToSlash":       reflect.ValueOf(path_filepath.ToSlash),
+		"VolumeName":    reflect.ValueOf(path_filepath.VolumeName),
+		"Walk":          reflect.ValueOf(path_filepath.Walk),
+		"WalkDir":       reflect.ValueOf(path_filepath.WalkDir),
+		"WalkFunc":      reflect.ValueOf((*path_filepath.WalkFunc)(nil)),
+	},
+	"regexp": {
+		"Compile":          reflect.ValueOf(regexp.Compile),
+		"CompilePOSIX":     reflect.ValueOf(regexp.CompilePOSIX),
+		"Match":            reflect.ValueOf(regexp.Match),
+		"MatchReader":      reflect.ValueOf(regexp.MatchReader),
+		"MatchString":      reflect.ValueOf(regexp.MatchString),
+		"MustCompile":      reflect.ValueOf(regexp.MustCompile),
+		"MustCompilePOSIX": reflect.ValueOf(regexp.MustCompilePOSIX),
+		"QuoteMeta":        reflect.ValueOf(regexp.QuoteMeta),
+		"Regexp":           reflect.ValueOf((*regexp.Regexp)(nil)),
+	},
+	"sort": {
+		"Find":              reflect.ValueOf(sort.Find),
+		"Float64Slice":      reflect.ValueOf((*sort.Float64Slice)(nil)),
+		"Float64s":          reflect.ValueOf(sort.Float64s),
+		"Float64sAreSorted": reflect.ValueOf(sort.Float64sAreSorted),
+		"IntSlice":          reflect.ValueOf((*sort.IntSlice)(nil)),
+		"Interface":         reflect.ValueOf((*sort.Interface)(nil)),
+		"Ints":              reflect.ValueOf(sort.Ints),
+		"IntsAreSorted":     reflect.ValueOf(sort.IntsAreSorted),
+		"IsSorted":          reflect.ValueOf(sort.IsSorted),
+		"Reverse":           reflect.ValueOf(sort.Reverse),
+		"Search":            reflect.ValueOf(sort.Search),
+		"SearchFloat64s":    reflect.ValueOf(sort.SearchFloat64s),
+		"SearchInts":        reflect.ValueOf(sort.SearchInts),
+		"SearchStrings":     reflect.ValueOf(sort.SearchStrings),
+		"Slice":             reflect.ValueOf(sort.Slice),
+		"SliceIsSorted":     reflect.ValueOf(sort.SliceIsSorted),
+		"SliceStable":       reflect.ValueOf(sort.SliceStable),
+		"Sort":              reflect.ValueOf(sort.Sort),
+		"Stable":            reflect.ValueOf(sort.Stable),
+		"StringSlice":       reflect.ValueOf((*sort.StringSlice)(nil)),
+		"Strings":           reflect.ValueOf(sort.Strings),
+		"StringsAreSorted":  reflect.ValueOf(sort.StringsAreSorted),
+	},
+	"strconv": {
+		"AppendBool":               reflect.ValueOf(strconv.AppendBool),
+		"AppendFloat":              reflect.ValueOf(strconv.AppendFloat),
+		"AppendInt":                reflect.ValueOf(strconv.AppendInt),
+		"AppendQuote":              reflect.ValueOf(strconv.AppendQuote),
+		"AppendQuoteRune":          reflect.ValueOf(strconv.AppendQuoteRune),
+		"AppendQuoteRuneToASCII":   reflect.ValueOf(strconv.AppendQuoteRuneToASCII),
+		"AppendQuoteRuneToGraphic": reflect.ValueOf(strconv.AppendQuoteRuneToGraphic),
+		"AppendQuoteToASCII":       reflect.ValueOf(strconv.AppendQuoteToASCII),
+		"AppendQuoteToGraphic":     reflect.ValueOf(strconv.AppendQuoteToGraphic),
+		"AppendUint":               reflect.ValueOf(strconv.AppendUint),
+		"Atoi":                     reflect.ValueOf(strconv.Atoi),
+		"CanBackquote":             reflect.ValueOf(strconv.CanBackquote),
+		"ErrRange":                 reflect.ValueOf(&strconv.ErrRange),
+		"ErrSyntax":                reflect.ValueOf(&strconv.ErrSyntax),
+		"FormatBool":               reflect.ValueOf(strconv.FormatBool),
+		"FormatComplex":            reflect.ValueOf(strconv.FormatComplex),
+		"FormatFloat":              reflect.ValueOf(strconv.FormatFloat),
+		"FormatInt":                reflect.ValueOf(strconv.FormatInt),
+		"FormatUint":               reflect.ValueOf(strconv.FormatUint),
+		"IsGraphic":                reflect.ValueOf(strconv.IsGraphic),
+		"IsPrint":                  reflect.ValueOf(strconv.IsPrint),
+		"Itoa":                     reflect.ValueOf(strconv.Itoa),
+		"NumError":                 reflect.ValueOf((*strconv.NumError)(nil)),
+		"ParseBool":                reflect.ValueOf(strconv.ParseBool),
+		"ParseComplex":             reflect.ValueOf(strconv.ParseComplex),
+		"ParseFloat":               reflect.ValueOf(strconv.ParseFloat),
+		"ParseInt":                 reflect.ValueOf(strconv.ParseInt),
+		"ParseUint":                reflect.ValueOf(strconv.ParseUint),
+		"Quote":                    reflect.ValueOf(strconv.Quote),
+		"QuoteRune":                reflect.ValueOf(strconv.QuoteRune),
+		"QuoteRuneToASCII":         reflect.ValueOf(strconv.QuoteRuneToASCII),
+		"QuoteRuneToGraphic":       reflect.ValueOf(strconv.QuoteRuneToGraphic),
+		"QuoteToASCII":             reflect.ValueOf(strconv.QuoteToASCII),
+		"QuoteToGraphic":           reflect.ValueOf(strconv.QuoteToGraphic),
+		"QuotedPrefix":             reflect.ValueOf(strconv.QuotedPrefix),
+		"Unquote":                  reflect.ValueOf(strconv.Unquote),
+		"UnquoteChar":              reflect.ValueOf(strconv.UnquoteChar),
+	},
+	"strings": {
+		"Builder":        reflect.ValueOf((*strings.Builder)(nil)),
+		"Clone":          reflect.ValueOf(strings.Clone),
+		"Compare":        reflect.ValueOf(strings.Compare),
+		"Contains":       reflect.ValueOf(strings.Contains),
+		"ContainsAny":    reflect.ValueOf(strings.ContainsAny),
+		"ContainsFunc":   reflect.ValueOf(strings.ContainsFunc),
+		"ContainsRune":   reflect.ValueOf(strings.ContainsRune),
+		"Count":          reflect.ValueOf(strings.Count),
+		"Cut":            reflect.ValueOf(strings.Cut),
+		"CutLast":        reflect.ValueOf(strings.CutLast),
+		"CutPrefix":      reflect.ValueOf(strings.CutPrefix),
+		"CutSuffix":      reflect.ValueOf(strings.CutSuffix),
+		"EqualFold":      reflect.ValueOf(strings.EqualFold),
+		"Fields":         reflect.ValueOf(strings.Fields),
+		"FieldsFunc":     reflect.ValueOf(strings.FieldsFunc),
+		"FieldsFuncSeq":  reflect.ValueOf(strings.FieldsFuncSeq),
+		"FieldsSeq":      reflect.ValueOf(strings.FieldsSeq),
+		"HasPrefix":      reflect.ValueOf(strings.HasPrefix),
+		"HasSuffix":      reflect.ValueOf(strings.HasSuffix),
+		"Index":          reflect.ValueOf(strings.Index),
+		"IndexAny":       reflect.ValueOf(strings.IndexAny),
+		"IndexByte":      reflect.ValueOf(strings.IndexByte),
+		"IndexFunc":      reflect.ValueOf(strings.IndexFunc),
+		"IndexRune":      reflect.ValueOf(strings.IndexRune),
+		"Join":           reflect.ValueOf(strings.Join),
+		"LastIndex":      reflect.ValueOf(strings.LastIndex),
+		"LastIndexAny":   reflect.ValueOf(strings.LastIndexAny),
+		"LastIndexByte":  reflect.ValueOf(strings.LastIndexByte),
+		"LastIndexFunc":  reflect.ValueOf(strings.LastIndexFunc),
+		"Lines":          reflect.ValueOf(strings.Lines),
+		"Map":            reflect.ValueOf(strings.Map),
+		"NewReader":      reflect.ValueOf(strings.NewReader),
+		"NewReplacer":    reflect.ValueOf(strings.NewReplacer),
+		"Reader":         reflect.ValueOf((*strings.Reader)(nil)),
+		"Repeat":         reflect.ValueOf(strings.Repeat),
+		"Replace":        reflect.ValueOf(strings.Replace),
+		"ReplaceAll":     reflect.ValueOf(strings.ReplaceAll),
+		"Replacer":       reflect.ValueOf((*strings.Replacer)(nil)),
+		"Split":          reflect.ValueOf(strings.Split),
+		"SplitAfter":     reflect.ValueOf(strings.SplitAfter),
+		"SplitAfterN":    reflect.ValueOf(strings.SplitAfterN),
+		"SplitAfterSeq":  reflect.ValueOf(strings.SplitAfterSeq),
+		"SplitN":         reflect.ValueOf(strings.SplitN),
+		"SplitSeq":       reflect.ValueOf(strings.SplitSeq),
+		"Title":          reflect.ValueOf(strings.Title),
+		"ToLower":        reflect.ValueOf(strings.ToLower),
+		"ToLowerSpecial": reflect.ValueOf(strings.ToLowerSpecial),
+		"ToTitle":        reflect.ValueOf(strings.ToTitle),
+		"ToTitleSpecial": reflect.ValueOf(strings.ToTitleSpecial),
+		"ToUpper":        reflect.ValueOf(strings.ToUpper),
+		"ToUpperSpecial": reflect.ValueOf(strings.ToUpperSpecial),
+		"ToValidUTF8":    reflect.ValueOf(strings.ToValidUTF8),
+		"Trim":           reflect.ValueOf(strings.Trim),
+		"TrimFunc":       reflect.ValueOf(strings.TrimFunc),
+		"TrimLeft":       reflect.ValueOf(strings.TrimLeft),
+		"TrimLeftFunc":   reflect.ValueOf(strings.TrimLeftFunc),
+		"TrimPrefix":     reflect.ValueOf(strings.TrimPrefix),
+		"TrimRight":      reflect.ValueOf(strings.TrimRight),
+		"TrimRightFunc":  reflect.ValueOf(strings.TrimRightFunc),
+		"TrimSpace":      reflect.ValueOf(strings.TrimSpace),
+		"TrimSuffix":     reflect.ValueOf(strings.TrimSuffix),
+	},
+	"sync": {
+		"Cond":      reflect.ValueOf((*sync.Cond)(nil)),
+		"Locker":    reflect.ValueOf((*sync.Locker)(nil)),
+		"Map":       reflect.ValueOf((*sync.Map)(nil)),
+		"Mutex":     reflect.ValueOf((*sync.Mutex)(nil)),
+		"NewCond":   reflect.ValueOf(sync.NewCond),
+		"Once":      reflect.ValueOf((*sync.Once)(nil)),
+		"OnceFunc":  reflect.ValueOf(sync.OnceFunc),
+		"Pool":      reflect.ValueOf((*sync.Pool)(nil)),
+		"RWMutex":   reflect.ValueOf((*sync.RWMutex)(nil)),
+		"WaitGroup": reflect.ValueOf((*sync.WaitGroup)(nil)),
+	},
+	"sync/atomic": {
+		"AddInt32":              reflect.ValueOf(sync_atomic.AddInt32),
+		"AddInt64":              reflect.ValueOf(sync_atomic.AddInt64),
+		"AddUint32":             reflect.ValueOf(sync_atomic.AddUint32),
+		"AddUint64":             reflect.ValueOf(sync_atomic.AddUint64),
+		"AddUintptr":            reflect.ValueOf(sync_atomic.AddUintptr),
+		"AndInt32":              reflect.ValueOf(sync_atomic.AndInt32),
+		"AndInt64":              reflect.ValueOf(sync_atomic.AndInt64),
+		"AndUint32":             reflect.ValueOf(sync_atomic.AndUint32),
+		"AndUint64":             reflect.ValueOf(sync_atomic.AndUint64),
+		"AndUintptr":            reflect.ValueOf(sync_atomic.AndUintptr),
+		"Bool":                  reflect.ValueOf((*sync_atomic.Bool)(nil)),
+		"CompareAndSwapInt32":   reflect.ValueOf(sync_atomic.CompareAndSwapInt32),
+		"CompareAndSwapInt64":   reflect.ValueOf(sync_atomic.CompareAndSwapInt64),
+		"CompareAndSwapPointer": reflect.ValueOf(sync_atomic.CompareAndSwapPointer),
+		"CompareAndSwapUint32":  reflect.ValueOf(sync_atomic.CompareAndSwapUint32),
+		"CompareAndSwapUint64":  reflect.ValueOf(sync_atomic.CompareAndSwapUint64),
+		"CompareAndSwapUintptr": reflect.ValueOf(sync_atomic.CompareAndSwapUintptr),
+		"Int32":                 reflect.ValueOf((*sync_atomic.Int32)(nil)),
+		"Int64":                 reflect.ValueOf((*sync_atomic.Int64)(nil)),
+		"LoadInt32":             reflect.ValueOf(sync_atomic.LoadInt32),
+		"LoadInt64":             reflect.ValueOf(sync_atomic.LoadInt64),
+		"LoadPointer":           reflect.ValueOf(sync_atomic.LoadPointer),
+		"LoadUint32":            reflect.ValueOf(sync_atomic.LoadUint32),
+		"LoadUint64":            reflect.ValueOf(sync_atomic.LoadUint64),
+		"LoadUintptr":           reflect.ValueOf(sync_atomic.LoadUintptr),
+		"OrInt32":               reflect.ValueOf(sync_atomic.OrInt32),
+		"OrInt64":               reflect.ValueOf(sync_atomic.OrInt64),
+		"OrUint32":              reflect.ValueOf(sync_atomic.OrUint32),
+		"OrUint64":              reflect.ValueOf(sync_atomic.OrUint64),
+		"OrUintptr":             reflect.ValueOf(sync_atomic.OrUintptr),
+		"StoreInt32":            reflect.ValueOf(sync_atomic.StoreInt32),
+		"StoreInt64":            reflect.ValueOf(sync_atomic.StoreInt64),
+		"StorePointer":          reflect.ValueOf(sync_atomic.StorePointer),
+		"StoreUint32":           reflect.ValueOf(sync_atomic.StoreUint32),
+		"StoreUint64":           reflect.ValueOf(sync_atomic.StoreUint64),
+		"StoreUintptr":          reflect.ValueOf(sync_atomic.StoreUintptr),
+		"SwapInt32":             reflect.ValueOf(sync_atomic.SwapInt32),
+		"SwapInt64":             reflect.ValueOf(sync_atomic.SwapInt64),
+		"SwapPointer":           reflect.ValueOf(sync_atomic.SwapPointer),
+		"SwapUint32":            reflect.ValueOf(sync_atomic.SwapUint32),
+		"SwapUint64":            reflect.ValueOf(sync_atomic.SwapUint64),
+		"SwapUintptr":           reflect.ValueOf(sync_atomic.SwapUintptr),
+		"Uint32":                reflect.ValueOf((*sync_atomic.Uint32)(nil)),
+		"Uint64":                reflect.ValueOf((*sync_atomic.Uint64)(nil)),
+		"Uintptr":               reflect.ValueOf((*sync_atomic.Uintptr)(nil)),
+		"Value":                 reflect.ValueOf((*sync_atomic.Value)(nil)),
+	},
+	"text/tabwriter": {
+		"NewWriter": reflect.ValueOf(text_tabwriter.NewWriter),
+		"Writer":    reflect.ValueOf((*text_tabwriter.Writer)(nil)),
+	},
+	"text/template": {
+		"ExecError":        reflect.ValueOf((*text_template.ExecError)(nil)),
+		"FuncMap":          reflect.ValueOf((*text_template.FuncMap)(nil)),
+		"HTMLEscape":       reflect.ValueOf(text_template.HTMLEscape),
+		"HTMLEscapeString": reflect.ValueOf(text_template.HTMLEscapeString),
+		"HTMLEscaper":      reflect.ValueOf(text_template.HTMLEscaper),
+		"IsTrue":           reflect.ValueOf(text_template.IsTrue),
+		"JSEscape":         reflect.ValueOf(text_template.JSEscape),
+		"JSEscapeString":   reflect.ValueOf(text_template.JSEscapeString),
+		"JSEscaper":        reflect.ValueOf(text_template.JSEscaper),
+		"Must":             reflect.ValueOf(text_template.Must),
+		"New":              reflect.ValueOf(text_template.New),
+		"ParseFS":          reflect.ValueOf(text_template.ParseFS),
+		"ParseFiles":       reflect.ValueOf(text_template.ParseFiles),
+		"ParseGlob":        reflect.ValueOf(text_template.ParseGlob),
+		"Template":         reflect.ValueOf((*text_template.Template)(nil)),
+		"URLQueryEscaper":  reflect.ValueOf(text_template.URLQueryEscaper),
+	},
+	"time": {
+		"After":                  reflect.ValueOf(time.After),
+		"AfterFunc":              reflect.ValueOf(time.AfterFunc),
+		"Date":                   reflect.ValueOf(time.Date),
+		"Duration":               reflect.ValueOf((*time.Duration)(nil)),
+		"FixedZone":              reflect.ValueOf(time.FixedZone),
+		"LoadLocation":           reflect.ValueOf(time.LoadLocation),
+		"LoadLocationFromTZData": reflect.ValueOf(time.LoadLocationFromTZData),
+		"Local":                  reflect.ValueOf(&time.Local),
+		"Location":               reflect.ValueOf((*time.Location)(nil)),
+		"Month":                  reflect.ValueOf((*time.Month)(nil)),
+		"NewTicker":              reflect.ValueOf(time.NewTicker),
+		"NewTimer":               reflect.ValueOf(time.NewTimer),
+		"Now":                    reflect.ValueOf(time.Now),
+		"Parse":                  reflect.ValueOf(time.Parse),
+		"ParseDuration":          reflect.ValueOf(time.ParseDuration),
+		"ParseError":             reflect.ValueOf((*time.ParseError)(nil)),
+		"ParseInLocation":        reflect.ValueOf(time.ParseInLocation),
+		"Since":                  reflect.ValueOf(time.Since),
+		"Sleep":                  reflect.ValueOf(time.Sleep),
+		"Tick":                   reflect.ValueOf(time.Tick),
+		"Ticker":                 reflect.ValueOf((*time.Ticker)(nil)),
+		"Time":                   reflect.ValueOf((*time.Time)(nil)),
+		"Timer":                  reflect.ValueOf((*time.Timer)(nil)),
+		"UTC":                    reflect.ValueOf(&time.UTC),
+		"Unix":                   reflect.ValueOf(time.Unix),
+		"UnixMicro":              reflect.ValueOf(time.UnixMicro),
+		"UnixMilli":              reflect.ValueOf(time.UnixMilli),
+		"Until":                  reflect.ValueOf(time.Until),
+		"Weekday":                reflect.ValueOf((*time.Weekday)(nil)),
+	},
+	"unicode": {
+		"ASCII_Hex_Digit":                    reflect.ValueOf(&unicode.ASCII_Hex_Digit),
+		"Adlam":                              reflect.ValueOf(&unicode.Adlam),
+		"Ahom":                               reflect.ValueOf(&unicode.Ahom),
+		"Anatolian_Hieroglyphs":              reflect.ValueOf(&unicode.Anatolian_Hieroglyphs),
+		"Arabic":                             reflect.ValueOf(&unicode.Arabic),
+		"Armenian":                           reflect.ValueOf(&unicode.Armenian),
+		"Avestan":                            reflect.ValueOf(&unicode.Avestan),
+		"AzeriCase":                          reflect.ValueOf(&unicode.AzeriCase),
+		"Balinese":                           reflect.ValueOf(&unicode.Balinese),
+		"Bamum":                              reflect.ValueOf(&unicode.Bamum),
+		"Bassa_Vah":                          reflect.ValueOf(&unicode.Bassa_Vah),
+		"Batak":                              reflect.ValueOf(&unicode.Batak),
+		"Bengali":                            reflect.ValueOf(&unicode.Bengali),
+		"Beria_Erfe":                         reflect.ValueOf(&unicode.Beria_Erfe),
+		"Bhaiksuki":                          reflect.ValueOf(&unicode.Bhaiksuki),
+		"Bidi_Control":                       reflect.ValueOf(&unicode.Bidi_Control),
+		"Bopomofo":                           reflect.ValueOf(&unicode.Bopomofo),
+		"Brahmi":                             reflect.ValueOf(&unicode.Brahmi),
+		"Braille":                            reflect.ValueOf(&unicode.Braille),
+		"Buginese":                           reflect.ValueOf(&unicode.Buginese),
+		"Buhid":                              reflect.ValueOf(&unicode.Buhid),
+		"C":                                  reflect.ValueOf(&unicode.C),
+		"Canadian_Aboriginal":                reflect.ValueOf(&unicode.Canadian_Aboriginal),
+		"Carian":                             reflect.ValueOf(&unicode.Carian),
+		"CaseRange":                          reflect.ValueOf((*unicode.CaseRange)(nil)),
+		"CaseRanges":                         reflect.ValueOf(&unicode.CaseRanges),
+		"Categories":                         reflect.ValueOf(&unicode.Categories),
+		"CategoryAliases":                    reflect.ValueOf(&unicode.CategoryAliases),
+		"Caucasian_Albanian":                 reflect.ValueOf(&unicode.Caucasian_Albanian),
+		"Cc":                                 reflect.ValueOf(&unicode.Cc),
+		"Cf":                                 reflect.ValueOf(&unicode.Cf),
+		"Chakma":                             reflect.ValueOf(&unicode.Chakma),
+		"Cham":                               reflect.ValueOf(&unicode.Cham),
+		"Cherokee":                           reflect.ValueOf(&unicode.Cherokee),
+		"Chorasmian":                         reflect.ValueOf(&unicode.Chorasmian),
+		"Cn":                                 reflect.ValueOf(&unicode.Cn),
+		"Co":                                 reflect.ValueOf(&unicode.Co),
+		"Common":                             reflect.ValueOf(&unicode.Common),
+		"Coptic":                             reflect.ValueOf(&unicode.Coptic),
+		"Cs":                                 reflect.ValueOf(&unicode.Cs),
+		"Cuneiform":                          reflect.ValueOf(&unicode.Cuneiform),
+		"Cypriot":                            reflect.ValueOf(&unicode.Cypriot),
+		"Cypro_Minoan":                       reflect.ValueOf(&unicode.Cypro_Minoan),
+		"Cyrillic":                           reflect.ValueOf(&unicode.Cyrillic),
+		"Dash":                               reflect.ValueOf(&unicode.Dash),
+		"Deprecated":                         reflect.ValueOf(&unicode.Deprecated),
+		"Deseret":                            reflect.ValueOf(&unicode.Deseret),
+		"Devanagari":                         reflect.ValueOf(&unicode.Devanagari),
+		"Diacritic":                          reflect.ValueOf(&unicode.Diacritic),
+		"Digit":                              reflect.ValueOf(&unicode.Digit),
+		"Dives_Akuru":                        reflect.ValueOf(&unicode.Dives_Akuru),
+		"Dogra":                              reflect.ValueOf(&unicode.Dogra),
+		"Duployan":                           reflect.ValueOf(&unicode.Duployan),
+		"Egyptian_Hieroglyphs":               reflect.ValueOf(&unicode.Egyptian_Hieroglyphs),
+		"Elbasan":                            reflect.ValueOf(&unicode.Elbasan),
+		"Elymaic":                            reflect.ValueOf(&unicode.Elymaic),
+		"Ethiopic":                           reflect.ValueOf(&unicode.Ethiopic),
+		"Extender":                           reflect.ValueOf(&unicode.Extender),
+		"FoldCategory":                       reflect.ValueOf(&unicode.FoldCategory),
+		"FoldScript":                         reflect.ValueOf(&unicode.FoldScript),
+		"Garay":                              reflect.ValueOf(&unicode.Garay),
+		"Georgian":                           reflect.ValueOf(&unicode.Georgian),
+		"Glagolitic":                         reflect.ValueOf(&unicode.Glagolitic),
+		"Gothic":                             reflect.ValueOf(&unicode.Gothic),
+		"Grantha":                            reflect.ValueOf(&unicode.Grantha),
+		"GraphicRanges":                      reflect.ValueOf(&unicode.GraphicRanges),
+		"Greek":                              reflect.ValueOf(&unicode.Greek),
+		"Gujarati":                           reflect.ValueOf(&unicode.Gujarati),
+		"Gunjala_Gondi":                      reflect.ValueOf(&unicode.Gunjala_Gondi),
+		"Gurmukhi":                           reflect.ValueOf(&unicode.Gurmukhi),
+		"Gurung_Khema":                       reflect.ValueOf(&unicode.Gurung_Khema),
+		"Han":                                reflect.ValueOf(&unicode.Han),
+		"Hangul":                             reflect.ValueOf(&unicode.Hangul),
+		"Hanifi_Rohingya":                    reflect.ValueOf(&unicode.Hanifi_Rohingya),
+		"Hanunoo":                            reflect.ValueOf(&unicode.Hanunoo),
+		"Hatran":                             reflect.ValueOf(&unicode.Hatran),
+		"Hebrew":                             reflect.ValueOf(&unicode.Hebrew),
+		"Hex_Digit":                          reflect.ValueOf(&unicode.Hex_Digit),
+		"Hiragana":                           reflect.ValueOf(&unicode.Hiragana),
+		"Hyphen":                             reflect.ValueOf(&unicode.Hyphen),
+		"IDS_Binary_Operator":                reflect.ValueOf(&unicode.IDS_Binary_Operator),
+		"IDS_Trinary_Operator":               reflect.ValueOf(&unicode.IDS_Trinary_Operator),
+		"IDS_Unary_Operator":                 reflect.ValueOf(&unicode.IDS_Unary_Operator),
+		"ID_Compat_Math_Continue":            reflect.ValueOf(&unicode.ID_Compat_Math_Continue),
+		"ID_Compat_Math_Start":               reflect.ValueOf(&unicode.ID_Compat_Math_Start),
+		"Ideographic":                        reflect.ValueOf(&unicode.Ideographic),
+		"Imperial_Aramaic":                   reflect.ValueOf(&unicode.Imperial_Aramaic),
+		"In":                                 reflect.ValueOf(unicode.In),
+		"Inherited":                          reflect.ValueOf(&unicode.Inherited),
+		"Inscriptional_Pahlavi":              reflect.ValueOf(&unicode.Inscriptional_Pahlavi),
+		"Inscriptional_Parthian":             reflect.ValueOf(&unicode.Inscriptional_Parthian),
+		"Is":                                 reflect.ValueOf(unicode.Is),
+		"IsControl":                          reflect.ValueOf(unicode.IsControl),
+		"IsDigit":                            reflect.ValueOf(unicode.IsDigit),
+		"IsGraphic":                          reflect.ValueOf(unicode.IsGraphic),
+		"IsLetter":                           reflect.ValueOf(unicode.IsLetter),
+		"IsLower":                            reflect.ValueOf(unicode.IsLower),
+		"IsMark":                             reflect.ValueOf(unicode.IsMark),
+		"IsNumber":                           reflect.ValueOf(unicode.IsNumber),
+		"IsOneOf":                            reflect.ValueOf(unicode.IsOneOf),
+		"IsPrint":                            reflect.ValueOf(unicode.IsPrint),
+		"IsPunct":                            reflect.ValueOf(unicode.IsPunct),
+		"IsSpace":                            reflect.ValueOf(unicode.IsSpace),
+		"IsSymbol":                           reflect.ValueOf(unicode.IsSymbol),
+		"IsTitle":                            reflect.ValueOf(unicode.IsTitle),
+		"IsUpper":                            reflect.ValueOf(unicode.IsUpper),
+		"Javanese":                           reflect.ValueOf(&unicode.Javanese),
+		"Join_Control":                       reflect.ValueOf(&unicode.Join_Control),
+		"Kaithi":                             reflect.ValueOf(&unicode.Kaithi),
+		"Kannada":                            reflect.ValueOf(&unicode.Kannada),
+		"Katakana":                           reflect.ValueOf(&unicode.Katakana),
+		"Kawi":                               reflect.ValueOf(&unicode.Kawi),
+		"Kayah_Li":                           reflect.ValueOf(&unicode.Kayah_Li),
+		"Kharoshthi":                         reflect.ValueOf(&unicode.Kharoshthi),
+		"Khitan_Small_Script":                reflect.ValueOf(&unicode.Khitan_Small_Script),
+		"Khmer":                              reflect.ValueOf(&unicode.Khmer),
+		"Khojki":                             reflect.ValueOf(&unicode.Khojki),
+		"Khudawadi":                          reflect.ValueOf(&unicode.Khudawadi),
+		"Kirat_Rai":                          reflect.ValueOf(&unicode.Kirat_Rai),
+		"L":                                  reflect.ValueOf(&unicode.L),
+		"LC":                                 reflect.ValueOf(&unicode.LC),
+		"Lao":                                reflect.ValueOf(&unicode.Lao),
+		"Latin":                              reflect.ValueOf(&unicode.Latin),
+		"Lepcha":                             reflect.ValueOf(&unicode.Lepcha),
+		"Letter":                             reflect.ValueOf(&unicode.Letter),
+		"Limbu":                              reflect.ValueOf(&unicode.Limbu),
+		"Linear_A":                           reflect.ValueOf(&unicode.Linear_A),
+		"Linear_B":                           reflect.ValueOf(&unicode.Linear_B),
+		"Lisu":                               reflect.ValueOf(&unicode.Lisu),
+		"Ll":                                 reflect.ValueOf(&unicode.Ll),
+		"Lm":                                 reflect.ValueOf(&unicode.Lm),
+		"Lo":                                 reflect.ValueOf(&unicode.Lo),
+		"Logical_Order_Exception":            reflect.ValueOf(&unicode.Logical_Order_Exception),
+		"Lower":                              reflect.ValueOf(&unicode.Lower),
+		"Lt":                                 reflect.ValueOf(&unicode.Lt),
+		"Lu":                                 reflect.ValueOf(&unicode.Lu),
+		"Lycian":                             reflect.ValueOf(&unicode.Lycian),
+		"Lydian":                             reflect.ValueOf(&unicode.Lydian),
+		"M":                                  reflect.ValueOf(&unicode.M),
+		"Mahajani":                           reflect.ValueOf(&unicode.Mahajani),
+		"Makasar":                            reflect.ValueOf(&unicode.Makasar),
+		"Malayalam":                          reflect.ValueOf(&unicode.Malayalam),
+		"Mandaic":                            reflect.ValueOf(&unicode.Mandaic),
+		"Manichaean":                         reflect.ValueOf(&unicode.Manichaean),
+		"Marchen":                            reflect.ValueOf(&unicode.Marchen),
+		"Mark":                               reflect.ValueOf(&unicode.Mark),
+		"Masaram_Gondi":                      reflect.ValueOf(&unicode.Masaram_Gondi),
+		"Mc":                                 reflect.ValueOf(&unicode.Mc),
+		"Me":                                 reflect.ValueOf(&unicode.Me),
+		"Medefaidrin":                        reflect.ValueOf(&unicode.Medefaidrin),
+		"Meetei_Mayek":                       reflect.ValueOf(&unicode.Meetei_Mayek),
+		"Mende_Kikakui":                      reflect.ValueOf(&unicode.Mende_Kikakui),
+		"Meroitic_Cursive":                   reflect.ValueOf(&unicode.Meroitic_Cursive),
+		"Meroitic_Hieroglyphs":               reflect.ValueOf(&unicode.Meroitic_Hieroglyphs),
+		"Miao":                               reflect.ValueOf(&unicode.Miao),
+		"Mn":                                 reflect.ValueOf(&unicode.Mn),
+		"Modi":                               reflect.ValueOf(&unicode.Modi),
+		"Modifier_Combining_Mark":            reflect.ValueOf(&unicode.Modifier_Combining_Mark),
+		"Mongolian":                          reflect.ValueOf(&unicode.Mongolian),
+		"Mro":                                reflect.ValueOf(&unicode.Mro),
+		"Multani":                            reflect.ValueOf(&unicode.Multani),
+		"Myanmar":                            reflect.ValueOf(&unicode.Myanmar),
+		"N":                                  reflect.ValueOf(&unicode.N),
+		"Nabataean":                          reflect.ValueOf(&unicode.Nabataean),
+		"Nag_Mundari":                        reflect.ValueOf(&unicode.Nag_Mundari),
+		"Nandinagari":                        reflect.ValueOf(&unicode.Nandinagari),
+		"Nd":                                 reflect.ValueOf(&unicode.Nd),
+		"New_Tai_Lue":                        reflect.ValueOf(&unicode.New_Tai_Lue),
+		"Newa":                               reflect.ValueOf(&unicode.Newa),
+		"Nko":                                reflect.ValueOf(&unicode.Nko),
+		"Nl":                                 reflect.ValueOf(&unicode.Nl),
+		"No":                                 reflect.ValueOf(&unicode.No),
+		"Noncharacter_Code_Point":            reflect.ValueOf(&unicode.Noncharacter_Code_Point),
+		"Number":                             reflect.ValueOf(&unicode.Number),
+		"Nushu":                              reflect.ValueOf(&unicode.Nushu),
+		"Nyiakeng_Puachue_Hmong":             reflect.ValueOf(&unicode.Nyiakeng_Puachue_Hmong),
+		"Ogham":                              reflect.ValueOf(&unicode.Ogham),
+		"Ol_Chiki":                           reflect.ValueOf(&unicode.Ol_Chiki),
+		"Ol_Onal":                            reflect.ValueOf(&unicode.Ol_Onal),
+		"Old_Hungarian":                      reflect.ValueOf(&unicode.Old_Hungarian),
+		"Old_Italic":                         reflect.ValueOf(&unicode.Old_Italic),
+		"Old_North_Arabian":                  reflect.ValueOf(&unicode.Old_North_Arabian),
+		"Old_Permic":                         reflect.ValueOf(&unicode.Old_Permic),
+		"Old_Persian":                        reflect.ValueOf(&unicode.Old_Persian),
+		"Old_Sogdian":                        reflect.ValueOf(&unicode.Old_Sogdian),
+		"Old_South_Arabian":                  reflect.ValueOf(&unicode.Old_South_Arabian),
+		"Old_Turkic":                         reflect.ValueOf(&unicode.Old_Turkic),
+		"Old_Uyghur":                         reflect.ValueOf(&unicode.Old_Uyghur),
+		"Oriya":                              reflect.ValueOf(&unicode.Oriya),
+		"Osage":                              reflect.ValueOf(&unicode.Osage),
+		"Osmanya":                            reflect.ValueOf(&unicode.Osmanya),
+		"Other":                              reflect.ValueOf(&unicode.Other),
+		"Other_Alphabetic":                   reflect.ValueOf(&unicode.Other_Alphabetic),
+		"Other_Default_Ignorable_Code_Point": reflect.ValueOf(&unicode.Other_Default_Ignorable_Code_Point),
+		"Other_Grapheme_Extend":              reflect.ValueOf(&unicode.Other_Grapheme_Extend),
+		"Other_ID_Continue":                  reflect.ValueOf(&unicode.Other_ID_Continue),
+		"Other_ID_Start":                     reflect.ValueOf(&unicode.Other_ID_Start),
+		"Other_Lowercase":                    reflect.ValueOf(&unicode.Other_Lowercase),
+		"Other_Math":                         reflect.ValueOf(&unicode.Other_Math),
+		"Other_Uppercase":                    reflect.ValueOf(&unicode.Other_Uppercase),
+		"P":                                  reflect.ValueOf(&unicode.P),
+		"Pahawh_Hmong":                       reflect.ValueOf(&unicode.Pahawh_Hmong),
+		"Palmyrene":                          reflect.ValueOf(&unicode.Palmyrene),
+		"Pattern_Syntax":                     reflect.ValueOf(&unicode.Pattern_Syntax),
+		"Pattern_White_Space":                reflect.ValueOf(&unicode.Pattern_White_Space),
+		"Pau_Cin_Hau":                        reflect.ValueOf(&unicode.Pau_Cin_Hau),
+		"Pc":                                 reflect.ValueOf(&unicode.Pc),
+		"Pd":                                 reflect.ValueOf(&unicode.Pd),
+		"Pe":                                 reflect.ValueOf(&unicode.Pe),
+		"Pf":                                 reflect.ValueOf(&unicode.Pf),
+		"Phags_Pa":                           reflect.ValueOf(&unicode.Phags_Pa),
+		"Phoenician":                         reflect.ValueOf(&unicode.Phoenician),
+		"Pi":                                 reflect.ValueOf(&unicode.Pi),
+		"Po":                                 reflect.ValueOf(&unicode.Po),
+		"Prepended_Concatenation_Mark":       reflect.ValueOf(&unicode.Prepended_Concatenation_Mark),
+		"PrintRanges":                        reflect.ValueOf(&unicode.PrintRanges),
+		"Properties":                         reflect.ValueOf(&unicode.Properties),
+		"Ps":                                 reflect.ValueOf(&unicode.Ps),
+		"Psalter_Pahlavi":                    reflect.ValueOf(&unicode.Psalter_Pahlavi),
+		"Punct":                              reflect.ValueOf(&unicode.Punct),
+		"Quotation_Mark":                     reflect.ValueOf(&unicode.Quotation_Mark),
+		"Radical":                            reflect.ValueOf(&unicode.Radical),
+		"Range16":                            reflect.ValueOf((*unicode.Range16)(nil)),
+		"Range32":                            reflect.ValueOf((*unicode.Range32)(nil)),
+		"RangeTable":                         reflect.ValueOf((*unicode.RangeTable)(nil)),
+		"Regional_Indicator":                 reflect.ValueOf(&unicode.Regional_Indicator),
+		"Rejang":                             reflect.ValueOf(&unicode.Rejang),
+		"Runic":                              reflect.ValueOf(&unicode.Runic),
+		"S":                                  reflect.ValueOf(&unicode.S),
+		"STerm":                              reflect.ValueOf(&unicode.STerm),
+		"Samaritan":                          reflect.ValueOf(&unicode.Samaritan),
+		"Saurashtra":                         reflect.ValueOf(&unicode.Saurashtra),
+		"Sc":                                 reflect.ValueOf(&unicode.Sc),
+		"Scripts":                            reflect.ValueOf(&unicode.Scripts),
+		"Sentence_Terminal":                  reflect.ValueOf(&unicode.Sentence_Terminal),
+		"Sharada":                            reflect.ValueOf(&unicode.Sharada),
+		"Shavian":                            reflect.ValueOf(&unicode.Shavian),
+		"Siddham":                            reflect.ValueOf(&unicode.Siddham),
+		"Sidetic":                            reflect.ValueOf(&unicode.Sidetic),
+		"SignWriting":                        reflect.ValueOf(&unicode.SignWriting),
+		"SimpleFold":                         reflect.ValueOf(unicode.SimpleFold),
+		"Sinhala":                            reflect.ValueOf(&unicode.Sinhala),
+		"Sk":                                 reflect.ValueOf(&unicode.Sk),
+		"Sm":                                 reflect.ValueOf(&unicode.Sm),
+		"So":                                 reflect.ValueOf(&unicode.So),
+		"Soft_Dotted":                        reflect.ValueOf(&unicode.Soft_Dotted),
+		"Sogdian":                            reflect.ValueOf(&unicode.Sogdian),
+		"Sora_Sompeng":                       reflect.ValueOf(&unicode.Sora_Sompeng),
+		"Soyombo":                            reflect.ValueOf(&unicode.Soyombo),
+		"Space":                              reflect.ValueOf(&unicode.Space),
+		"SpecialCase":                        reflect.ValueOf((*unicode.SpecialCase)(nil)),
+		"Sundanese":                          reflect.ValueOf(&unicode.Sundanese),
+		"Sunuwar":                            reflect.ValueOf(&unicode.Sunuwar),
+		"Syloti_Nagri":                       reflect.ValueOf(&unicode.Syloti_Nagri),
+		"Symbol":                             reflect.ValueOf(&unicode.Symbol),
+		"Syriac":                             reflect.ValueOf(&unicode.Syriac),
+		"Tagalog":                            reflect.ValueOf(&unicode.Tagalog),
+		"Tagbanwa":                           reflect.ValueOf(&unicode.Tagbanwa),
+		"Tai_Le":                             reflect.ValueOf(&unicode.Tai_Le),
+		"Tai_Tham":                           reflect.ValueOf(&unicode.Tai_Tham),
+		"Tai_Viet":                           reflect.ValueOf(&unicode.Tai_Viet),
+		"Tai_Yo":                             reflect.ValueOf(&unicode.Tai_Yo),
+		"Takri":                              reflect.ValueOf(&unicode.Takri),
+		"Tamil":                              reflect.ValueOf(&unicode.Tamil),
+		"Tangsa":                             reflect.ValueOf(&unicode.Tangsa),
+		"Tangut":                             reflect.ValueOf(&unicode.Tangut),
+		"Telugu":                             reflect.ValueOf(&unicode.Telugu),
+		"Terminal_Punctuation":               reflect.ValueOf(&unicode.Terminal_Punctuation),
+		"Thaana":                             reflect.ValueOf(&unicode.Thaana),
+		"Thai":                               reflect.ValueOf(&unicode.Thai),
+		"Tibetan":                            reflect.ValueOf(&unicode.Tibetan),
+		"Tifinagh":                           reflect.ValueOf(&unicode.Tifinagh),
+		"Tirhuta":                            reflect.ValueOf(&unicode.Tirhuta),
+		"Title":                              reflect.ValueOf(&unicode.Title),
+		"To":                                 reflect.ValueOf(unicode.To),
+		"ToLower":                            reflect.ValueOf(unicode.ToLower),
+		"ToTitle":                            reflect.ValueOf(unicode.ToTitle),
+		"ToUpper":                            reflect.ValueOf(unicode.ToUpper),
+		"Todhri":                             reflect.ValueOf(&unicode.Todhri),
+		"Tolong_Siki":                        reflect.ValueOf(&unicode.Tolong_Siki),
+		"Toto":                               reflect.ValueOf(&unicode.Toto),
+		"Tulu_Tigalari":                      reflect.ValueOf(&unicode.Tulu_Tigalari),
+		"TurkishCase":                        reflect.ValueOf(&unicode.TurkishCase),
+		"Ugaritic":                           reflect.ValueOf(&unicode.Ugaritic),
+		"Unified_Ideograph":                  reflect.ValueOf(&unicode.Unified_Ideograph),
+		"Upper":                              reflect.ValueOf(&unicode.Upper),
+		"Vai":                                reflect.ValueOf(&unicode.Vai),
+		"Variation_Selector":                 reflect.ValueOf(&unicode.Variation_Selector),
+		"Vithkuqi":                           reflect.ValueOf(&unicode.Vithkuqi),
+		"Wancho":                             reflect.ValueOf(&unicode.Wancho),
+		"Warang_Citi":                        reflect.ValueOf(&unicode.Warang_Citi),
+		"White_Space":                        reflect.ValueOf(&unicode.White_Space),
+		"Yezidi":                             reflect.ValueOf(&unicode.Yezidi),
+		"Yi":                                 reflect.ValueOf(&unicode.Yi),
+		"Z":                                  reflect.ValueOf(&unicode.Z),
+		"Zanabazar_Square":                   reflect.ValueOf(&unicode.Zanabazar_Square),
+		"Zl":                                 reflect.ValueOf(&unicode.Zl),
+		"Zp":                                 reflect.ValueOf(&unicode.Zp),
+		"Zs":                                 reflect.ValueOf(&unicode.Zs),
+	},
+	"unicode/utf16": {
+		"AppendRune":  reflect.ValueOf(unicode_utf16.AppendRune),
+		"Decode":      reflect.ValueOf(unicode_utf16.Decode),
+		"DecodeRune":  reflect.ValueOf(unicode_utf16.DecodeRune),
+		"Encode":      reflect.ValueOf(unicode_utf16.Encode),
+		"EncodeRune":  reflect.ValueOf(unicode_utf16.EncodeRune),
+		"IsSurrogate": reflect.ValueOf(unicode_utf16.IsSurrogate),
+		"RuneLen":     reflect.ValueOf(unicode_utf16.RuneLen),
+	},
+	"unicode/utf8": {
+		"AppendRune":             reflect.ValueOf(unicode_utf8.AppendRune),
+		"DecodeLastRune":         reflect.ValueOf(unicode_utf8.DecodeLastRune),
+		"DecodeLastRuneInString": reflect.ValueOf(unicode_utf8.DecodeLastRuneInString),
+		"DecodeRune":             reflect.ValueOf(unicode_utf8.DecodeRune),
+		"DecodeRuneInString":     reflect.ValueOf(unicode_utf8.DecodeRuneInString),
+		"EncodeRune":             reflect.ValueOf(unicode_utf8.EncodeRune),
+		"FullRune":               reflect.ValueOf(unicode_utf8.FullRune),
+		"FullRuneInString":       reflect.ValueOf(unicode_utf8.FullRuneInString),
+		"RuneCount":              reflect.ValueOf(unicode_utf8.RuneCount),
+		"RuneCountInString":      reflect.ValueOf(unicode_utf8.RuneCountInString),
+		"RuneLen":                reflect.ValueOf(unicode_utf8.RuneLen),
+		"RuneStart":              reflect.ValueOf(unicode_utf8.RuneStart),
+		"Valid":                  reflect.ValueOf(unicode_utf8.Valid),
+		"ValidRune":              reflect.ValueOf(unicode_utf8.ValidRune),
+		"ValidString":            reflect.ValueOf(unicode_utf8.ValidString),
+	},
+	"github.com/fabian-z/gopherlab/display": {
+		"Bundle":              reflect.ValueOf((*github_com_fabian_z_gopherlab_display.Bundle)(nil)),
+		"Clear":               reflect.ValueOf(github_com_fabian_z_gopherlab_display.Clear),
+		"Data":                reflect.ValueOf(github_com_fabian_z_gopherlab_display.Data),
+		"DefaultImageOptions": reflect.ValueOf(&github_com_fabian_z_gopherlab_display.DefaultImageOptions),
+		"Display":             reflect.ValueOf((*github_com_fabian_z_gopherlab_display.Display)(nil)),
+		"HTML":                reflect.ValueOf(github_com_fabian_z_gopherlab_display.HTML),
+		"HTMLer":              reflect.ValueOf((*github_com_fabian_z_gopherlab_display.HTMLer)(nil)),
+		"Image":               reflect.ValueOf(github_com_fabian_z_gopherlab_display.Image),
+		"ImageOptions":        reflect.ValueOf((*github_com_fabian_z_gopherlab_display.ImageOptions)(nil)),
+		"JPEG":                reflect.ValueOf(github_com_fabian_z_gopherlab_display.JPEG),
+		"JPEGer":              reflect.ValueOf((*github_com_fabian_z_gopherlab_display.JPEGer)(nil)),
+		"JSON":                reflect.ValueOf(github_com_fabian_z_gopherlab_display.JSON),
+		"Latex":               reflect.ValueOf(github_com_fabian_z_gopherlab_display.Latex),
+		"Latexer":             reflect.ValueOf((*github_com_fabian_z_gopherlab_display.Latexer)(nil)),
+		"MIMEBundler":         reflect.ValueOf((*github_com_fabian_z_gopherlab_display.MIMEBundler)(nil)),
+		"Markdown":            reflect.ValueOf(github_com_fabian_z_gopherlab_display.Markdown),
+		"Markdowner":          reflect.ValueOf((*github_com_fabian_z_gopherlab_display.Markdowner)(nil)),
+		"MaxDepth":            reflect.ValueOf(&github_com_fabian_z_gopherlab_display.MaxDepth),
+		"MaxLength":           reflect.ValueOf(&github_com_fabian_z_gopherlab_display.MaxLength),
+		"New":                 reflect.ValueOf(github_com_fabian_z_gopherlab_display.New),
+		"PNG":                 reflect.ValueOf(github_com_fabian_z_gopherlab_display.PNG),
+		"PNGer":               reflect.ValueOf((*github_com_fabian_z_gopherlab_display.PNGer)(nil)),
+		"SVG":                 reflect.ValueOf(github_com_fabian_z_gopherlab_display.SVG),
+		"SVGer":               reflect.ValueOf((*github_com_fabian_z_gopherlab_display.SVGer)(nil)),
+		"Value":               reflect.ValueOf(github_com_fabian_z_gopherlab_display.Value),
+		"ValueBundle":         reflect.ValueOf(github_com_fabian_z_gopherlab_display.ValueBundle),
+	},
+	"github.com/fabian-z/gopherlab/ipc": {
+		"Available":       reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.Available),
+		"BeginExpr":       reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.BeginExpr),
+		"BeginValue":      reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.BeginValue),
+		"Call":            reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.Call),
+		"CommMessage":     reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.CommMessage)(nil)),
+		"CommMessages":    reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.CommMessages)(nil)),
+		"Conn":            reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.Conn)(nil)),
+		"Default":         reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.Default),
+		"Dial":            reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.Dial),
+		"DisplayMessage":  reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.DisplayMessage)(nil)),
+		"EndExpr":         reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.EndExpr),
+		"HandlePanic":     reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.HandlePanic),
+		"Handler":         reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.Handler)(nil)),
+		"Input":           reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.Input),
+		"InputReply":      reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.InputReply)(nil)),
+		"InputRequest":    reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.InputRequest)(nil)),
+		"Listen":          reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.Listen),
+		"Message":         reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.Message)(nil)),
+		"NewConn":         reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.NewConn),
+		"NewPanicMessage": reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.NewPanicMessage),
+		"PanicMessage":    reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.PanicMessage)(nil)),
+		"Reply":           reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.Reply),
+		"RunRequest":      reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.RunRequest)(nil)),
+		"RunResult":       reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.RunResult)(nil)),
+		"SendValueData":   reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.SendValueData),
+		"Server":          reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.Server)(nil)),
+		"SetAddr":         reflect.ValueOf(github_com_fabian_z_gopherlab_ipc.SetAddr),
+		"StreamMessage":   reflect.ValueOf((*github_com_fabian_z_gopherlab_ipc.StreamMessage)(nil)),
+		"Values":          reflect.ValueOf(&github_com_fabian_z_gopherlab_ipc.Values),
+	},
+}
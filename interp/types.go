@@ -0,0 +1,304 @@
+package interp
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+var (
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// basicTypes are the reflect types of the basic types, untyped ones mapped to
+// their default types.
+var basicTypes = map[types.BasicKind]reflect.Type{
+	types.Bool:          reflect.TypeOf(false),
+	types.Int:           reflect.TypeOf(int(0)),
+	types.Int8:          reflect.TypeOf(int8(0)),
+	types.Int16:         reflect.TypeOf(int16(0)),
+	types.Int32:         reflect.TypeOf(int32(0)),
+	types.Int64:         reflect.TypeOf(int64(0)),
+	types.Uint:          reflect.TypeOf(uint(0)),
+	types.Uint8:         reflect.TypeOf(uint8(0)),
+	types.Uint16:        reflect.TypeOf(uint16(0)),
+	types.Uint32:        reflect.TypeOf(uint32(0)),
+	types.Uint64:        reflect.TypeOf(uint64(0)),
+	types.Uintptr:       reflect.TypeOf(uintptr(0)),
+	types.Float32:       reflect.TypeOf(float32(0)),
+	types.Float64:       reflect.TypeOf(float64(0)),
+	types.Complex64:     reflect.TypeOf(complex64(0)),
+	types.Complex128:    reflect.TypeOf(complex128(0)),
+	types.String:        reflect.TypeOf(""),
+	types.UnsafePointer: reflect.TypeOf(unsafe.Pointer(nil)),
+
+	types.UntypedBool:    reflect.TypeOf(false),
+	types.UntypedInt:     reflect.TypeOf(int(0)),
+	types.UntypedRune:    reflect.TypeOf(rune(0)),
+	types.UntypedFloat:   reflect.TypeOf(float64(0)),
+	types.UntypedComplex: reflect.TypeOf(complex128(0)),
+	types.UntypedString:  reflect.TypeOf(""),
+	types.UntypedNil:     emptyInterfaceType,
+}
+
+// rtype returns the reflect type of t, which has been checked by check.
+func (in *Interpreter) rtype(t types.Type) reflect.Type {
+	rt, err := in.convertType(t)
+	if err != nil {
+		panic(err)
+	}
+	return rt
+}
+
+// convertType returns the reflect type of t, or an error if it cannot be
+// represented.
+func (in *Interpreter) convertType(t types.Type) (reflect.Type, error) {
+	in.typesMu.Lock()
+	defer in.typesMu.Unlock()
+
+	return in.convertTypeLocked(t, make(map[types.Type]bool))
+}
+
+func (in *Interpreter) convertTypeLocked(t types.Type, converting map[types.Type]bool) (rt reflect.Type, err error) {
+	t = types.Unalias(t)
+	if rt, ok := in.types[t]; ok {
+		return rt, nil
+	}
+	if converting[t] {
+		return nil, fmt.Errorf("recursive type %s", t)
+	}
+	converting[t] = true
+	defer func() {
+		if err == nil {
+			in.types[t] = rt
+		}
+	}()
+
+	elem := func(t types.Type) (reflect.Type, error) {
+		return in.convertTypeLocked(t, converting)
+	}
+
+	switch t := t.(type) {
+	case *types.Basic:
+		if rt, ok := basicTypes[t.Kind()]; ok {
+			return rt, nil
+		}
+	case *types.Named:
+		obj := t.Obj()
+		switch {
+		case obj.Pkg() == nil:
+			if obj.Name() == "error" {
+				return errorType, nil
+			}
+		case t.TypeArgs().Len() > 0:
+			return nil, fmt.Errorf("generic type %s", obj.Name())
+		case obj.Pkg().Path() == "main":
+			if obj.Parent() == obj.Pkg().Scope() {
+				return nil, fmt.Errorf("type %s is declared outside main", obj.Name())
+			}
+			return elem(t.Underlying())
+		default:
+			v, ok := symbols[obj.Pkg().Path()][obj.Name()]
+			if !ok || v.Kind() != reflect.Ptr || !v.IsNil() {
+				return nil, fmt.Errorf("type %s.%s is not available", obj.Pkg().Name(), obj.Name())
+			}
+			return v.Type().Elem(), nil
+		}
+	case *types.Pointer:
+		e, err := elem(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PtrTo(e), nil
+	case *types.Slice:
+		e, err := elem(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(e), nil
+	case *types.Array:
+		e, err := elem(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ArrayOf(int(t.Len()), e), nil
+	case *types.Map:
+		k, err := elem(t.Key())
+		if err != nil {
+			return nil, err
+		}
+		e, err := elem(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return reflect.MapOf(k, e), nil
+	case *types.Chan:
+		e, err := elem(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		dir := reflect.BothDir
+		switch t.Dir() {
+		case types.SendOnly:
+			dir = reflect.SendDir
+		case types.RecvOnly:
+			dir = reflect.RecvDir
+		}
+		return reflect.ChanOf(dir, e), nil
+	case *types.Signature:
+		if t.TypeParams().Len() > 0 {
+			return nil, fmt.Errorf("generic function type %s", t)
+		}
+		var params, results []reflect.Type
+		for i := 0; i < t.Params().Len(); i++ {
+			p, err := elem(t.Params().At(i).Type())
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, p)
+		}
+		for i := 0; i < t.Results().Len(); i++ {
+			r, err := elem(t.Results().At(i).Type())
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, r)
+		}
+		return reflect.FuncOf(params, results, t.Variadic()), nil
+	case *types.Struct:
+		var fields []reflect.StructField
+		for i := 0; i < t.NumFields(); i++ {
+			field := t.Field(i)
+			ft, err := elem(field.Type())
+			if err != nil {
+				return nil, err
+			}
+			sf := reflect.StructField{
+				Name:      field.Name(),
+				Type:      ft,
+				Tag:       reflect.StructTag(t.Tag(i)),
+				Anonymous: field.Embedded(),
+			}
+			if !field.Exported() {
+				sf.PkgPath = "main"
+			}
+			fields = append(fields, sf)
+		}
+		return structOf(fields)
+	case *types.Interface:
+		if t.NumMethods() == 0 && t.IsMethodSet() {
+			return emptyInterfaceType, nil
+		}
+		return nil, fmt.Errorf("interface type %s with methods", t)
+	}
+
+	return nil, fmt.Errorf("type %s", t)
+}
+
+// structOf is reflect.StructOf, returning an error for the structs it does not
+// support.
+func structOf(fields []reflect.StructField) (rt reflect.Type, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("struct type: %v", r)
+		}
+	}()
+	return reflect.StructOf(fields), nil
+}
+
+// check returns an *UnsupportedError if stmts use features the interpreter does
+// not support.
+func (in *Interpreter) check(c *code, stmts []ast.Stmt) error {
+	var err error
+	unsupported := func(pos token.Pos, format string, args ...interface{}) {
+		if err == nil {
+			err = &UnsupportedError{pos, fmt.Sprintf(format, args...)}
+		}
+	}
+
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(node ast.Node) bool {
+			if err != nil {
+				return false
+			}
+
+			switch node := node.(type) {
+			case *ast.BranchStmt:
+				if node.Tok == token.GOTO {
+					unsupported(node.Pos(), "goto is not supported")
+				}
+			case *ast.RangeStmt:
+				if _, ok := c.info.TypeOf(node.X).Underlying().(*types.Signature); ok {
+					unsupported(node.Pos(), "range over functions is not supported")
+				}
+			case *ast.SelectorExpr:
+				ident, ok := node.X.(*ast.Ident)
+				if !ok {
+					break
+				}
+				pkgName, ok := c.info.Uses[ident].(*types.PkgName)
+				if !ok {
+					break
+				}
+				obj := c.info.Uses[node.Sel]
+				if _, ok := obj.(*types.Const); ok {
+					return false
+				}
+				if _, ok := in.symbol(pkgName.Imported().Path(), obj.Name()); !ok {
+					if _, ok := symbols[pkgName.Imported().Path()]; !ok {
+						unsupported(node.Pos(), "package %s is not available", strconv.Quote(pkgName.Imported().Path()))
+					} else {
+						unsupported(node.Pos(), "%s.%s is not available", ident.Name, obj.Name())
+					}
+				}
+				return false
+			case *ast.Ident:
+				obj := c.info.Uses[node]
+				if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != "main" || obj.Parent() != obj.Pkg().Scope() {
+					break
+				}
+				switch obj.(type) {
+				case *types.Func:
+					if _, ok := in.symbol("main", obj.Name()); !ok {
+						unsupported(node.Pos(), "function %s is declared outside main", obj.Name())
+					}
+				case *types.Var, *types.TypeName:
+					unsupported(node.Pos(), "%s is declared outside main", obj.Name())
+				}
+			}
+
+			if expr, ok := node.(ast.Expr); ok {
+				if _, ok := c.info.Instances[identOf(expr)]; ok {
+					unsupported(node.Pos(), "generic functions and types are not supported")
+				}
+				tv, ok := c.info.Types[expr]
+				if ok && tv.Type != nil && !tv.IsNil() && !tv.IsBuiltin() {
+					if _, isTuple := tv.Type.(*types.Tuple); !isTuple {
+						if _, e := in.convertType(tv.Type); e != nil {
+							unsupported(node.Pos(), "%s is not supported", e)
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	return err
+}
+
+// identOf returns the identifier expr refers to, if it is one or a qualified one.
+func identOf(expr ast.Expr) *ast.Ident {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr
+	case *ast.SelectorExpr:
+		return expr.Sel
+	}
+	return nil
+}
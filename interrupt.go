@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/fabian-z/gopherlab/protocol"
+	"sync/atomic"
+)
+
+// interrupted is set to 1 once the running cell has been interrupted, and reset
+// when the next one starts.
+var interrupted int32
+
+// HandleInterruptRequest stops the running cell and sends an interrupt_reply.
+func HandleInterruptRequest(receipt MsgReceipt) {
+	InterruptExecution()
+
+	reply := NewMsg("interrupt_reply", receipt.Msg)
+	reply.Content = protocol.InterruptReply{Status: "ok"}
+	receipt.Reply(reply)
+}
+
+// InterruptExecution kills the program run by the current cell, if any, and
+// cancels pending input requests. The kernel and its session stay alive.
+func InterruptExecution() {
+	atomic.StoreInt32(&interrupted, 1)
+
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	if sessionClosed || !REPLSession.Interrupt() {
+		logger.Println("Interrupt: no cell running")
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"github.com/fabian-z/gopherlab/protocol"
+	repl "github.com/fabian-z/gopherlab/replpkg"
+	"unicode/utf8"
+)
+
+// HandleCompleteRequest sends a complete_reply with completion candidates for the
+// code and cursor position of a complete_request.
+func HandleCompleteRequest(receipt MsgReceipt) {
+	var req protocol.CompleteRequest
+	if err := receipt.Msg.DecodeContent(&req); err != nil {
+		receipt.ReplyError("complete_reply", err)
+		return
+	}
+
+	reply := NewMsg("complete_reply", receipt.Msg)
+
+	// Jupyter counts the cursor position in unicode code points, the session in bytes
+	code := req.Code
+	pos := byteOffset(code, req.CursorPos)
+	matches, start, end := REPLSession.Complete(code, pos)
+	if matches == nil {
+		matches = []string{}
+	}
+
+	reply.Content = protocol.CompleteReply{
+		Matches:     matches,
+		CursorStart: utf8.RuneCountInString(code[:start]),
+		CursorEnd:   utf8.RuneCountInString(code[:end]),
+		Metadata:    make(map[string]interface{}),
+		Status:      "ok",
+	}
+
+	receipt.Reply(reply)
+}
+
+// byteOffset converts an offset in unicode code points into a byte offset within s.
+func byteOffset(s string, runes int) int {
+	for i := range s {
+		if runes <= 0 {
+			return i
+		}
+		runes--
+	}
+	return len(s)
+}
+
+// HandleInspectRequest sends an inspect_reply with the documentation of the
+// identifier at the cursor position of an inspect_request.
+func HandleInspectRequest(receipt MsgReceipt) {
+	var req protocol.InspectRequest
+	if err := receipt.Msg.DecodeContent(&req); err != nil {
+		receipt.ReplyError("inspect_reply", err)
+		return
+	}
+
+	reply := NewMsg("inspect_reply", receipt.Msg)
+
+	content := protocol.InspectReply{
+		Status:   "ok",
+		Data:     make(protocol.MIMEBundle),
+		Metadata: make(map[string]interface{}),
+	}
+
+	text, markdown, err := REPLSession.Inspect(req.Code, byteOffset(req.Code, req.CursorPos), req.DetailLevel)
+	if err != nil {
+		logger.Println("inspect:", err)
+	} else {
+		content.Found = true
+		content.Data["text/plain"] = text
+		content.Data["text/markdown"] = markdown
+	}
+
+	reply.Content = content
+	receipt.Reply(reply)
+}
+
+// HandleIsCompleteRequest sends an is_complete_reply telling whether the code of an
+// is_complete_request can be executed as it is. The session is left unchanged.
+func HandleIsCompleteRequest(receipt MsgReceipt) {
+	var req protocol.IsCompleteRequest
+	if err := receipt.Msg.DecodeContent(&req); err != nil {
+		receipt.ReplyError("is_complete_reply", err)
+		return
+	}
+
+	reply := NewMsg("is_complete_reply", receipt.Msg)
+
+	status, indent := repl.IsComplete(req.Code)
+	reply.Content = protocol.IsCompleteReply{Status: status, Indent: indent}
+
+	receipt.Reply(reply)
+}
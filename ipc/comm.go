@@ -0,0 +1,22 @@
+package ipc
+
+import (
+	"encoding/json"
+)
+
+// CommMessage is exchanged for the comms opened by programs. Requests of type
+// "comm_open" carry the target name, and the trace of the goroutine opening the
+// comm in Stack, and are answered with the id of the comm; "comm_msg",
+// "comm_recv" and "comm_close" refer to the comm by its id.
+type CommMessage struct {
+	ID     string          `json:"comm_id,omitempty"`
+	Target string          `json:"target_name,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Stack  string          `json:"stack,omitempty"`
+}
+
+// CommMessages is the reply to "comm_recv": the data of the messages sent to the
+// comm by the frontend since the last request.
+type CommMessages struct {
+	Messages []json.RawMessage `json:"messages"`
+}
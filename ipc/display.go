@@ -0,0 +1,11 @@
+package ipc
+
+// DisplayMessage is sent by programs to show rich output in the notebook. Requests
+// of type "display_data" show Data, "update_display_data" replaces the data of the
+// display DisplayID, and "clear_output" clears the output of the cell.
+type DisplayMessage struct {
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	DisplayID string                 `json:"display_id,omitempty"`
+	Wait      bool                   `json:"wait,omitempty"`
+}
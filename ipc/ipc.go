@@ -0,0 +1,280 @@
+// Package ipc implements the side channel between the gopherlab kernel and the
+// programs it runs for a session.
+//
+// The kernel listens on a unix socket and passes its path to the program in the
+// GOPHERLAB_IPC environment variable. Both ends exchange Messages over it, encoded
+// as one JSON object per line.
+package ipc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+)
+
+// EnvVar is the environment variable holding the path of the kernel's socket.
+const EnvVar = "GOPHERLAB_IPC"
+
+// Message is the unit of exchange on the channel.
+type Message struct {
+	Type    string          `json:"type"`
+	Content json.RawMessage `json:"content,omitempty"`
+}
+
+// Decode unmarshals the content of the message into v.
+func (msg Message) Decode(v interface{}) error {
+	if len(msg.Content) == 0 {
+		return nil
+	}
+	return json.Unmarshal(msg.Content, v)
+}
+
+// Conn is a connection on the channel. Send and Recv may be called concurrently
+// with each other.
+type Conn struct {
+	conn net.Conn
+	dec  *json.Decoder
+
+	mu  sync.Mutex // guards enc
+	enc *json.Encoder
+}
+
+// NewConn returns a Conn exchanging messages over conn.
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{
+		conn: conn,
+		dec:  json.NewDecoder(conn),
+		enc:  json.NewEncoder(conn),
+	}
+}
+
+// Send sends a message of type typ with content marshalled from content.
+func (c *Conn) Send(typ string, content interface{}) error {
+	msg := Message{Type: typ}
+	if content != nil {
+		raw, err := json.Marshal(content)
+		if err != nil {
+			return err
+		}
+		msg.Content = raw
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enc.Encode(msg)
+}
+
+// Recv receives the next message.
+func (c *Conn) Recv() (msg Message, err error) {
+	err = c.dec.Decode(&msg)
+	return
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+var (
+	addrMu sync.Mutex
+	addr   string // the path set by SetAddr
+)
+
+// kernelAddr returns the path of the kernel's socket: the one set by SetAddr, or
+// else the one in EnvVar.
+func kernelAddr() string {
+	addrMu.Lock()
+	defer addrMu.Unlock()
+
+	if addr != "" {
+		return addr
+	}
+	return os.Getenv(EnvVar)
+}
+
+// SetAddr sets the path of the kernel's socket for the programs the kernel runs in
+// its own process, which do not get EnvVar. The connections dialled before are
+// closed, and dialled again on use; an empty path resets it to EnvVar.
+func SetAddr(path string) {
+	addrMu.Lock()
+	changed := addr != path
+	addr = path
+	addrMu.Unlock()
+	if !changed {
+		return
+	}
+
+	defaultMu.Lock()
+	if defaultConn != nil {
+		defaultConn.Close()
+	}
+	defaultDialled, defaultConn, defaultErr = false, nil, nil
+	defaultMu.Unlock()
+
+	callMu.Lock()
+	if callConn != nil {
+		callConn.Close()
+	}
+	callDialled, callConn, callErr = false, nil, nil
+	callMu.Unlock()
+}
+
+// Available reports whether the program was started by the kernel.
+func Available() bool {
+	return kernelAddr() != ""
+}
+
+var (
+	defaultMu      sync.Mutex // guards the variables below
+	defaultDialled bool
+	defaultConn    *Conn
+	defaultErr     error
+)
+
+// Default returns the connection to the kernel shared within the program,
+// dialling it on first use.
+func Default() (*Conn, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if !defaultDialled {
+		defaultConn, defaultErr = Dial()
+		defaultDialled = true
+	}
+	return defaultConn, defaultErr
+}
+
+// Dial connects to the kernel that started the program.
+func Dial() (*Conn, error) {
+	path := kernelAddr()
+	if path == "" {
+		return nil, fmt.Errorf("ipc: %s not set", EnvVar)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConn(conn), nil
+}
+
+var (
+	callMu      sync.Mutex // guards the variables below
+	callDialled bool
+	callConn    *Conn
+	callErr     error
+)
+
+// Call sends a request of type typ to the kernel and waits for its reply, which is
+// decoded into reply. Requests are sent on a connection of their own, one at a time.
+// The kernel answers with a "reply" message, or with an "error" message holding the
+// reason it failed.
+func Call(typ string, content interface{}, reply interface{}) error {
+	callMu.Lock()
+	defer callMu.Unlock()
+
+	if !callDialled {
+		callConn, callErr = Dial()
+		callDialled = true
+	}
+	if callErr != nil {
+		return callErr
+	}
+
+	if err := callConn.Send(typ, content); err != nil {
+		return err
+	}
+
+	msg, err := callConn.Recv()
+	if err != nil {
+		return err
+	}
+
+	if msg.Type == "error" {
+		var reason string
+		if err := msg.Decode(&reason); err != nil {
+			return err
+		}
+		return errors.New(reason)
+	}
+
+	if reply == nil {
+		return nil
+	}
+	return msg.Decode(reply)
+}
+
+// Reply answers a request received with Call, with either content or err.
+func Reply(conn *Conn, content interface{}, err error) error {
+	if err != nil {
+		return conn.Send("error", err.Error())
+	}
+	return conn.Send("reply", content)
+}
+
+// Handler responds to a message received on conn.
+type Handler func(conn *Conn, msg Message)
+
+// Server serves the channel for the programs of a session.
+type Server struct {
+	l       net.Listener
+	handler Handler
+	wg      sync.WaitGroup
+}
+
+// Listen starts serving the channel on the unix socket at path. The handler is
+// called for every message received; messages of one connection are handled in
+// order.
+func Listen(path string, handler Handler) (*Server, error) {
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	srv := &Server{l: l, handler: handler}
+	srv.wg.Add(1)
+	go srv.serve()
+
+	return srv, nil
+}
+
+// Addr returns the path of the socket, to be passed to programs in EnvVar.
+func (srv *Server) Addr() string {
+	return srv.l.Addr().String()
+}
+
+func (srv *Server) serve() {
+	defer srv.wg.Done()
+
+	for {
+		conn, err := srv.l.Accept()
+		if err != nil {
+			return
+		}
+
+		srv.wg.Add(1)
+		go func(c *Conn) {
+			defer srv.wg.Done()
+			defer c.Close()
+			for {
+				msg, err := c.Recv()
+				if err != nil {
+					return
+				}
+				srv.handler(c, msg)
+			}
+		}(NewConn(conn))
+	}
+}
+
+// Close stops accepting connections and waits until the accepted ones have been
+// closed by the programs, so that all of their messages have been handled.
+func (srv *Server) Close() error {
+	err := srv.l.Close()
+	srv.wg.Wait()
+	return err
+}
@@ -0,0 +1,56 @@
+package ipc
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+)
+
+// PanicMessage reports a panic of the main goroutine of a program.
+type PanicMessage struct {
+	Value string `json:"value"`
+	Type  string `json:"type"`
+	// Stack is the trace of the panicking goroutine, as printed by the runtime.
+	Stack string `json:"stack"`
+}
+
+// HandlePanic reports a panic of the main goroutine to the kernel, including the
+// dynamic type of the panic value, and exits with status 2 as the runtime does.
+// It has to be deferred by the main function; programs not started by the kernel
+// panic as usual.
+func HandlePanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if !Available() {
+		panic(r)
+	}
+	conn, err := Default()
+	if err != nil {
+		panic(r)
+	}
+
+	if err := conn.Send("panic", NewPanicMessage(r)); err != nil {
+		panic(r)
+	}
+	os.Exit(2)
+}
+
+// NewPanicMessage returns the report of the panic value r, recovered by a deferred
+// function of the panicking goroutine, which is included in the trace of the stack.
+func NewPanicMessage(r interface{}) PanicMessage {
+	return PanicMessage{Value: panicValue(r), Type: fmt.Sprintf("%T", r), Stack: string(debug.Stack())}
+}
+
+// panicValue returns the text of the panic value r.
+func panicValue(r interface{}) string {
+	switch r := r.(type) {
+	case error:
+		return r.Error()
+	case fmt.Stringer:
+		return r.String()
+	}
+	return fmt.Sprint(r)
+}
@@ -0,0 +1,32 @@
+package ipc
+
+import (
+	"errors"
+)
+
+// InputRequest asks the notebook user for a line of input.
+type InputRequest struct {
+	Prompt   string `json:"prompt"`
+	Password bool   `json:"password"`
+}
+
+// InputReply holds the line entered by the user, or why there is none.
+type InputReply struct {
+	Value string `json:"value"`
+	Error string `json:"error,omitempty"`
+}
+
+// Input asks the notebook user for a line of input, showing prompt. If password is
+// set, the input is not echoed. The returned line has no trailing newline.
+func Input(prompt string, password bool) (string, error) {
+	var reply InputReply
+	err := Call("input_request", InputRequest{prompt, password}, &reply)
+	if err != nil {
+		return "", err
+	}
+	if reply.Error != "" {
+		return "", errors.New(reply.Error)
+	}
+
+	return reply.Value, nil
+}
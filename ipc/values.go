@@ -0,0 +1,81 @@
+package ipc
+
+import (
+	"os"
+)
+
+// Values receives the printed values of the expressions evaluated in a cell, which
+// are shown as its result. Programs not started by the kernel print them to
+// os.Stdout instead.
+var Values = valueWriter{}
+
+type valueWriter struct{}
+
+func (valueWriter) Write(p []byte) (int, error) {
+	if !Available() {
+		return os.Stdout.Write(p)
+	}
+
+	conn, err := Default()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := conn.Send("value", string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// BeginValue marks the start of the value printed by one statement, so that the
+// kernel can tell the values of several statements apart.
+func BeginValue() {
+	if !Available() {
+		return
+	}
+
+	if conn, err := Default(); err == nil {
+		conn.Send("value_start", nil)
+	}
+}
+
+// BeginExpr marks the start of the value of the expression i the kernel evaluates
+// along with a cell, which is kept apart from the values of the cell.
+func BeginExpr(i int) {
+	if !Available() {
+		return
+	}
+
+	if conn, err := Default(); err == nil {
+		conn.Send("expr_start", i)
+	}
+}
+
+// EndExpr ends the value of the expression begun by BeginExpr. It is deferred, and
+// reports a panic evaluating the expression in place of its value, which does not
+// fail the cell.
+func EndExpr() {
+	r := recover()
+	if !Available() {
+		return
+	}
+
+	var msg *PanicMessage
+	if r != nil {
+		p := NewPanicMessage(r)
+		msg = &p
+	}
+	if conn, err := Default(); err == nil {
+		conn.Send("expr_end", msg)
+	}
+}
+
+// SendValueData sends the rich representations of the value whose text is printed
+// next, as marked by BeginValue. The kernel shows them along with the text.
+func SendValueData(data DisplayMessage) error {
+	conn, err := Default()
+	if err != nil {
+		return err
+	}
+	return conn.Send("value_data", data)
+}
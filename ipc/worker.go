@@ -0,0 +1,23 @@
+package ipc
+
+// RunRequest is sent by the kernel to the worker process of a session, to run the
+// cell built as the plugin at the path Plugin.
+type RunRequest struct {
+	Plugin string `json:"plugin"`
+}
+
+// RunResult is the reply of the worker process to a "run" request: Error tells why
+// the plugin could not be run, Panic reports a panic of the cell, and Interrupted
+// that the cell has been interrupted before it returned.
+type RunResult struct {
+	Error       string        `json:"error,omitempty"`
+	Panic       *PanicMessage `json:"panic,omitempty"`
+	Interrupted bool          `json:"interrupted,omitempty"`
+}
+
+// StreamMessage carries the output a cell run by the worker process writes to the
+// stream Name, "stdout" or "stderr".
+type StreamMessage struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
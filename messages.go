@@ -1,29 +1,23 @@
 package main
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
-	uuid "github.com/nu7hatch/gouuid"
+	"errors"
+	"fmt"
+	"github.com/fabian-z/gopherlab/protocol"
 	zmq "github.com/pebbe/zmq4"
 )
 
-// MsgHeader encodes header info for ZMQ messages
-type MsgHeader struct {
-	MsgID    string `json:"msg_id"`
-	Username string `json:"username"`
-	Session  string `json:"session"`
-	MsgType  string `json:"msg_type"`
-	Version  string `json:"version"`
-}
-
 // ComposedMsg represents an entire message in a high-level structure.
 type ComposedMsg struct {
-	Header       MsgHeader
-	ParentHeader MsgHeader
+	Header       protocol.Header
+	ParentHeader protocol.Header
 	Metadata     map[string]interface{}
-	Content      interface{}
+	// Content holds the content of messages to be sent, and the raw JSON content
+	// of received ones, see DecodeContent.
+	Content interface{}
+	// Buffers holds the binary buffers following the content, if any.
+	Buffers [][]byte
 }
 
 // InvalidSignatureError is returned when the signature on a received message does not
@@ -37,37 +31,56 @@ func (e *InvalidSignatureError) Error() string {
 // WireMsgToComposedMsg translates a multipart ZMQ messages received from a socket into
 // a ComposedMsg struct and a slice of return identities. This includes verifying the
 // message signature.
-func WireMsgToComposedMsg(msgparts [][]byte, signkey []byte) (msg ComposedMsg,
+func WireMsgToComposedMsg(msgparts [][]byte, signer *Signer) (msg ComposedMsg,
 	identities [][]byte, err error) {
 	i := 0
-	for string(msgparts[i]) != "<IDS|MSG>" {
+	for i < len(msgparts) && string(msgparts[i]) != "<IDS|MSG>" {
 		i++
 	}
+	if i == len(msgparts) {
+		return msg, nil, errors.New("message without delimiter")
+	}
+	if len(msgparts) < i+6 {
+		return msg, nil, fmt.Errorf("message with %d frames after the delimiter, need 5", len(msgparts)-i-1)
+	}
 	identities = msgparts[:i]
 	// msgparts[i] is the delimiter
 
 	// Validate signature
-	if len(signkey) != 0 {
-		mac := hmac.New(sha256.New, signkey)
-		for _, msgpart := range msgparts[i+2 : i+6] {
-			mac.Write(msgpart)
-		}
-		signature := make([]byte, hex.DecodedLen(len(msgparts[i+1])))
-		hex.Decode(signature, msgparts[i+1])
-		if !hmac.Equal(mac.Sum(nil), signature) {
-			return msg, nil, &InvalidSignatureError{}
+	if err = signer.Verify(msgparts[i+1], msgparts[i+2:i+6]); err != nil {
+		return msg, nil, err
+	}
+
+	// the content is decoded by the handler of the message
+	var content map[string]json.RawMessage
+	frames := []struct {
+		name string
+		v    interface{}
+	}{
+		{"header", &msg.Header},
+		{"parent header", &msg.ParentHeader},
+		{"metadata", &msg.Metadata},
+		{"content", &content},
+	}
+	for j, frame := range frames {
+		if err = json.Unmarshal(msgparts[i+2+j], frame.v); err != nil {
+			return msg, nil, fmt.Errorf("message %s: %s", frame.name, err)
 		}
 	}
-	json.Unmarshal(msgparts[i+2], &msg.Header)
-	json.Unmarshal(msgparts[i+3], &msg.ParentHeader)
-	json.Unmarshal(msgparts[i+4], &msg.Metadata)
-	json.Unmarshal(msgparts[i+5], &msg.Content)
+	if err = msg.Header.Validate(); err != nil {
+		return msg, nil, err
+	}
+	msg.Content = json.RawMessage(msgparts[i+5])
+
+	if len(msgparts) > i+6 {
+		msg.Buffers = msgparts[i+6:]
+	}
 	return
 }
 
 // ToWireMsg translates a ComposedMsg into a multipart ZMQ message ready to send, and
 // signs it. This does not add the return identities or the delimiter.
-func (msg ComposedMsg) ToWireMsg(signkey []byte) (msgparts [][]byte) {
+func (msg ComposedMsg) ToWireMsg(signer *Signer) (msgparts [][]byte) {
 
 	msgparts = make([][]byte, 5)
 	header, err := json.Marshal(msg.Header)
@@ -95,14 +108,10 @@ func (msg ComposedMsg) ToWireMsg(signkey []byte) (msgparts [][]byte) {
 	msgparts[4] = content
 
 	// Sign the message
-	if len(signkey) != 0 {
-		mac := hmac.New(sha256.New, signkey)
-		for _, msgpart := range msgparts[1:] {
-			mac.Write(msgpart)
-		}
-		msgparts[0] = make([]byte, hex.EncodedLen(mac.Size()))
-		hex.Encode(msgparts[0], mac.Sum(nil))
-	}
+	msgparts[0] = signer.Sign(msgparts[1:])
+
+	// Buffers are not signed
+	msgparts = append(msgparts, msg.Buffers...)
 
 	return
 }
@@ -113,6 +122,14 @@ type MsgReceipt struct {
 	Msg        ComposedMsg
 	Identities [][]byte
 	Sockets    SocketGroup
+	// Socket is the socket the message was received on.
+	Socket *zmq.Socket
+}
+
+// Reply sends a reply to the received message on the socket it was received on.
+// It must only be called by the goroutine owning that socket.
+func (receipt *MsgReceipt) Reply(msg ComposedMsg) {
+	receipt.SendResponse(receipt.Socket, msg)
 }
 
 // SendResponse sends a message back to return identites of the received message.
@@ -135,7 +152,7 @@ func (receipt *MsgReceipt) SendResponse(socket *zmq.Socket, msg ComposedMsg) {
 		logger.Fatal(err)
 	}
 
-	newmsg := msg.ToWireMsg(receipt.Sockets.Key)
+	newmsg := msg.ToWireMsg(receipt.Sockets.Signer)
 
 	for i := 0; i < len(newmsg)-1; i++ {
 		if _, err = socket.SendBytes(newmsg[i], zmq.SNDMORE); err != nil {
@@ -151,35 +168,105 @@ func (receipt *MsgReceipt) SendResponse(socket *zmq.Socket, msg ComposedMsg) {
 	logger.Printf("%+v\n", msg.Content)
 }
 
+// publication is a message queued for publishing on the IOPub socket.
+type publication struct {
+	receipt MsgReceipt
+	msg     ComposedMsg
+}
+
+var (
+	// publications holds the messages to be sent by PublishMsgs, in order.
+	publications = make(chan publication, 64)
+	// stopPublishing is closed to make PublishMsgs close the IOPub socket, once
+	// the queued messages have been sent.
+	stopPublishing = make(chan struct{})
+	// publisherDone is closed when PublishMsgs has returned.
+	publisherDone = make(chan struct{})
+)
+
+// PublishMsgs sends the messages queued by Publish on the IOPub socket. It is the
+// only goroutine using the socket, and closes it when the kernel stops.
+func PublishMsgs(sockets SocketGroup) {
+	defer close(publisherDone)
+
+	for {
+		select {
+		case p := <-publications:
+			p.receipt.SendResponse(sockets.IOPub_socket, p.msg)
+		case <-stopPublishing:
+			for {
+				select {
+				case p := <-publications:
+					p.receipt.SendResponse(sockets.IOPub_socket, p.msg)
+				default:
+					sockets.IOPub_socket.Close()
+					return
+				}
+			}
+		}
+	}
+}
+
+// Publish queues a message for the IOPub socket. Messages are published in the order
+// they are queued in, with a topic naming their type. Once the kernel is stopping,
+// messages are dropped. It is safe for concurrent use.
+func (receipt *MsgReceipt) Publish(msg ComposedMsg) {
+	topic := MsgReceipt{
+		Msg:        receipt.Msg,
+		Identities: [][]byte{[]byte("kernel." + msg.Header.MsgType)},
+		Sockets:    receipt.Sockets,
+	}
+
+	// the publisher may have drained the queue already
+	select {
+	case <-stopPublishing:
+		logger.Println("Dropping message published after shutdown:", msg.Header.MsgType)
+		return
+	default:
+	}
+
+	select {
+	case publications <- publication{topic, msg}:
+	case <-stopPublishing:
+		logger.Println("Dropping message published after shutdown:", msg.Header.MsgType)
+	}
+}
+
 // NewMsg creates a new ComposedMsg to respond to a parent message. This includes setting
 // up its headers.
 func NewMsg(msgType string, parent ComposedMsg) (msg ComposedMsg) {
 	msg.ParentHeader = parent.Header
-	msg.Header.Session = parent.Header.Session
-	msg.Header.Username = parent.Header.Username
-	msg.Header.MsgType = msgType
-	u, _ := uuid.NewV4()
-	msg.Header.MsgID = u.String()
-	msg.Header.Version = protocolVersion
+	msg.Header = protocol.NewHeader(msgType, parent.Header.Session, parent.Header.Username)
 	return
 }
 
-func HandleWithStatus(receipt MsgReceipt, handler func(MsgReceipt)) {
+// DecodeContent decodes the content of a received message into v, see protocol.Decode.
+func (msg ComposedMsg) DecodeContent(v interface{}) error {
+	content, _ := msg.Content.(json.RawMessage)
+	return protocol.Decode(content, v)
+}
 
-	// Publish status: busy
+// ReplyError sends a reply of type msgType with status "error" to a request whose
+// content could not be decoded.
+func (receipt *MsgReceipt) ReplyError(msgType string, err error) {
+	logger.Printf("Bad %s: %s", receipt.Msg.Header.MsgType, err)
 
-	busy := NewMsg("status", receipt.Msg)
-	busy.Content = KernelStatus{"busy"}
-	receipt.SendResponse(receipt.Sockets.IOPub_socket, busy)
+	reply := NewMsg(msgType, receipt.Msg)
+	reply.Content = protocol.NewErrorReply("BadRequest", err)
+	receipt.Reply(reply)
+}
 
-	// Call actual handler function
+// PublishStatus publishes the execution state of the kernel in response to receipt.
+func PublishStatus(receipt MsgReceipt, state string) {
+	status := NewMsg("status", receipt.Msg)
+	status.Content = protocol.Status{ExecutionState: state}
+	receipt.Publish(status)
+}
 
+// HandleWithStatus calls handler for the request received, publishing the busy status
+// before and the idle status after.
+func HandleWithStatus(receipt MsgReceipt, handler func(MsgReceipt)) {
+	PublishStatus(receipt, "busy")
 	handler(receipt)
-
-	// Publish status: idle after processing
-
-	idle := NewMsg("status", receipt.Msg)
-	idle.Content = KernelStatus{"idle"}
-	receipt.SendResponse(receipt.Sockets.IOPub_socket, idle)
-
+	PublishStatus(receipt, "idle")
 }
@@ -0,0 +1,71 @@
+package protocol
+
+import (
+	"errors"
+)
+
+// Status is the content of a status message.
+type Status struct {
+	ExecutionState string `json:"execution_state"`
+}
+
+// Stream is the content of a stream message.
+type Stream struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+// DisplayData is the content of display_data and update_display_data messages.
+type DisplayData struct {
+	Data      MIMEBundle             `json:"data"`
+	Metadata  map[string]interface{} `json:"metadata"`
+	Transient map[string]interface{} `json:"transient,omitempty"`
+}
+
+// ExecuteInput is the content of an execute_input message.
+type ExecuteInput struct {
+	Code           string `json:"code"`
+	ExecutionCount int    `json:"execution_count"`
+}
+
+// ExecuteResult is the content of an execute_result message.
+type ExecuteResult struct {
+	ExecutionCount int                    `json:"execution_count"`
+	Data           MIMEBundle             `json:"data"`
+	Metadata       map[string]interface{} `json:"metadata"`
+}
+
+// ClearOutput is the content of a clear_output message.
+type ClearOutput struct {
+	Wait bool `json:"wait"`
+}
+
+// CommOpen is the content of a comm_open message.
+type CommOpen struct {
+	CommID       string      `json:"comm_id"`
+	TargetName   string      `json:"target_name"`
+	TargetModule string      `json:"target_module,omitempty"`
+	Data         interface{} `json:"data"`
+}
+
+// Validate checks that the comm is identified.
+func (msg *CommOpen) Validate() error {
+	if msg.CommID == "" {
+		return errors.New("comm_open without comm_id")
+	}
+	return nil
+}
+
+// CommMsg is the content of comm_msg and comm_close messages.
+type CommMsg struct {
+	CommID string      `json:"comm_id"`
+	Data   interface{} `json:"data"`
+}
+
+// Validate checks that the comm is identified.
+func (msg *CommMsg) Validate() error {
+	if msg.CommID == "" {
+		return errors.New("comm message without comm_id")
+	}
+	return nil
+}
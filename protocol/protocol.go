@@ -0,0 +1,106 @@
+// Package protocol defines the messages of the Jupyter messaging protocol, version 5.x,
+// as exchanged between the gopherlab kernel and its frontends.
+//
+// See https://jupyter-client.readthedocs.io/en/latest/messaging.html for the specification.
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	uuid "github.com/nu7hatch/gouuid"
+)
+
+// Version is the version of the messaging protocol implemented by the kernel.
+const Version = "5.3"
+
+// Header is the header of a message.
+type Header struct {
+	MsgID    string `json:"msg_id"`
+	Username string `json:"username"`
+	Session  string `json:"session"`
+	Date     string `json:"date"`
+	MsgType  string `json:"msg_type"`
+	Version  string `json:"version"`
+}
+
+// NewHeader returns the header of a new message of type msgType for the session,
+// with a fresh message id and the current date.
+func NewHeader(msgType, session, username string) Header {
+	u, _ := uuid.NewV4()
+	return Header{
+		MsgID:    u.String(),
+		Username: username,
+		Session:  session,
+		Date:     time.Now().UTC().Format(time.RFC3339Nano),
+		MsgType:  msgType,
+		Version:  Version,
+	}
+}
+
+// Validate checks that the header identifies a message. Older frontends omit the
+// date and version, so they are not required.
+func (h Header) Validate() error {
+	if h.MsgID == "" {
+		return errors.New("header without msg_id")
+	}
+	if h.MsgType == "" {
+		return errors.New("header without msg_type")
+	}
+	return nil
+}
+
+// Defaulter is implemented by contents with fields whose default is not their zero value.
+type Defaulter interface {
+	SetDefaults()
+}
+
+// Validator is implemented by contents which check their fields after decoding.
+type Validator interface {
+	Validate() error
+}
+
+// Decode decodes the JSON content of a message into v. Fields missing from content
+// keep the defaults set by v's SetDefaults method, and v is validated afterwards
+// if it has a Validate method.
+func Decode(content []byte, v interface{}) error {
+	if d, ok := v.(Defaulter); ok {
+		d.SetDefaults()
+	}
+
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, v); err != nil {
+			return err
+		}
+	}
+
+	if val, ok := v.(Validator); ok {
+		return val.Validate()
+	}
+	return nil
+}
+
+// MIMEBundle maps MIME types to the representations of some data.
+type MIMEBundle map[string]interface{}
+
+// Error is the content of error messages, and part of replies with status "error".
+type Error struct {
+	EName     string   `json:"ename"`
+	EValue    string   `json:"evalue"`
+	Traceback []string `json:"traceback"`
+}
+
+// ErrorReply is the content of a reply with status "error".
+type ErrorReply struct {
+	Status string `json:"status"`
+	Error
+}
+
+// NewErrorReply returns the content of a reply to a request which failed with err.
+func NewErrorReply(ename string, err error) ErrorReply {
+	return ErrorReply{
+		Status: "error",
+		Error:  Error{EName: ename, EValue: err.Error(), Traceback: []string{err.Error()}},
+	}
+}
@@ -0,0 +1,55 @@
+package protocol
+
+import (
+	"testing"
+)
+
+func TestDecode_ExecuteRequest(t *testing.T) {
+	var req ExecuteRequest
+	err := Decode([]byte(`{"code": "1 + 1", "allow_stdin": false}`), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Code != "1 + 1" || req.Silent || !req.StoreHistory || req.AllowStdin || !req.StopOnError {
+		t.Errorf("missing fields should have their defaults: got %+v", req)
+	}
+
+	err = Decode([]byte(`{"code": "", "silent": true}`), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.StoreHistory {
+		t.Errorf("silent requests should not be stored in the history: got %+v", req)
+	}
+}
+
+func TestDecode_invalid(t *testing.T) {
+	tests := []struct {
+		content string
+		v       interface{}
+	}{
+		{`{"code": 1}`, &ExecuteRequest{}},
+		{`{"silent": "yes"}`, &ExecuteRequest{}},
+		{`{"code": "", "cursor_pos": -1}`, &CompleteRequest{}},
+		{`{"code": "", "cursor_pos": 0, "detail_level": 2}`, &InspectRequest{}},
+		{`{"target_name": "t"}`, &CommOpen{}},
+		{`{"data": {}}`, &CommMsg{}},
+	}
+
+	for _, test := range tests {
+		if err := Decode([]byte(test.content), test.v); err == nil {
+			t.Errorf("%s should be rejected as %T", test.content, test.v)
+		}
+	}
+}
+
+func TestNewHeader(t *testing.T) {
+	h := NewHeader("status", "session", "user")
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if h.Date == "" || h.Version != Version || h.Session != "session" {
+		t.Errorf("header should be complete: got %+v", h)
+	}
+}
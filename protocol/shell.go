@@ -0,0 +1,216 @@
+package protocol
+
+import (
+	"errors"
+)
+
+// ExecuteRequest is the content of an execute_request message.
+type ExecuteRequest struct {
+	Code            string            `json:"code"`
+	Silent          bool              `json:"silent"`
+	StoreHistory    bool              `json:"store_history"`
+	UserExpressions map[string]string `json:"user_expressions"`
+	AllowStdin      bool              `json:"allow_stdin"`
+	StopOnError     bool              `json:"stop_on_error"`
+}
+
+// SetDefaults sets the defaults given by the specification.
+func (req *ExecuteRequest) SetDefaults() {
+	req.StoreHistory = true
+	req.AllowStdin = true
+	req.StopOnError = true
+}
+
+// Validate applies the rule that silent requests are not stored in the history.
+func (req *ExecuteRequest) Validate() error {
+	if req.Silent {
+		req.StoreHistory = false
+	}
+	return nil
+}
+
+// ExecuteReply is the content of an execute_reply message with status "ok".
+type ExecuteReply struct {
+	Status          string                      `json:"status"`
+	ExecutionCount  int                         `json:"execution_count"`
+	Payload         []map[string]interface{}    `json:"payload"`
+	UserExpressions map[string]ExpressionResult `json:"user_expressions"`
+}
+
+// ExpressionResult is the result of a user expression in an execute_reply message:
+// its value, or with status "error", why it could not be evaluated.
+type ExpressionResult struct {
+	Status   string                 `json:"status"`
+	Data     MIMEBundle             `json:"data,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	*Error
+}
+
+// ExecuteErrorReply is the content of an execute_reply message with status "error".
+type ExecuteErrorReply struct {
+	Status         string `json:"status"`
+	ExecutionCount int    `json:"execution_count"`
+	Error
+}
+
+// AbortedReply is the content of a reply to a request which has not been handled,
+// e.g. an execute_request queued behind one which failed.
+type AbortedReply struct {
+	Status string `json:"status"`
+}
+
+// InspectRequest is the content of an inspect_request message.
+type InspectRequest struct {
+	Code        string `json:"code"`
+	CursorPos   int    `json:"cursor_pos"`
+	DetailLevel int    `json:"detail_level"`
+}
+
+// Validate checks the cursor position and detail level.
+func (req *InspectRequest) Validate() error {
+	if req.CursorPos < 0 {
+		return errors.New("negative cursor_pos")
+	}
+	if req.DetailLevel != 0 && req.DetailLevel != 1 {
+		return errors.New("detail_level must be 0 or 1")
+	}
+	return nil
+}
+
+// InspectReply is the content of an inspect_reply message.
+type InspectReply struct {
+	Status   string                 `json:"status"`
+	Found    bool                   `json:"found"`
+	Data     MIMEBundle             `json:"data"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// CompleteRequest is the content of a complete_request message.
+type CompleteRequest struct {
+	Code      string `json:"code"`
+	CursorPos int    `json:"cursor_pos"`
+}
+
+// Validate checks the cursor position.
+func (req *CompleteRequest) Validate() error {
+	if req.CursorPos < 0 {
+		return errors.New("negative cursor_pos")
+	}
+	return nil
+}
+
+// CompleteReply is the content of a complete_reply message.
+type CompleteReply struct {
+	Matches     []string               `json:"matches"`
+	CursorStart int                    `json:"cursor_start"`
+	CursorEnd   int                    `json:"cursor_end"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	Status      string                 `json:"status"`
+}
+
+// HistoryRequest is the content of a history_request message.
+type HistoryRequest struct {
+	Output         bool   `json:"output"`
+	Raw            bool   `json:"raw"`
+	HistAccessType string `json:"hist_access_type"`
+	Session        int    `json:"session"`
+	Start          int    `json:"start"`
+	Stop           int    `json:"stop"`
+	N              int    `json:"n"`
+	Pattern        string `json:"pattern"`
+	Unique         bool   `json:"unique"`
+}
+
+// HistoryReply is the content of a history_reply message. Its entries are
+// (session, line number, input) or (session, line number, (input, output)) tuples.
+type HistoryReply struct {
+	Status  string          `json:"status"`
+	History [][]interface{} `json:"history"`
+}
+
+// IsCompleteRequest is the content of an is_complete_request message.
+type IsCompleteRequest struct {
+	Code string `json:"code"`
+}
+
+// IsCompleteReply is the content of an is_complete_reply message.
+type IsCompleteReply struct {
+	Status string `json:"status"`
+	Indent string `json:"indent,omitempty"`
+}
+
+// ConnectRequest is the content of a connect_request message.
+type ConnectRequest struct{}
+
+// ConnectReply is the content of a connect_reply message.
+type ConnectReply struct {
+	ShellPort   int `json:"shell_port"`
+	IOPubPort   int `json:"iopub_port"`
+	StdinPort   int `json:"stdin_port"`
+	HBPort      int `json:"hb_port"`
+	ControlPort int `json:"control_port"`
+}
+
+// CommInfoRequest is the content of a comm_info_request message. If TargetName is
+// set, only the comms of that target are requested.
+type CommInfoRequest struct {
+	TargetName string `json:"target_name,omitempty"`
+}
+
+// CommInfo describes an open comm in a comm_info_reply message.
+type CommInfo struct {
+	TargetName string `json:"target_name"`
+}
+
+// CommInfoReply is the content of a comm_info_reply message.
+type CommInfoReply struct {
+	Status string              `json:"status"`
+	Comms  map[string]CommInfo `json:"comms"`
+}
+
+// KernelInfoRequest is the content of a kernel_info_request message.
+type KernelInfoRequest struct{}
+
+// KernelInfoReply is the content of a kernel_info_reply message.
+type KernelInfoReply struct {
+	Status                string       `json:"status"`
+	ProtocolVersion       string       `json:"protocol_version"`
+	Implementation        string       `json:"implementation"`
+	ImplementationVersion string       `json:"implementation_version"`
+	LanguageInfo          LanguageInfo `json:"language_info"`
+	Banner                string       `json:"banner"`
+	HelpLinks             []HelpLink   `json:"help_links,omitempty"`
+}
+
+// LanguageInfo describes the language of the kernel in a kernel_info_reply message.
+type LanguageInfo struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	Mimetype      string `json:"mimetype"`
+	FileExtension string `json:"file_extension"`
+}
+
+// HelpLink is a link shown in the help menu of the frontend.
+type HelpLink struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// ShutdownRequest is the content of a shutdown_request message.
+type ShutdownRequest struct {
+	Restart bool `json:"restart"`
+}
+
+// ShutdownReply is the content of a shutdown_reply message.
+type ShutdownReply struct {
+	Status  string `json:"status"`
+	Restart bool   `json:"restart"`
+}
+
+// InterruptRequest is the content of an interrupt_request message.
+type InterruptRequest struct{}
+
+// InterruptReply is the content of an interrupt_reply message.
+type InterruptReply struct {
+	Status string `json:"status"`
+}
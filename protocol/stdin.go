@@ -0,0 +1,12 @@
+package protocol
+
+// InputRequest is the content of an input_request message.
+type InputRequest struct {
+	Prompt   string `json:"prompt"`
+	Password bool   `json:"password"`
+}
+
+// InputReply is the content of an input_reply message.
+type InputReply struct {
+	Value string `json:"value"`
+}
@@ -0,0 +1,226 @@
+package replpkg
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/parser"
+	"go/types"
+)
+
+// cellSnapshot is the session source before the statements of a cell have been
+// replaced or dropped, which the session returns to if that fails.
+type cellSnapshot struct {
+	source string
+	stmts  []*origin          // the origins of the statements of main, by index
+	decls  map[string]*origin // the origins of the declarations, by key
+}
+
+func (s *Session) takeSnapshot() (*cellSnapshot, error) {
+	source, err := s.source(false)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &cellSnapshot{source: source, decls: s.declOrigins(s.File)}
+	for _, stmt := range s.mainBody.List {
+		snap.stmts = append(snap.stmts, s.origins[stmt])
+	}
+	return snap, nil
+}
+
+func (s *Session) restoreSnapshot(snap *cellSnapshot) {
+	file, err := parser.ParseFile(s.Fset, "gore_session.go", snap.source, parser.Mode(0))
+	if err != nil {
+		errorf("restore: %s", err)
+		return
+	}
+
+	s.File = file
+	s.mainBody = s.mainFunc().Body
+	s.origins = make(map[ast.Node]*origin)
+	for i, stmt := range s.mainBody.List {
+		if i < len(snap.stmts) && snap.stmts[i] != nil {
+			s.origins[stmt] = snap.stmts[i]
+		}
+	}
+	for _, decl := range file.Decls {
+		if o := snap.decls[declKey(decl)]; o != nil {
+			s.origins[decl] = o
+		}
+	}
+}
+
+// replaceCell drops the statements and declarations of the earlier runs of the
+// cell with the ID s.CellID, if any, so that the cell replaces them in place: the
+// statements following them are returned, detached from main until appendTail
+// appends them to those of the cell. restoreMainBody brings the statements dropped
+// back.
+func (s *Session) replaceCell() []ast.Stmt {
+	match := func(o *origin) bool {
+		return o.id == s.CellID
+	}
+	if s.CellID == "" || !s.hasCell(match) {
+		return nil
+	}
+
+	snap, err := s.takeSnapshot()
+	if err != nil {
+		errorf("replace: %s", err)
+		return nil
+	}
+	s.snapshot = snap
+
+	at := s.dropCell(match)
+	tail := append([]ast.Stmt(nil), s.mainBody.List[at:]...)
+	s.mainBody.List = s.mainBody.List[:at]
+	s.storedBodyLength = at
+	return tail
+}
+
+// appendTail appends the statements detached by replaceCell to main again,
+// renaming what the cell declares which they declare again.
+func (s *Session) appendTail(tail []ast.Stmt) {
+	if len(tail) == 0 {
+		return
+	}
+
+	first := len(s.mainBody.List)
+	s.appendStatements(tail...)
+	s.shadowRedeclared(first)
+}
+
+// hasCell reports whether the session holds statements or declarations of a cell
+// whose origin matches match.
+func (s *Session) hasCell(match func(*origin) bool) bool {
+	for _, o := range s.origins {
+		if match(o) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropCell removes the statements and declarations whose origins match match,
+// and returns the index in main of the first statement removed, or the number of
+// statements left if there was none. The statements left refer to what those
+// removed declared under names renamed by shadowRedeclared by their names again.
+func (s *Session) dropCell(match func(*origin) bool) int {
+	at := -1
+	renamed := make(map[string]string)
+	var kept []ast.Stmt
+	for _, stmt := range s.mainBody.List {
+		if o := s.origins[stmt]; o != nil && match(o) {
+			if at < 0 {
+				at = len(kept)
+			}
+			for _, ident := range declaredIdents(stmt) {
+				if name, ok := shadowedName(ident.Name); ok {
+					renamed[ident.Name] = name
+				}
+			}
+			delete(s.origins, stmt)
+			continue
+		}
+		kept = append(kept, stmt)
+	}
+	s.mainBody.List = kept
+	if at < 0 {
+		at = len(kept)
+	}
+
+	decls := s.File.Decls[:0]
+	for _, decl := range s.File.Decls {
+		if o := s.origins[decl]; o != nil && match(o) {
+			delete(s.origins, decl)
+			continue
+		}
+		decls = append(decls, decl)
+	}
+	s.File.Decls = decls
+
+	if len(renamed) > 0 {
+		ast.Inspect(s.mainBody, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok && renamed[ident.Name] != "" {
+				ident.Name = renamed[ident.Name]
+			}
+			return true
+		})
+	}
+
+	return at
+}
+
+// DropCell removes the statements and declarations of a cell, given by its number
+// or its ID, from the session. It returns an error, keeping them, if the cells
+// following it use what it declares.
+func (s *Session) DropCell(cell string) error {
+	match := func(o *origin) bool {
+		return o.id == cell || strconv.Itoa(o.cell) == cell
+	}
+	if !s.hasCell(match) {
+		return fmt.Errorf("no cell %s", cell)
+	}
+
+	snap, err := s.takeSnapshot()
+	if err != nil {
+		return err
+	}
+	s.dropCell(match)
+
+	if err := s.checkSession(); err != nil {
+		s.restoreSnapshot(snap)
+		return fmt.Errorf("cannot drop cell %s: %s", cell, err)
+	}
+	return nil
+}
+
+// checkSession type checks the session source, and returns its first error other
+// than unused variables and imports.
+func (s *Session) checkSession() error {
+	if err := s.reset(); err != nil {
+		return err
+	}
+
+	var first error
+	config := types.Config{
+		Importer: s.Types.Importer,
+		Error: func(err error) {
+			if err, ok := err.(types.Error); ok && !err.Soft && first == nil {
+				first = fmt.Errorf("%s", err.Msg)
+			}
+		},
+	}
+	config.Check("main", s.Fset, append(s.ExtraFiles[:len(s.ExtraFiles):len(s.ExtraFiles)], s.File), nil)
+	return first
+}
+
+// cellLines returns a line for every cell the session holds statements or
+// declarations of, in the order they have been run: its number and the first line
+// of its code.
+func (s *Session) cellLines() []string {
+	byEval := make(map[int]*origin)
+	for _, o := range s.origins {
+		byEval[o.eval] = o
+	}
+
+	evals := make([]int, 0, len(byEval))
+	for eval := range byEval {
+		evals = append(evals, eval)
+	}
+	sort.Ints(evals)
+
+	lines := make([]string, len(evals))
+	for i, eval := range evals {
+		o := byEval[eval]
+		code := strings.TrimSpace(o.src)
+		if nl := strings.IndexByte(code, '\n'); nl >= 0 {
+			code = code[:nl] + " …"
+		}
+		lines[i] = fmt.Sprintf("[%d] %s", o.cell, code)
+	}
+	return lines
+}
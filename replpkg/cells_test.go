@@ -0,0 +1,77 @@
+package replpkg
+
+import (
+	"testing"
+)
+
+func TestRun_cellID(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+
+	cells := []struct{ id, code, out string }{
+		{"a", `x := 1`, "1\n"},
+		{"b", `y := x * 10; println(y)`, ""},
+		{"a", `x := 2`, "2\n"},
+		{"a", `x := 3`, "3\n"},
+		{"c", `x + y`, "33\n"},
+	}
+	for i, cell := range cells {
+		s.Cell, s.CellID = i+1, cell.id
+		out, err, _ := s.Eval(cell.code)
+		noError(t, err)
+		if out != cell.out {
+			t.Errorf("%s: got %q, want %q", cell.code, out, cell.out)
+		}
+	}
+	n := 0
+	for _, stmt := range s.mainBody.List {
+		if s.origins[stmt].id == "a" {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("the cell should have replaced its statements: %d statements", n)
+	}
+
+	s.Cell, s.CellID = 6, "a"
+	_, err, _ = s.Eval(`x := "s"`)
+	if _, ok := err.(*CompileError); !ok {
+		t.Fatalf("should be a compile error: %#v", err)
+	}
+	s.Cell, s.CellID = 7, "d"
+	out, err, _ := s.Eval(`x + y`)
+	noError(t, err)
+	if out != "33\n" {
+		t.Errorf("the replaced statements should have been restored: %q", out)
+	}
+}
+
+func TestAction_Cells(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+
+	for i, code := range []string{"x := 1", "y := x + 1; println(y)"} {
+		s.Cell = i + 1
+		_, err, _ := s.Eval(code)
+		noError(t, err)
+	}
+
+	_, err, _ = s.Eval(`:cells`)
+	noError(t, err)
+	if out := <-s.StdoutChannel; out != "[1] x := 1\n[2] y := x + 1; println(y)" {
+		t.Errorf("got %q", out)
+	}
+
+	if err := s.DropCell("1"); err == nil {
+		t.Errorf("cell 1 should not be dropped, as y uses x")
+	}
+	_, err, _ = s.Eval(`:cells drop 2`)
+	noError(t, err)
+	for _, stmt := range s.mainBody.List {
+		if o := s.origins[stmt]; o != nil && o.cell == 2 {
+			t.Errorf("cell 2 should have been dropped: %s", showNode(s.Fset, stmt))
+		}
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
 
 	"go/ast"
 	"go/build"
+	"go/parser"
 	"go/types"
 	"golang.org/x/tools/go/ast/astutil"
 )
@@ -61,6 +63,19 @@ func init() {
 			arg:      "<expr or pkg>",
 			document: "show documentation",
 		},
+		{
+			name:     "printer",
+			action:   actionPrinter,
+			complete: completePrinter,
+			arg:      "[pp|spew|fmt] [depth=<n>] [length=<n>]",
+			document: "set how values are printed and limit their depth and length",
+		},
+		{
+			name:     "cells",
+			action:   actionCells,
+			arg:      "[drop <cell>]",
+			document: "list the cells run, or drop a cell given by its number",
+		},
 		{
 			name:     "help",
 			action:   actionHelp,
@@ -202,6 +217,72 @@ func actionWrite(s *Session, filename string) error {
 }
 
 func actionDoc(s *Session, in string) error {
+	expr, err := s.checkExpr(in)
+	if err != nil {
+		return err
+	}
+
+	docObj := s.docObject(expr)
+	if docObj == nil {
+		return fmt.Errorf("cannot determine the document location")
+	}
+
+	debugf("doc :: obj=%#v", docObj)
+
+	var pkgPath, objName string
+	if pkgName, ok := docObj.(*types.PkgName); ok {
+		pkgPath = pkgName.Imported().Path()
+	} else {
+		if pkg := docObj.Pkg(); pkg != nil {
+			pkgPath = pkg.Path()
+		} else {
+			pkgPath = "builtin"
+		}
+		objName = docObj.Name()
+	}
+
+	debugf("doc :: %q %q", pkgPath, objName)
+
+	args := []string{pkgPath}
+	if objName != "" {
+		args = append(args, objName)
+	}
+
+	godoc := exec.Command("godoc", args...)
+	godoc.Stderr = os.Stderr
+
+	// TODO just use PAGER?
+	if pagerCmd := os.Getenv("GORE_PAGER"); pagerCmd != "" {
+		r, err := godoc.StdoutPipe()
+		if err != nil {
+			return err
+		}
+
+		pager := exec.Command(pagerCmd)
+		pager.Stdin = r
+		pager.Stdout = os.Stdout
+		pager.Stderr = os.Stderr
+
+		err = pager.Start()
+		if err != nil {
+			return err
+		}
+
+		err = godoc.Run()
+		if err != nil {
+			return err
+		}
+
+		return pager.Wait()
+	} else {
+		godoc.Stdout = os.Stdout
+		return godoc.Run()
+	}
+}
+
+// checkExpr type checks the expression in within the current session.
+// The resulting types are left in s.TypeInfo.
+func (s *Session) checkExpr(in string) (ast.Expr, error) {
 	s.clearQuickFix()
 
 	s.storeMainBody()
@@ -209,7 +290,7 @@ func actionDoc(s *Session, in string) error {
 
 	expr, err := s.evalExpr(in)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	s.TypeInfo = types.Info{
@@ -218,11 +299,16 @@ func actionDoc(s *Session, in string) error {
 		Defs:   make(map[*ast.Ident]types.Object),
 		Scopes: make(map[ast.Node]*types.Scope),
 	}
-	_, err = s.Types.Check("_tmp", s.Fset, []*ast.File{s.File}, &s.TypeInfo)
+	_, err = s.Types.Check(checkPkgPath, s.Fset, []*ast.File{s.File}, &s.TypeInfo)
 	if err != nil {
 		debugf("typecheck error (ignored): %s", err)
 	}
 
+	return expr, nil
+}
+
+// docObject resolves the object documented by the type checked expression expr.
+func (s *Session) docObject(expr ast.Expr) types.Object {
 	// :doc patterns:
 	// - "json" -> "encoding/json" (package name)
 	// - "json.Encoder" -> "encoding/json", "Encoder" (package member)
@@ -252,61 +338,122 @@ func actionDoc(s *Session, in string) error {
 		_, docObj = mainScope.LookupParent(ident.Name, ident.NamePos)
 	}
 
-	if docObj == nil {
-		return fmt.Errorf("cannot determine the document location")
-	}
+	return docObj
+}
 
-	debugf("doc :: obj=%#v", docObj)
+// actionPrinter selects the package printing the text of values and the limits
+// of the values shown, or shows the current settings if there are no arguments.
+// The limits apply to the tables and trees of package display, and to the depth
+// of the values printed by spew; zero means no limit.
+func actionPrinter(s *Session, arg string) error {
+	settings := s.printer
+	for _, field := range strings.Fields(arg) {
+		if i := strings.Index(field, "="); i >= 0 {
+			n, err := strconv.Atoi(field[i+1:])
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid limit %q", field)
+			}
 
-	var pkgPath, objName string
-	if pkgName, ok := docObj.(*types.PkgName); ok {
-		pkgPath = pkgName.Imported().Path()
-	} else {
-		if pkg := docObj.Pkg(); pkg != nil {
-			pkgPath = pkg.Path()
-		} else {
-			pkgPath = "builtin"
+			switch field[:i] {
+			case "depth":
+				settings.maxDepth = n
+			case "length":
+				settings.maxLength = n
+			default:
+				return fmt.Errorf("unknown limit %q", field[:i])
+			}
+			continue
+		}
+
+		var found bool
+		for _, pp := range printerPkgs {
+			if pp.name == field {
+				settings.pkg, found = pp, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown printer %q", field)
+		}
+		if _, err := s.Types.Importer.Import(settings.pkg.path); err != nil {
+			return err
 		}
-		objName = docObj.Name()
 	}
 
-	debugf("doc :: %q %q", pkgPath, objName)
+	if settings.pkg.path != s.printer.pkg.path {
+		if err := s.setPrinterCode(settings.pkg); err != nil {
+			return err
+		}
+	}
+	s.printer = settings
 
-	args := []string{pkgPath}
-	if objName != "" {
-		args = append(args, objName)
+	if arg == "" {
+		text := fmt.Sprintf("printer %s, depth=%d, length=%d", settings.pkg.name, settings.maxDepth, settings.maxLength)
+		s.StdoutChannel <- text
+		fmt.Println(text)
 	}
 
-	godoc := exec.Command("godoc", args...)
-	godoc.Stderr = os.Stderr
+	return nil
+}
 
-	// TODO just use PAGER?
-	if pagerCmd := os.Getenv("GORE_PAGER"); pagerCmd != "" {
-		r, err := godoc.StdoutPipe()
-		if err != nil {
-			return err
-		}
+// setPrinterCode makes the printer function of the session print with pp.
+func (s *Session) setPrinterCode(pp printerPkg) error {
+	code, err := parser.ParseExpr(pp.code)
+	if err != nil {
+		return err
+	}
+	normalizeNodePos(code)
 
-		pager := exec.Command(pagerCmd)
-		pager.Stdin = r
-		pager.Stdout = os.Stdout
-		pager.Stderr = os.Stderr
+	obj := s.File.Scope.Lookup(printerName)
+	if obj == nil {
+		return fmt.Errorf("no printer function")
+	}
 
-		err = pager.Start()
-		if err != nil {
-			return err
+	var replaced bool
+	ast.Inspect(obj.Decl.(*ast.FuncDecl).Body, func(node ast.Node) bool {
+		if loop, ok := node.(*ast.RangeStmt); ok && !replaced {
+			loop.Body.List = []ast.Stmt{&ast.ExprStmt{X: code}}
+			replaced = true
 		}
+		return !replaced
+	})
+	if !replaced {
+		return fmt.Errorf("no printer loop")
+	}
 
-		err = godoc.Run()
-		if err != nil {
-			return err
+	astutil.AddImport(s.Fset, s.File, pp.path)
+	return nil
+}
+
+func completePrinter(s *Session, prefix string) []string {
+	var result []string
+	for _, pp := range printerPkgs {
+		if strings.HasPrefix(pp.name, prefix) {
+			result = append(result, pp.name)
 		}
+	}
+	return result
+}
 
-		return pager.Wait()
-	} else {
-		godoc.Stdout = os.Stdout
-		return godoc.Run()
+// actionCells lists the cells whose statements and declarations the session
+// holds, or drops one of them.
+func actionCells(s *Session, arg string) error {
+	fields := strings.Fields(arg)
+	switch {
+	case len(fields) == 2 && fields[0] == "drop":
+		return s.DropCell(strings.Trim(fields[1], "[]"))
+	case len(fields) > 0:
+		return fmt.Errorf("usage: :cells [drop <cell>]")
 	}
+
+	text := strings.Join(s.cellLines(), "\n")
+	if text == "" {
+		text = "no cells"
+	}
+	s.StdoutChannel <- text
+	fmt.Println(text)
+
+	return nil
 }
 
 func actionHelp(s *Session, _ string) error {
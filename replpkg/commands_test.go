@@ -39,3 +39,23 @@ func TestActionDoc(t *testing.T) {
 
 	test()
 }
+
+func TestAction_Printer(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	_, err, _ = s.Eval(`:printer fmt depth=3 length=7`)
+	noError(t, err)
+	_, err, _ = s.Eval(`:printer`)
+	noError(t, err)
+
+	if out := <-s.StdoutChannel; out != "printer fmt, depth=3, length=7" {
+		t.Errorf("got %q", out)
+	}
+
+	out, err, _ := s.Eval(`[]int{1, 2}`)
+	noError(t, err)
+	if out != "[]int{1, 2}\n" {
+		t.Errorf("values should still be printed with fmt: %q", out)
+	}
+}
@@ -89,3 +89,32 @@ func (s *Session) completeCode(in string, pos int, exprMode bool) (keep int, can
 
 	return
 }
+
+// Complete returns completion candidates for the cell source in with the cursor at
+// byte offset pos. Candidates replace in[start:end]. Lines starting with ":" are
+// completed as commands and their arguments, anything else as Go code.
+func (s *Session) Complete(in string, pos int) (candidates []string, start, end int) {
+	lineStart := strings.LastIndex(in[0:pos], "\n") + 1
+	lineEnd := len(in)
+	if i := strings.Index(in[pos:], "\n"); i != -1 {
+		lineEnd = pos + i
+	}
+
+	line := in[lineStart:lineEnd]
+	if strings.HasPrefix(line, ":") {
+		head, cands, _ := s.completeWord(line, pos-lineStart)
+		return cands, lineStart + len(head), pos
+	}
+
+	if gocode.Available() == false {
+		return nil, pos, pos
+	}
+
+	keep, cands, err := s.completeCode(in, pos, true)
+	if err != nil {
+		errorf("completeCode: %s", err)
+		return nil, pos, pos
+	}
+
+	return cands, keep, pos
+}
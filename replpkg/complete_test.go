@@ -28,3 +28,22 @@ func TestSession_completeCode(t *testing.T) {
 
 	stringsContain(t, cands, "Println(")
 }
+
+func TestSession_Complete_command(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	code := "a := 1\n:imp"
+	cands, start, end := s.Complete(code, len(code))
+	if start != 7 || end != len(code) {
+		t.Errorf("replaced range should be [7:%d]: got [%d:%d]", len(code), start, end)
+	}
+	stringsContain(t, cands, ":import ")
+
+	code = ":import encoding/js"
+	cands, start, _ = s.Complete(code, len(code))
+	if start != len(":import ") {
+		t.Errorf("start should be == %d: got %d", len(":import "), start)
+	}
+	stringsContain(t, cands, "encoding/json")
+}
@@ -0,0 +1,156 @@
+package replpkg
+
+import (
+	"bytes"
+	"strings"
+	"text/scanner"
+	"unicode"
+
+	"go/parser"
+	goscanner "go/scanner"
+	"go/token"
+)
+
+// Statuses returned by IsComplete.
+const (
+	StatusComplete   = "complete"
+	StatusIncomplete = "incomplete"
+	StatusInvalid    = "invalid"
+)
+
+// countDepth returns the nesting depth of braces at the end of src.
+func countDepth(src string) int {
+	reader := bytes.NewBufferString(src)
+	sc := new(scanner.Scanner)
+	sc.Init(reader)
+	sc.Error = func(_ *scanner.Scanner, msg string) {
+		debugf("scanner: %s", msg)
+	}
+
+	depth := 0
+	for {
+		switch sc.Scan() {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case scanner.EOF:
+			return depth
+		}
+	}
+}
+
+// IsComplete reports whether in is ready to be evaluated (StatusComplete), needs more
+// lines (StatusIncomplete) or cannot become valid input (StatusInvalid). For incomplete
+// input, nextIndent is the indentation suggested for the next line.
+func IsComplete(in string) (status, nextIndent string) {
+	in = strings.TrimRightFunc(in, unicode.IsSpace)
+	if in == "" || strings.HasPrefix(in, ":") {
+		return StatusComplete, ""
+	}
+
+	depth := countDepth(in)
+	if depth < 0 {
+		return StatusInvalid, ""
+	}
+	if depth > 0 {
+		return StatusIncomplete, strings.Repeat(indent, depth)
+	}
+
+	if _, err := parser.ParseExpr(in); err == nil {
+		return StatusComplete, ""
+	}
+	if _, err := parseCell(in, declsPrefix); err == nil {
+		return StatusComplete, ""
+	}
+
+	first, err := parseCell(in, stmtPrefix)
+	if err == nil {
+		return StatusComplete, ""
+	}
+	if _, _, ok := splitDecls(in); ok {
+		return StatusComplete, ""
+	}
+
+	// an error at the end of input, such as in "x :=" or an unterminated raw string,
+	// can still be fixed by further lines
+	if first.Pos.Offset >= len(in) || strings.HasSuffix(first.Msg, "not terminated") {
+		return StatusIncomplete, ""
+	}
+
+	return StatusInvalid, ""
+}
+
+// Prefixes of the code of a cell parsed as a list of declarations or as the body
+// of a function.
+const (
+	declsPrefix = "package P; "
+	stmtPrefix  = "package P; func F() { "
+)
+
+// parseCell parses in after prefix, and returns its first syntax error, if any,
+// with the offset of its position in in.
+func parseCell(in, prefix string) (*goscanner.Error, error) {
+	src := prefix + in
+	if prefix == stmtPrefix {
+		src += "\n}"
+	}
+	_, err := parser.ParseFile(token.NewFileSet(), "cell.go", src, parser.Mode(0))
+	errList, ok := err.(goscanner.ErrorList)
+	if !ok || len(errList) == 0 {
+		return nil, err
+	}
+
+	first := *errList[0]
+	first.Pos.Offset -= len(prefix)
+	if first.Pos.Offset < 0 {
+		first.Pos.Offset = 0
+	}
+	return &first, err
+}
+
+// splitDecls splits in, the code of a cell, into declarations followed by
+// statements, as in a cell declaring a function and calling it. The declarations
+// are blanked out of the statements returned, so that the offsets in both are those
+// in in.
+func splitDecls(in string) (decls, stmts string, ok bool) {
+	first, err := parseCell(in, declsPrefix)
+	if err == nil || first == nil || first.Pos.Offset == 0 || first.Pos.Offset >= len(in) {
+		return "", "", false
+	}
+
+	decls = in[:first.Pos.Offset]
+	blank := []byte(decls)
+	for i, b := range blank {
+		if b != '\n' {
+			blank[i] = ' '
+		}
+	}
+	stmts = string(blank) + in[first.Pos.Offset:]
+
+	if _, err := parseCell(decls, declsPrefix); err != nil {
+		return "", "", false
+	}
+	if _, err := parseCell(stmts, stmtPrefix); err != nil {
+		return "", "", false
+	}
+	return decls, stmts, true
+}
+
+// syntaxError returns the error of the code in of a cell which is neither an
+// expression, nor statements, nor declarations, nor declarations followed by
+// statements: the first syntax error of the parse getting further into in,
+// located in the cell.
+func (s *Session) syntaxError(in string) *CompileError {
+	first, _ := parseCell(in, stmtPrefix)
+	if decls, _ := parseCell(in, declsPrefix); first == nil || decls != nil && decls.Pos.Offset > first.Pos.Offset {
+		first = decls
+	}
+	if first == nil {
+		return &CompileError{}
+	}
+
+	line, col, text := offsetLineCol(in, first.Pos.Offset)
+	pos := CellPos{Cell: s.Cell, Line: line, Col: col, Source: text}
+	return &CompileError{Errors: []CellError{{CellPos: pos, Msg: "syntax error: " + first.Msg}}}
+}
@@ -0,0 +1,35 @@
+package replpkg
+
+import (
+	"testing"
+)
+
+func TestIsComplete(t *testing.T) {
+	tests := []struct {
+		in     string
+		status string
+		indent string
+	}{
+		{"", StatusComplete, ""},
+		{"a := 1", StatusComplete, ""},
+		{":import fmt", StatusComplete, ""},
+		{`import "fmt"`, StatusComplete, ""},
+		{"func add(a, b int) int { return a + b }", StatusComplete, ""},
+		{"func (p point) Sum() int { return p.X + p.Y }", StatusComplete, ""},
+		{"for i := 0; i < 3; i++ {", StatusIncomplete, "    "},
+		{"if x {\n\tfor {", StatusIncomplete, "        "},
+		{"a :=", StatusIncomplete, ""},
+		{"s := `multi\nline", StatusIncomplete, ""},
+		{"}", StatusInvalid, ""},
+		{"a := 1)", StatusInvalid, ""},
+		{"func f() int { return 1 }\nf()", StatusComplete, ""},
+		{"func f() int { return 1 }\nf())", StatusInvalid, ""},
+	}
+
+	for _, test := range tests {
+		status, indent := IsComplete(test.in)
+		if status != test.status || indent != test.indent {
+			t.Errorf("IsComplete(%q) should be %q, %q: got %q, %q", test.in, test.status, test.indent, status, indent)
+		}
+	}
+}
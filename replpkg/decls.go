@@ -0,0 +1,268 @@
+package replpkg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// errNotDecls is returned by evalDecls for code which is evaluated as statements
+// of main.
+var errNotDecls = errors.New("not a list of declarations")
+
+// evalDecls adds the declarations of in, the code of a cell, at package scope,
+// replacing the earlier declarations of the names it declares, and merges its
+// imports into those of the session. It returns the declarations added, which are
+// none if in only imports packages.
+//
+// Declarations of variables and constants on their own are statements of main,
+// which may use the variables of earlier cells, unless they declare names declared
+// at package scope again; errNotDecls is returned for them, as for code which is
+// no list of declarations and for types using names declared in main.
+func (s *Session) evalDecls(in string) ([]ast.Decl, error) {
+	const prefix = declsPrefix
+	f, err := parser.ParseFile(s.Fset, "decls.go", prefix+in, parser.Mode(0))
+	if err != nil || len(f.Decls) == 0 {
+		return nil, errNotDecls
+	}
+
+	var decls []ast.Decl
+	var names []string
+	var hasFunc, hasType bool
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			hasFunc = true
+		case *ast.GenDecl:
+			if decl.Tok == token.IMPORT {
+				continue
+			}
+			if decl.Tok == token.TYPE {
+				hasType = true
+			}
+		}
+		decls = append(decls, decl)
+		names = append(names, declNames(decl)...)
+	}
+
+	for _, name := range names {
+		if name == "main" || name == printerName {
+			return nil, fmt.Errorf("%s cannot be declared again", name)
+		}
+	}
+
+	if len(decls) > 0 && !hasFunc {
+		declared := s.packageNames()
+		redeclares := false
+		for _, name := range names {
+			redeclares = redeclares || declared[name]
+		}
+		if !redeclares && (!hasType || s.usesMainNames(decls)) {
+			return nil, errNotDecls
+		}
+	}
+
+	for _, imp := range f.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		name := ""
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		astutil.AddNamedImport(s.Fset, s.File, name, path)
+	}
+
+	for _, removed := range s.removeDecls(names) {
+		s.replaced = append(s.replaced, removedDecl{removed, s.origins[removed]})
+	}
+	for _, decl := range decls {
+		s.origins[decl] = s.newOrigin(in, s.Fset, decl, len(prefix))
+		s.insertDecl(decl)
+	}
+	s.declared = append(s.declared, names...)
+
+	return decls, nil
+}
+
+// evalDeclsStmts adds the code in of a cell made of declarations followed by
+// statements, as split by splitDecls, to the session source.
+func (s *Session) evalDeclsStmts(in, decls, stmts string) error {
+	if _, err := s.evalDecls(decls); err == errNotDecls {
+		return s.syntaxError(in)
+	} else if err != nil {
+		return err
+	}
+	if err := s.evalStmt(stmts); err != nil {
+		return err
+	}
+
+	// the statements and declarations come from the whole cell
+	for _, o := range s.origins {
+		if o.eval == s.evals {
+			o.src = in
+		}
+	}
+	return nil
+}
+
+// removedDecl is a declaration replaced by the last call to Eval, and its origin.
+type removedDecl struct {
+	decl   ast.Decl
+	origin *origin
+}
+
+// insertDecl adds decl to the session source, before main.
+func (s *Session) insertDecl(decl ast.Decl) {
+	main := s.mainFunc()
+	for i, d := range s.File.Decls {
+		if d == main {
+			s.File.Decls = append(s.File.Decls[:i], append([]ast.Decl{decl}, s.File.Decls[i:]...)...)
+			return
+		}
+	}
+	s.File.Decls = append(s.File.Decls, decl)
+}
+
+// removeDecls removes the declarations of the session source which declare any of
+// names, and returns them. A grouped declaration is removed as a whole.
+func (s *Session) removeDecls(names []string) []ast.Decl {
+	remove := make(map[string]bool)
+	for _, name := range names {
+		remove[name] = true
+	}
+
+	var removed []ast.Decl
+	decls := s.File.Decls[:0]
+	for _, decl := range s.File.Decls {
+		found := false
+		for _, name := range declNames(decl) {
+			found = found || remove[name]
+		}
+		if found {
+			removed = append(removed, decl)
+		} else {
+			decls = append(decls, decl)
+		}
+	}
+	s.File.Decls = decls
+	return removed
+}
+
+// restoreDecls undoes the changes of the last call to Eval to the declarations at
+// package scope.
+func (s *Session) restoreDecls() {
+	s.removeDecls(s.declared)
+	for _, r := range s.replaced {
+		s.insertDecl(r.decl)
+		if r.origin != nil {
+			s.origins[r.decl] = r.origin
+		}
+	}
+	s.declared, s.replaced = nil, nil
+}
+
+// packageNames returns the names declared at package scope by the session source.
+func (s *Session) packageNames() map[string]bool {
+	names := make(map[string]bool)
+	for _, decl := range s.File.Decls {
+		for _, name := range declNames(decl) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// usesMainNames reports whether decls use a name declared by the statements of
+// main, which is not visible at package scope.
+func (s *Session) usesMainNames(decls []ast.Decl) bool {
+	local := make(map[string]bool)
+	for _, stmt := range s.mainBody.List {
+		for _, ident := range declaredIdents(stmt) {
+			local[ident.Name] = true
+		}
+	}
+
+	uses := false
+	for _, decl := range decls {
+		ast.Inspect(decl, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok && local[ident.Name] {
+				uses = true
+			}
+			return !uses
+		})
+	}
+	return uses
+}
+
+// declNames returns the names decl declares at package scope, leaving out blank
+// ones. Methods are named after their receiver type, as in T.M; imports and init
+// functions declare none.
+func declNames(decl ast.Decl) []string {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Recv == nil {
+			if decl.Name.Name == "init" {
+				return nil
+			}
+			return []string{decl.Name.Name}
+		}
+		if len(decl.Recv.List) == 1 {
+			if recv := recvTypeName(decl.Recv.List[0].Type); recv != "" {
+				return []string{recv + "." + decl.Name.Name}
+			}
+		}
+	case *ast.GenDecl:
+		var names []string
+		for _, spec := range decl.Specs {
+			for _, name := range specNames(spec) {
+				if name != "_" {
+					names = append(names, name)
+				}
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// specNames returns the names declared by spec, which is no import.
+func specNames(spec ast.Spec) []string {
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		return []string{spec.Name.Name}
+	case *ast.ValueSpec:
+		names := make([]string, len(spec.Names))
+		for i, name := range spec.Names {
+			names[i] = name.Name
+		}
+		return names
+	}
+	return nil
+}
+
+// recvTypeName returns the name of the type of the receiver expression expr.
+func recvTypeName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
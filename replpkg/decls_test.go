@@ -0,0 +1,78 @@
+package replpkg
+
+import (
+	"testing"
+)
+
+func TestRun_decls(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+
+	codes := []string{
+		"import \"strings\"\n\nfunc shout(s string) string {\n\treturn strings.ToUpper(s) + \"!\"\n}",
+		"type point struct{ X, Y int }\n\nfunc (p point) Sum() int { return p.X + p.Y }",
+		`func shout(s string) string { return strings.TrimSpace(s) + "?" }`,
+	}
+	for _, code := range codes {
+		_, err, _ := s.Eval(code)
+		noError(t, err)
+	}
+
+	s.Cell = 4
+	_, err, _ = s.Eval("func shout(s string) int {\n\treturn strings.Count(s, undefinedName)\n}")
+	compileErr, ok := err.(*CompileError)
+	if !ok {
+		t.Fatalf("should be a compile error: %#v", err)
+	}
+	if cellErr := compileErr.Errors[0]; cellErr.Cell != 4 || cellErr.Line != 2 || cellErr.Col != 26 {
+		t.Errorf("the error should be located in the cell: %+v", cellErr)
+	}
+
+	out, err, _ := s.Eval(`point{1, 2}.Sum() + len(shout(" hi"))`)
+	noError(t, err)
+	if out != "6\n" {
+		t.Errorf("shout should have been replaced, then restored: %q", out)
+	}
+}
+
+func TestRun_declsImport(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+
+	out, err, _ := s.Eval(`import "strings"`)
+	noError(t, err)
+	if out != "" {
+		t.Errorf("importing should not output: %q", out)
+	}
+
+	out, err, _ = s.Eval(`strings.ToUpper("go")`)
+	noError(t, err)
+	if out != "\"GO\"\n" {
+		t.Errorf("strings should have been imported: %q", out)
+	}
+}
+
+func TestRun_declsAndStmts(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+
+	s.Cell = 1
+	out, err, _ := s.Eval("import \"strings\"\n\nfunc f() string { return strings.Repeat(\"a\", 2) }\nx := f()")
+	noError(t, err)
+	if out != "\"aa\"\n" {
+		t.Errorf("f should have been declared and called: %q", out)
+	}
+
+	s.Cell = 2
+	_, err, _ = s.Eval("func g() int { return 2 }\n\nprintln(x, g()+undefinedName)")
+	compileErr, ok := err.(*CompileError)
+	if !ok {
+		t.Fatalf("should be a compile error: %#v", err)
+	}
+	if cellErr := compileErr.Errors[0]; cellErr.Cell != 2 || cellErr.Line != 3 || cellErr.Col != 16 {
+		t.Errorf("the error should be located in the cell: %+v", cellErr)
+	}
+}
@@ -0,0 +1,139 @@
+package replpkg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go/printer"
+
+	"github.com/fabian-z/gopherlab/ipc"
+)
+
+// Evaluator runs the statements of a session for Eval. The evaluators available
+// are listed in evaluators, by the names NewEvaluator takes.
+type Evaluator interface {
+	// Run runs the statements added by the last call to Eval, and returns what
+	// Session.Run returns. It returns a fallbackError if it cannot run them.
+	Run() ([]byte, error, bytes.Buffer)
+	// Interrupt stops a concurrent call to Run, which then returns ErrInterrupt.
+	// It reports whether Run was running.
+	Interrupt() bool
+	// Close stops what Run started. The evaluator must not be used afterwards.
+	Close() error
+}
+
+// evaluators are the evaluators of sessions by name.
+var evaluators = map[string]func(s *Session) Evaluator{
+	// run builds and runs the whole session again for every cell.
+	"run": func(s *Session) Evaluator { return &runner{s} },
+	// worker runs every cell once, in a worker process keeping the variables.
+	"worker": func(s *Session) Evaluator { return newWorker(s) },
+	// interp interprets every cell in the kernel, keeping the variables.
+	"interp": func(s *Session) Evaluator { return newInterpreter(s) },
+}
+
+// NewEvaluator returns the evaluator of s named name: "run", "worker" or "interp".
+func (s *Session) NewEvaluator(name string) (Evaluator, error) {
+	newEvaluator, ok := evaluators[name]
+	if !ok {
+		var names []string
+		for name := range evaluators {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown evaluator %q, use one of %s", name, strings.Join(names, ", "))
+	}
+	return newEvaluator(s), nil
+}
+
+// fallbackError tells why the cells of a session cannot be run by its evaluator.
+// The session runs the whole session again from then on.
+type fallbackError string
+
+func (e fallbackError) Error() string {
+	return string(e)
+}
+
+// runner is the evaluator building the whole session as a program, and running
+// it, for every cell.
+type runner struct {
+	s *Session
+}
+
+func (r *runner) Run() ([]byte, error, bytes.Buffer) {
+	s := r.s
+
+	// the expressions evaluated along with the cell are printed last, and kept out
+	// of the session source
+	n := len(s.mainBody.List)
+	s.appendStatements(s.exprStmts()...)
+	defer func() {
+		s.mainBody.List = s.mainBody.List[:n]
+	}()
+
+	fset, program, err := s.program()
+	if err != nil {
+		return []byte{}, err, bytes.Buffer{}
+	}
+	s.markExprs(program)
+
+	f, err := os.Create(s.FilePath)
+	if err != nil {
+		return []byte{}, err, bytes.Buffer{}
+	}
+	defer f.Close()
+
+	var src bytes.Buffer
+	err = printer.Fprint(&src, fset, program)
+	if err != nil {
+		return []byte{}, err, bytes.Buffer{}
+	}
+	if _, err := f.Write(src.Bytes()); err != nil {
+		return []byte{}, err, bytes.Buffer{}
+	}
+	s.srcMap, s.srcPath = s.newSourceMap(src.Bytes(), "main", s.mainBody.List), s.FilePath
+
+	s.resetValues()
+	srv, err := ipc.Listen(filepath.Join(filepath.Dir(s.FilePath), "ipc.sock"), s.handleIPC)
+	if err != nil {
+		return []byte{}, err, bytes.Buffer{}
+	}
+
+	var stdout, stderr bytes.Buffer
+	outw, errw := s.outputWriters(&stdout, &stderr)
+
+	// the report of a panic is held back, to be returned as a *PanicError
+	pw := &panicWriter{w: errw}
+
+	env := append(os.Environ(), ipc.EnvVar+"="+srv.Addr())
+	err = s.goRun(append(s.ExtraFilePaths, s.FilePath), env, outw, pw)
+
+	// wait for the values sent by the program
+	srv.Close()
+
+	var panicErr *PanicError
+	if _, ok := err.(*exec.ExitError); ok {
+		panicErr = s.panicError(pw.heldOutput())
+	}
+	if panicErr != nil {
+		err = panicErr
+	} else {
+		pw.flush()
+	}
+
+	return append(stdout.Bytes(), s.values.Bytes()...), err, stderr
+}
+
+func (r *runner) Interrupt() bool {
+	return r.s.interruptCmd()
+}
+
+func (r *runner) Close() error {
+	r.s.interruptCmd()
+	return nil
+}
@@ -0,0 +1,264 @@
+package replpkg
+
+import (
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+)
+
+// errExprsRun is returned by EvalExprs for the expressions the "run" evaluator has
+// not evaluated along with the last cell, as it would run every cell again.
+var errExprsRun = errors.New(`the "run" evaluator only evaluates expressions along with a cell`)
+
+// errExprsNotEvaluated is returned by EvalExprs for the expressions whose values
+// the program did not print.
+var errExprsNotEvaluated = errors.New("expressions were not evaluated")
+
+// exprsRun holds the expressions of Exprs evaluated along with a cell by the "run"
+// evaluator, and their values or why they failed.
+type exprsRun struct {
+	eval    int // the call to Eval
+	exprs   []string
+	values  []Value
+	printed []bool
+	errs    []error
+	stmts   []int // the expressions printed by the program, in order
+	current int   // the expression whose value is printed, or -1
+}
+
+// EvalExprs evaluates expressions against the session and returns their values,
+// or why they could not be evaluated. The session is left unchanged: the
+// expressions are run once by the evaluator, with their output and input
+// discarded. The "run" evaluator, which would run the whole session again, returns
+// the values of Exprs evaluated along with the last cell instead, where a panic
+// evaluating one of them only fails its own. Expressions which do not compile fail
+// with a *CompileError.
+func (s *Session) EvalExprs(exprs []string) (values []Value, errs []error) {
+	values = make([]Value, len(exprs))
+	errs = make([]error, len(exprs))
+
+	if _, ok := s.Evaluator.(*runner); ok {
+		s.exprValues(exprs, values, errs)
+		return
+	}
+
+	s.storeMainBody()
+	defer s.restoreMainBody()
+
+	// the evaluator runs the statements of the last call to Eval, which the
+	// statements of the expressions pass for
+	s.evals++
+	var stmts []ast.Stmt
+	var evaluated []int
+	defer func() {
+		for _, stmt := range stmts {
+			delete(s.origins, stmt)
+		}
+	}()
+
+	for i, in := range exprs {
+		s.restoreMainBody()
+		stmt, err := s.exprStmt(in)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		s.origins[stmt] = s.newOrigin(in, s.Fset, stmt.X.(*ast.CallExpr).Args[0], 0)
+		stmts = append(stmts, stmt)
+		evaluated = append(evaluated, i)
+	}
+
+	if len(stmts) == 0 {
+		return
+	}
+
+	s.restoreMainBody()
+	s.appendStatements(stmts...)
+
+	stdout, stderr, input := s.Stdout, s.Stderr, s.Input
+	s.Stdout, s.Stderr, s.Input = ioutil.Discard, nil, nil
+	_, err, _ := s.Evaluator.Run()
+	s.Stdout, s.Stderr, s.Input = stdout, stderr, input
+
+	// the values of the expressions are the last ones printed
+	printed := s.Values()
+	if err == nil && len(printed) < len(stmts) {
+		err = errExprsNotEvaluated
+	}
+	if err != nil {
+		for _, i := range evaluated {
+			errs[i] = err
+		}
+		return
+	}
+
+	printed = printed[len(printed)-len(stmts):]
+	for j, i := range evaluated {
+		values[i] = printed[j]
+	}
+
+	return
+}
+
+// exprValues sets the values of exprs, or why they could not be evaluated, as
+// evaluated along with the last cell by the "run" evaluator.
+func (s *Session) exprValues(exprs []string, values []Value, errs []error) {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
+	e := s.exprs
+	if e == nil || e.eval != s.evals || strings.Join(e.exprs, "\x00") != strings.Join(exprs, "\x00") {
+		for i := range errs {
+			errs[i] = errExprsRun
+		}
+		return
+	}
+
+	for i := range exprs {
+		switch {
+		case e.errs[i] != nil:
+			errs[i] = e.errs[i]
+		case !e.printed[i]:
+			errs[i] = errExprsNotEvaluated
+		default:
+			values[i] = e.values[i]
+			values[i].Text = strings.TrimSuffix(values[i].Text, "\n")
+		}
+	}
+}
+
+// exprStmts returns the statements printing the expressions of Exprs after those
+// of the session, for the "run" evaluator. Expressions which do not compile there
+// are left out, their errors kept for EvalExprs.
+func (s *Session) exprStmts() []ast.Stmt {
+	e := &exprsRun{
+		eval:    s.evals,
+		exprs:   s.Exprs,
+		values:  make([]Value, len(s.Exprs)),
+		printed: make([]bool, len(s.Exprs)),
+		errs:    make([]error, len(s.Exprs)),
+		current: -1,
+	}
+
+	var stmts []ast.Stmt
+	for i, in := range s.Exprs {
+		stmt, err := s.exprStmt(in)
+		if err != nil {
+			e.errs[i] = err
+			continue
+		}
+		stmts = append(stmts, stmt)
+		e.stmts = append(e.stmts, i)
+	}
+
+	s.valuesMu.Lock()
+	s.exprs = e
+	s.valuesMu.Unlock()
+	return stmts
+}
+
+// markExprs makes the statements printing the expressions of Exprs, which the body
+// of main in the program f ends with, send the index of their expression with
+// ipc.BeginExpr first, so that their values are kept apart from those of the cells,
+// and report a panic with ipc.EndExpr, so that it only fails their expression.
+func (s *Session) markExprs(f *ast.File) {
+	indexes := s.exprs.stmts
+	obj := f.Scope.Lookup("main")
+	if len(indexes) == 0 || obj == nil {
+		return
+	}
+
+	body := obj.Decl.(*ast.FuncDecl).Body.List
+	stmts := body[len(body)-len(indexes):]
+	for j, i := range indexes {
+		// func() { defer __gore_ipc.EndExpr(); __gore_ipc.BeginExpr(i); __gore_p(expr) }()
+		end := &ast.DeferStmt{
+			Call: &ast.CallExpr{
+				Fun: &ast.SelectorExpr{X: ast.NewIdent(ipcPkgName), Sel: ast.NewIdent("EndExpr")},
+			},
+		}
+		begin := &ast.ExprStmt{
+			X: &ast.CallExpr{
+				Fun:  &ast.SelectorExpr{X: ast.NewIdent(ipcPkgName), Sel: ast.NewIdent("BeginExpr")},
+				Args: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(i)}},
+			},
+		}
+		fn := &ast.FuncLit{
+			Type: &ast.FuncType{Params: &ast.FieldList{}},
+			Body: &ast.BlockStmt{List: []ast.Stmt{end, begin, stmts[j]}},
+		}
+		stmts[j] = &ast.ExprStmt{X: &ast.CallExpr{Fun: fn}}
+	}
+}
+
+// exprStmt returns the statement printing the expression in, which is checked after
+// the statements of the session. A *CompileError is returned if it does not
+// compile there.
+func (s *Session) exprStmt(in string) (*ast.ExprStmt, error) {
+	expr, err := parser.ParseExprFrom(s.Fset, "expr.go", in, 0)
+	if err != nil {
+		return nil, exprError(in, err)
+	}
+
+	stmt := &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun:  ast.NewIdent(printerName),
+			Args: []ast.Expr{expr},
+		},
+	}
+
+	n := len(s.mainBody.List)
+	s.appendStatements(stmt)
+	err = s.typeCheck()
+	s.mainBody.List = s.mainBody.List[:n]
+	if err != nil {
+		return nil, exprError(in, err)
+	}
+	return stmt, nil
+}
+
+// exprError returns err, the error of the expression in, as a *CompileError located
+// in the expression.
+func exprError(in string, err error) *CompileError {
+	cellErr := CellError{Msg: err.Error()}
+
+	var pos token.Position
+	switch err := err.(type) {
+	case scanner.ErrorList:
+		if len(err) > 0 {
+			pos, cellErr.Msg = err[0].Pos, "syntax error: "+err[0].Msg
+		}
+	case types.Error:
+		pos, cellErr.Msg = err.Fset.Position(err.Pos), err.Msg
+	}
+	if pos.IsValid() && pos.Filename == "expr.go" {
+		cellErr.Line, cellErr.Col, cellErr.Source = offsetLineCol(in, pos.Offset)
+	}
+
+	return &CompileError{Errors: []CellError{cellErr}}
+}
+
+// typeCheck type checks the session source and returns the first error.
+func (s *Session) typeCheck() error {
+	files := append([]*ast.File{}, s.ExtraFiles...)
+	files = append(files, s.File)
+
+	var firstErr error
+	conf := *s.Types
+	conf.Error = func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	conf.Check(checkPkgPath, s.Fset, files, nil)
+
+	return firstErr
+}
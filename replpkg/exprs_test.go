@@ -0,0 +1,79 @@
+package replpkg
+
+import (
+	"testing"
+)
+
+func TestSession_EvalExprs(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+
+	s.Evaluator.Close()
+	s.Evaluator, err = s.NewEvaluator("interp")
+	noError(t, err)
+
+	_, err, _ = s.Eval(`x := 21`)
+	noError(t, err)
+
+	source, err := s.source(false)
+	noError(t, err)
+
+	values, errs := s.EvalExprs([]string{`x * 2`, `y`, `"a" + "b"`, `x +`})
+
+	if errs[0] != nil || values[0].Text != "42" {
+		t.Errorf("x * 2 should be 42: got %q, %v", values[0].Text, errs[0])
+	}
+	if errs[1] == nil {
+		t.Errorf("undefined y should be an error")
+	}
+	if errs[2] != nil || values[2].Text != `"ab"` {
+		t.Errorf(`"a" + "b" should be "ab": got %q, %v`, values[2].Text, errs[2])
+	}
+	if errs[3] == nil {
+		t.Errorf("x + should be a syntax error")
+	}
+
+	after, err := s.source(false)
+	noError(t, err)
+	if after != source {
+		t.Errorf("session should be unchanged:\n%s\n---\n%s", source, after)
+	}
+}
+
+func TestSession_EvalExprs_run(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+
+	s.Evaluator.Close()
+	s.Evaluator, err = s.NewEvaluator("run")
+	noError(t, err)
+
+	_, err, _ = s.Eval(`x := 21; println(x)`)
+	noError(t, err)
+
+	_, errs := s.EvalExprs([]string{`x * 2`})
+	if errs[0] != errExprsRun {
+		t.Errorf("the session should not be run again: %v", errs[0])
+	}
+
+	exprs := []string{`x * 2`, `y`, `[]int{}[x]`}
+	s.Exprs = exprs
+	out, err, _ := s.Eval(`x++`)
+	noError(t, err)
+	if out != "" || len(s.Values()) > 0 {
+		t.Errorf("the values of the expressions should not be those of the cell: %q, %+v", out, s.Values())
+	}
+
+	values, errs := s.EvalExprs(exprs)
+	if errs[0] != nil || values[0].Text != "44" {
+		t.Errorf("x * 2 should be 44 after the cell: got %q, %v", values[0].Text, errs[0])
+	}
+	if _, ok := errs[1].(*CompileError); !ok {
+		t.Errorf("undefined y should be a compile error: %v", errs[1])
+	}
+	if _, ok := errs[2].(*PanicError); !ok {
+		t.Errorf("the index out of range should only fail its expression: %v", errs[2])
+	}
+}
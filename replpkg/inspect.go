@@ -0,0 +1,269 @@
+package replpkg
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// Inspect returns the documentation of the identifier at byte offset pos of in,
+// formatted both as plain text and as markdown. It consists of the signature and
+// the doc comment of the declaration; if detailLevel > 0, the source of the
+// declaration is included as well.
+func (s *Session) Inspect(in string, pos int, detailLevel int) (text, markdown string, err error) {
+	target := exprAt(in, pos)
+	if target == "" {
+		return "", "", fmt.Errorf("no identifier at cursor")
+	}
+
+	expr, err := s.checkExpr(target)
+	if err != nil {
+		return "", "", err
+	}
+
+	var obj types.Object
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		obj = s.TypeInfo.ObjectOf(expr)
+	case *ast.SelectorExpr:
+		obj = s.TypeInfo.ObjectOf(expr.Sel)
+	}
+	if obj == nil {
+		obj = s.docObject(expr)
+	}
+	if obj == nil {
+		return "", "", fmt.Errorf("cannot determine the document location")
+	}
+
+	debugf("inspect :: %q obj=%#v", target, obj)
+
+	signature := types.ObjectString(obj, func(pkg *types.Package) string {
+		if pkg.Path() == checkPkgPath {
+			return ""
+		}
+		return pkg.Name()
+	})
+	doc, source := s.declOf(obj)
+
+	text = signature
+	markdown = "```go\n" + signature + "\n```"
+	if doc != "" {
+		text += "\n\n" + doc
+		markdown += "\n\n" + doc
+	}
+	if detailLevel > 0 && source != "" {
+		text += "\n\n" + source
+		markdown += "\n\n```go\n" + source + "\n```"
+	}
+
+	return text, markdown, nil
+}
+
+// exprAt returns the (possibly qualified) identifier at byte offset pos of in.
+// If there is none, the function called by the innermost open call around pos
+// is returned, so that e.g. "fmt.Println(|" yields "fmt.Println".
+func exprAt(in string, pos int) string {
+	start := pos
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(in[0:start])
+		if !isIdentRune(r) && r != '.' {
+			break
+		}
+		start -= size
+	}
+
+	end := pos
+	for end < len(in) {
+		r, size := utf8.DecodeRuneInString(in[end:])
+		if !isIdentRune(r) {
+			break
+		}
+		end += size
+	}
+
+	if expr := strings.Trim(in[start:end], "."); expr != "" {
+		return expr
+	}
+
+	depth := 0
+	for i := pos - 1; i >= 0; i-- {
+		switch in[i] {
+		case ')':
+			depth++
+		case '(':
+			if depth == 0 {
+				return exprAt(in[0:i], i)
+			}
+			depth--
+		}
+	}
+
+	return ""
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// declOf looks up the declaration of obj in the session or in the source of its
+// package, and returns its doc comment and source.
+func (s *Session) declOf(obj types.Object) (doc, source string) {
+	_, isPkgName := obj.(*types.PkgName)
+	if !isPkgName && obj.Pkg() != nil && obj.Pkg().Path() == checkPkgPath {
+		nodepath, _ := astutil.PathEnclosingInterval(s.File, obj.Pos(), obj.Pos())
+		for _, node := range nodepath {
+			switch node.(type) {
+			case ast.Stmt, ast.Decl:
+				return declDoc(node), showNode(s.Fset, node)
+			}
+		}
+		return "", ""
+	}
+
+	pkgPath := "builtin"
+	if pkgName, ok := obj.(*types.PkgName); ok {
+		pkgPath = pkgName.Imported().Path()
+	} else if obj.Pkg() != nil {
+		pkgPath = obj.Pkg().Path()
+	}
+
+	pkg, err := build.Import(pkgPath, ".", 0)
+	if err != nil {
+		debugf("inspect :: %s", err)
+		return "", ""
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range pkg.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			debugf("inspect :: %s", err)
+			continue
+		}
+
+		if isPkgName {
+			if f.Doc != nil {
+				return strings.TrimSpace(f.Doc.Text()), ""
+			}
+			continue
+		}
+
+		if node := findDecl(f, obj); node != nil {
+			var buf bytes.Buffer
+			printer.Fprint(&buf, fset, node)
+			return declDoc(node), buf.String()
+		}
+	}
+
+	return "", ""
+}
+
+// findDecl finds the top level declaration of obj in f. Of grouped declarations
+// only the matching spec is returned, wrapped in a declaration of its own.
+func findDecl(f *ast.File, obj types.Object) ast.Node {
+	var recv string
+	if fn, ok := obj.(*types.Func); ok {
+		if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+			t := sig.Recv().Type()
+			if pt, ok := t.(*types.Pointer); ok {
+				t = pt.Elem()
+			}
+			named, ok := t.(*types.Named)
+			if !ok {
+				// interface methods are not declared at top level
+				return nil
+			}
+			recv = named.Obj().Name()
+		}
+	}
+
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Name.Name == obj.Name() && recvName(decl) == recv {
+				return decl
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				var names []*ast.Ident
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = []*ast.Ident{spec.Name}
+				case *ast.ValueSpec:
+					names = spec.Names
+				}
+				for _, name := range names {
+					if name.Name != obj.Name() {
+						continue
+					}
+					if len(decl.Specs) == 1 {
+						return decl
+					}
+					return &ast.GenDecl{Tok: decl.Tok, Specs: []ast.Spec{spec}}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// recvName returns the name of the receiver type of a method declaration.
+func recvName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := decl.Recv.List[0].Type
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+func specDoc(spec ast.Spec) *ast.CommentGroup {
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		return spec.Doc
+	case *ast.ValueSpec:
+		return spec.Doc
+	}
+	return nil
+}
+
+// declDoc returns the text of the doc comment attached to node, if any.
+func declDoc(node ast.Node) string {
+	var doc *ast.CommentGroup
+	switch node := node.(type) {
+	case *ast.FuncDecl:
+		doc = node.Doc
+	case *ast.GenDecl:
+		doc = node.Doc
+		if doc == nil && len(node.Specs) == 1 {
+			doc = specDoc(node.Specs[0])
+		}
+	}
+
+	return strings.TrimSpace(doc.Text())
+}
@@ -0,0 +1,48 @@
+package replpkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExprAt(t *testing.T) {
+	tests := []struct {
+		in   string
+		pos  int
+		expr string
+	}{
+		{"fmt.Println", 5, "fmt.Println"},
+		{"x := json.Marshal(v)", 10, "json.Marshal"},
+		{"fmt.Println(", 12, "fmt.Println"},
+		{"fmt.Println(a, ", 15, "fmt.Println"},
+		{"f(g(1), ", 8, "f"},
+		{"1 + ", 4, ""},
+	}
+
+	for _, test := range tests {
+		if expr := exprAt(test.in, test.pos); expr != test.expr {
+			t.Errorf("exprAt(%q, %d) should be %q: got %q", test.in, test.pos, test.expr, expr)
+		}
+	}
+}
+
+func TestSession_Inspect(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	err = actionImport(s, "strings")
+	noError(t, err)
+
+	text, markdown, err := s.Inspect("strings.ToUpper(", 16, 1)
+	noError(t, err)
+
+	if !strings.HasPrefix(text, "func strings.ToUpper(s string) string") {
+		t.Errorf("text should start with the signature: %q", text)
+	}
+	if !strings.Contains(text, "ToUpper returns") {
+		t.Errorf("text should contain the doc comment: %q", text)
+	}
+	if !strings.Contains(markdown, "func ToUpper(s string) string {") {
+		t.Errorf("markdown should contain the source: %q", markdown)
+	}
+}
@@ -0,0 +1,379 @@
+package replpkg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+
+	"github.com/fabian-z/gopherlab/display"
+	"github.com/fabian-z/gopherlab/interp"
+	"github.com/fabian-z/gopherlab/ipc"
+)
+
+// interpreter is the evaluator interpreting every cell in the kernel process,
+// keeping the variables; see package github.com/fabian-z/gopherlab/interp. Values
+// are printed with fmt, whichever printer is selected.
+type interpreter struct {
+	s  *Session
+	in *interp.Interpreter
+
+	null           *os.File // the null device
+	stdin          *os.File // the null device, or the pipe the input of the cell running is read from
+	stdout, stderr *os.File // the pipes the output of the cell running is read from
+
+	ipcDir string      // the directory of the socket of srv
+	srv    *ipc.Server // serves the ipc channel of the cells, see serveIPC
+}
+
+func newInterpreter(s *Session) *interpreter {
+	e := &interpreter{s: s, in: interp.New()}
+
+	e.in.Define("main", printerName, e.printValues)
+	e.in.Define("os", "Stdin", &e.stdin)
+	e.in.Define("os", "Stdout", &e.stdout)
+	e.in.Define("os", "Stderr", &e.stderr)
+	e.in.Define("fmt", "Print", func(a ...interface{}) (int, error) {
+		return fmt.Fprint(e.stdout, a...)
+	})
+	e.in.Define("fmt", "Printf", func(format string, a ...interface{}) (int, error) {
+		return fmt.Fprintf(e.stdout, format, a...)
+	})
+	e.in.Define("fmt", "Println", func(a ...interface{}) (int, error) {
+		return fmt.Fprintln(e.stdout, a...)
+	})
+	e.in.Define("fmt", "Scan", func(a ...interface{}) (int, error) {
+		return fmt.Fscan(e.stdin, a...)
+	})
+	e.in.Define("fmt", "Scanf", func(format string, a ...interface{}) (int, error) {
+		return fmt.Fscanf(e.stdin, format, a...)
+	})
+	e.in.Define("fmt", "Scanln", func(a ...interface{}) (int, error) {
+		return fmt.Fscanln(e.stdin, a...)
+	})
+
+	return e
+}
+
+// Run interprets the statements added by the last call to Eval. A fallbackError is
+// returned if they use what the interpreter does not support.
+func (e *interpreter) Run() ([]byte, error, bytes.Buffer) {
+	s := e.s
+	var stdout, stderr bytes.Buffer
+	outw, errw := s.outputWriters(&stdout, &stderr)
+
+	fset, f, info, err := e.check()
+	if err != nil {
+		return nil, err, stderr
+	}
+
+	mainDecl := f.Scope.Lookup("main").Decl.(*ast.FuncDecl)
+	if len(mainDecl.Body.List) != len(s.mainBody.List) {
+		return nil, fmt.Errorf("session source does not match its statements"), stderr
+	}
+	var stmts []ast.Stmt
+	for i, stmt := range mainDecl.Body.List {
+		if o := s.origins[s.mainBody.List[i]]; o != nil && o.eval == s.evals {
+			stmts = append(stmts, stmt)
+		}
+	}
+
+	if e.null == nil {
+		if e.null, err = os.Open(os.DevNull); err != nil {
+			return nil, err, stderr
+		}
+	}
+	e.stdin = e.null
+	if s.Input != nil && stdinAvailable {
+		// the input is read from the notebook while the cell runs, see Session.Input
+		stdin, err := newStdinPipe()
+		if err != nil {
+			return nil, err, stderr
+		}
+		defer func() {
+			e.stdin = e.null
+			stdin.Close()
+		}()
+		stop := make(chan struct{})
+		defer close(stop)
+		go func(input func(string, bool) (string, error)) {
+			if err := stdin.serve(os.Getpid(), stdin.r.Fd(), input, stop); err != nil {
+				stdin.w.Close()
+			}
+		}(s.Input)
+		e.stdin = stdin.r
+	}
+	outr, outp, err := os.Pipe()
+	if err != nil {
+		return nil, err, stderr
+	}
+	errr, errp, err := os.Pipe()
+	if err != nil {
+		outr.Close()
+		outp.Close()
+		return nil, err, stderr
+	}
+	var copying sync.WaitGroup
+	copying.Add(2)
+	go func() {
+		defer copying.Done()
+		io.Copy(outw, outr)
+	}()
+	go func() {
+		defer copying.Done()
+		io.Copy(errw, errr)
+	}()
+
+	e.stdout, e.stderr = outp, errp
+	e.in.Stderr = s.Stderr
+	logOutput := e.in.Log.Writer()
+	e.in.Log.SetOutput(errp)
+	s.resetValues()
+	display.MaxDepth, display.MaxLength = s.printer.maxDepth, s.printer.maxLength
+
+	if err = e.serveIPC(); err == nil {
+		for _, sh := range s.shadowed {
+			e.in.Rename(sh.name, sh.renamed)
+		}
+		err = e.in.Run(fset, info, mainDecl, stmts)
+		syncInterpIPC()
+	}
+
+	e.in.Log.SetOutput(logOutput)
+	outp.Close()
+	errp.Close()
+	copying.Wait()
+	outr.Close()
+	errr.Close()
+
+	switch runErr := err.(type) {
+	case *interp.UnsupportedError:
+		return nil, fallbackError(fmt.Sprintf("the interpreter cannot run the cell: %s", runErr.Msg)), stderr
+	case *interp.Panic:
+		err = e.panicError(runErr)
+	}
+	if err == interp.ErrInterrupted {
+		err = ErrInterrupt
+	}
+	if _, ok := err.(*PanicError); ok || err == ErrInterrupt {
+		// the session renames the variables back, see restoreMainBody
+		for i := len(s.shadowed) - 1; i >= 0; i-- {
+			e.in.Rename(s.shadowed[i].renamed, s.shadowed[i].name)
+		}
+	}
+
+	return append(stdout.Bytes(), s.values.Bytes()...), err, stderr
+}
+
+// check parses the session source, and type checks it recording what the
+// interpreter needs. Errors are returned as a *CompileError, like those of the
+// compiler.
+func (e *interpreter) check() (*token.FileSet, *ast.File, *types.Info, error) {
+	s := e.s
+	source, err := s.source(false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	src := splitMain(source)
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, s.FilePath, src, parser.Mode(0))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	s.srcMap, s.srcPath = s.newSourceMap([]byte(src), "main", s.mainBody.List), s.FilePath
+
+	files := []*ast.File{f}
+	for _, path := range s.ExtraFilePaths {
+		ext, err := parser.ParseFile(fset, path, nil, parser.Mode(0))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		files = append(files, ext)
+	}
+
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Scopes:     make(map[ast.Node]*types.Scope),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Instances:  make(map[*ast.Ident]types.Instance),
+	}
+	var errs []string
+	config := types.Config{
+		Importer: s.Types.Importer,
+		Error: func(err error) {
+			errs = append(errs, err.Error())
+		},
+	}
+	config.Check("main", fset, files, info)
+	if len(errs) > 0 {
+		return nil, nil, nil, compileError(strings.Join(errs, "\n"), s.FilePath, s.srcMap)
+	}
+
+	return fset, f, info, nil
+}
+
+// panicError returns the *PanicError for the panic p of the statements
+// interpreted, locating the calls in the cells.
+func (e *interpreter) panicError(p *interp.Panic) *PanicError {
+	s := e.s
+	msg := ipc.NewPanicMessage(p.Value)
+	err := &PanicError{Value: msg.Value, Type: msg.Type}
+	for _, f := range p.Frames {
+		frame := Frame{Func: f.Func, File: f.Pos.Filename, Line: f.Pos.Line}
+		if filepath.Base(frame.File) == filepath.Base(s.FilePath) {
+			frame.Pos, _ = s.srcMap.locate(f.Pos.Line, f.Pos.Column)
+		}
+		err.Frames = append(err.Frames, frame)
+	}
+	return err
+}
+
+// printValues is the printer function of the session, which adds the values xx to
+// those of the cell as the printer function of the programs run does.
+func (e *interpreter) printValues(xx ...interface{}) {
+	s := e.s
+	for _, x := range xx {
+		bundle, metadata := valueBundle(x)
+
+		s.valuesMu.Lock()
+		s.valueStarts = append(s.valueStarts, s.values.Len())
+		if bundle != nil {
+			s.valueData = append(s.valueData, &ipc.DisplayMessage{Data: bundle, Metadata: metadata})
+		} else {
+			s.valueData = append(s.valueData, nil)
+		}
+		if _, hasText := bundle[display.MIMEPlain]; !hasText {
+			fmt.Fprintf(&s.values, "%#v\n", x)
+		}
+		s.valuesMu.Unlock()
+	}
+}
+
+// valueBundle returns the rich representations of x, or nil if it has none or they
+// cannot be computed.
+func valueBundle(x interface{}) (bundle display.Bundle, metadata map[string]interface{}) {
+	defer func() {
+		if recover() != nil {
+			bundle, metadata = nil, nil
+		}
+	}()
+
+	bundle, metadata, err := display.ValueBundle(x)
+	if err != nil {
+		return nil, nil
+	}
+	return bundle, metadata
+}
+
+func (e *interpreter) Interrupt() bool {
+	return e.in.Interrupt()
+}
+
+// Close stops the cell running, and serving the ipc channel.
+func (e *interpreter) Close() error {
+	e.in.Interrupt()
+	if e.srv != nil {
+		ipc.SetAddr("")
+		if err := e.srv.Close(); err != nil {
+			errorf("ipc: %s", err)
+		}
+		if err := os.RemoveAll(e.ipcDir); err != nil {
+			errorf("ipc: %s", err)
+		}
+		e.srv = nil
+	}
+	if e.null != nil {
+		null := e.null
+		e.stdin, e.null = nil, nil
+		return null.Close()
+	}
+	return nil
+}
+
+// interpIPC passes the messages on the ipc channel of the interpreted sessions to
+// the session whose cell is running. Package ipc dials the channel as in the
+// programs run, on a connection shared by the process.
+var interpIPC struct {
+	mu      sync.Mutex    // guards session and synced
+	session *Session      // the session whose cell is running
+	synced  chan struct{} // closed once the messages of the cell have been handled
+}
+
+// interpSync is the type of the message syncInterpIPC sends.
+const interpSync = "interp_sync"
+
+// serveIPC passes the messages on the ipc channel of the interpreted cells to the
+// session, starting to serve it if needed. Package ipc is directed to its socket,
+// which is kept out of the environment of the processes the kernel starts.
+func (e *interpreter) serveIPC() error {
+	if e.srv == nil {
+		dir, err := ioutil.TempDir("", "")
+		if err != nil {
+			return err
+		}
+		srv, err := ipc.Listen(filepath.Join(dir, "ipc.sock"), handleInterpIPC)
+		if err != nil {
+			os.RemoveAll(dir)
+			return err
+		}
+		e.ipcDir, e.srv = dir, srv
+	}
+	ipc.SetAddr(e.srv.Addr())
+
+	interpIPC.mu.Lock()
+	defer interpIPC.mu.Unlock()
+
+	interpIPC.session = e.s
+	interpIPC.synced = make(chan struct{})
+	return nil
+}
+
+// syncInterpIPC waits until the messages sent on the ipc channel so far have been
+// handled, and stops passing them on.
+func syncInterpIPC() {
+	interpIPC.mu.Lock()
+	synced := interpIPC.synced
+	interpIPC.mu.Unlock()
+
+	if conn, err := ipc.Default(); err == nil && conn.Send(interpSync, nil) == nil {
+		select {
+		case <-synced:
+		case <-time.After(time.Second):
+			errorf("ipc: messages of the cell have not been handled")
+		}
+	}
+
+	interpIPC.mu.Lock()
+	defer interpIPC.mu.Unlock()
+
+	interpIPC.session = nil
+	interpIPC.synced = nil
+}
+
+func handleInterpIPC(conn *ipc.Conn, msg ipc.Message) {
+	interpIPC.mu.Lock()
+	s := interpIPC.session
+	if msg.Type == interpSync && interpIPC.synced != nil {
+		close(interpIPC.synced)
+		interpIPC.synced = nil
+	}
+	interpIPC.mu.Unlock()
+
+	if msg.Type != interpSync && s != nil {
+		s.handleIPC(conn, msg)
+	}
+}
@@ -0,0 +1,98 @@
+package replpkg
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/fabian-z/gopherlab/ipc"
+)
+
+func TestRun_interp(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+	s.Evaluator, err = s.NewEvaluator("interp")
+	noError(t, err)
+
+	for _, code := range []string{":import fmt", "xs := []int{1, 2}"} {
+		_, err, _ = s.Eval(code)
+		noError(t, err)
+	}
+	out, err, _ := s.Eval("for _, x := range xs { fmt.Println(x) }")
+	noError(t, err)
+	if out != "1\n2\n" {
+		t.Errorf("got %q", out)
+	}
+	out, err, _ = s.Eval("xs[0] + xs[1]")
+	noError(t, err)
+	if out != "3\n" {
+		t.Errorf("the earlier cells should not have run again: got %q", out)
+	}
+
+	_, err, _ = s.Eval("xs[2]")
+	if err, ok := err.(*PanicError); !ok || err.Value != "runtime error: index out of range [2] with length 2" || len(err.Frames) == 0 || err.Frames[0].Pos.Line != 1 {
+		t.Errorf("should panic in the cell: %#v", err)
+	}
+	_, err, _ = s.Eval("xs + 1")
+	if _, ok := err.(*CompileError); !ok {
+		t.Errorf("should not compile: %v", err)
+	}
+	if _, ok := s.Evaluator.(*interpreter); !ok {
+		t.Errorf("got evaluator %T", s.Evaluator)
+	}
+}
+
+func TestRun_interpFallback(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+	s.Evaluator, err = s.NewEvaluator("interp")
+	noError(t, err)
+
+	for _, code := range []string{"x := 1", ":import net/http"} {
+		_, err, _ = s.Eval(code)
+		noError(t, err)
+	}
+	out, err, _ := s.Eval("http.DefaultClient != nil && x == 1")
+	noError(t, err)
+	if out != "true\n" {
+		t.Errorf("got %q", out)
+	}
+	if _, ok := s.Evaluator.(*runner); !ok {
+		t.Errorf("should have fallen back to running the whole session: got %T", s.Evaluator)
+	}
+}
+
+func TestRun_interpIPC(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+	s.Evaluator, err = s.NewEvaluator("interp")
+	noError(t, err)
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	_, err, _ = s.Eval(":import log")
+	noError(t, err)
+	_, err, stderr := s.Eval(`log.SetFlags(0); log.Print("hi")`)
+	noError(t, err)
+	if stderr.String() != "hi\n" || logs.Len() > 0 {
+		t.Errorf("the cell should log to its own output: got %q, and %q in the log of the process", stderr.String(), logs.String())
+	}
+	if addr := os.Getenv(ipc.EnvVar); addr != "" {
+		t.Errorf("the ipc channel should not be in the environment: %s", addr)
+	}
+	if log.Writer() != &logs {
+		t.Errorf("the output of the log of the process should not have changed")
+	}
+
+	dir := s.Evaluator.(*interpreter).ipcDir
+	noError(t, s.Evaluator.Close())
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("the directory of the ipc channel should have been removed: %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package replpkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/fabian-z/gopherlab/ipc"
+)
+
+const (
+	ipcPkgPath     = "github.com/fabian-z/gopherlab/ipc"
+	ipcPkgName     = "__gore_ipc"
+	displayPkgPath = "github.com/fabian-z/gopherlab/display"
+	displayPkgName = "__gore_display"
+)
+
+// fprintFuncs maps the fmt-like functions printing to os.Stdout to their io.Writer variants.
+var fprintFuncs = map[string]string{
+	"Print":   "Fprint",
+	"Printf":  "Fprintf",
+	"Println": "Fprintln",
+}
+
+// handleIPC responds to a message sent by the running program.
+func (s *Session) handleIPC(conn *ipc.Conn, msg ipc.Message) {
+	debugf("ipc :: %s %s", msg.Type, msg.Content)
+
+	switch msg.Type {
+	case "value":
+		var value string
+		if err := msg.Decode(&value); err != nil {
+			errorf("ipc: %s", err)
+			return
+		}
+		s.valuesMu.Lock()
+		if e := s.exprs; e != nil && e.current >= 0 {
+			e.values[e.current].Text += value
+		} else {
+			s.values.WriteString(value)
+		}
+		s.valuesMu.Unlock()
+	case "value_start":
+		s.valuesMu.Lock()
+		if e := s.exprs; e != nil && e.current >= 0 {
+			e.printed[e.current] = true
+		} else {
+			s.valueStarts = append(s.valueStarts, s.values.Len())
+			s.valueData = append(s.valueData, nil)
+		}
+		s.valuesMu.Unlock()
+	case "value_data":
+		var data ipc.DisplayMessage
+		if err := msg.Decode(&data); err != nil {
+			errorf("ipc: %s", err)
+			return
+		}
+		s.valuesMu.Lock()
+		if e := s.exprs; e != nil && e.current >= 0 {
+			e.values[e.current].Data, e.values[e.current].Metadata = data.Data, data.Metadata
+		} else if n := len(s.valueData); n > 0 {
+			s.valueData[n-1] = &data
+		}
+		s.valuesMu.Unlock()
+	case "expr_start":
+		var i int
+		if err := msg.Decode(&i); err != nil {
+			errorf("ipc: %s", err)
+			return
+		}
+		s.valuesMu.Lock()
+		if e := s.exprs; e != nil && i >= 0 && i < len(e.values) {
+			e.current = i
+		}
+		s.valuesMu.Unlock()
+	case "expr_end":
+		var p *ipc.PanicMessage
+		if err := msg.Decode(&p); err != nil {
+			errorf("ipc: %s", err)
+			return
+		}
+		s.valuesMu.Lock()
+		if e := s.exprs; e != nil && e.current >= 0 {
+			if p != nil {
+				e.errs[e.current] = &PanicError{Value: p.Value, Type: p.Type, Frames: parseStack(p.Stack, s.srcPath, s.srcMap)}
+			}
+			e.current = -1
+		}
+		s.valuesMu.Unlock()
+	case "panic":
+		var p ipc.PanicMessage
+		if err := msg.Decode(&p); err != nil {
+			errorf("ipc: %s", err)
+			return
+		}
+		s.valuesMu.Lock()
+		s.panicMsg = &p
+		s.valuesMu.Unlock()
+	case "input_request":
+		var req ipc.InputRequest
+		var reply ipc.InputReply
+		if s.Input == nil {
+			reply.Error = "standard input is not available"
+		} else if err := msg.Decode(&req); err != nil {
+			reply.Error = err.Error()
+		} else if reply.Value, err = s.Input(req.Prompt, req.Password); err != nil {
+			reply.Error = err.Error()
+		}
+		if err := ipc.Reply(conn, reply, nil); err != nil {
+			errorf("ipc: %s", err)
+		}
+	default:
+		if s.Handler != nil {
+			s.Handler(conn, msg)
+			return
+		}
+		debugf("ipc :: unhandled message type %q", msg.Type)
+	}
+}
+
+// Value is the value of a statement evaluated by the session.
+type Value struct {
+	// Text is the value as printed by the printer package.
+	Text string
+	// Data and Metadata hold the rich representations of the value by MIME type,
+	// if it has any; see package github.com/fabian-z/gopherlab/display.
+	Data     map[string]interface{}
+	Metadata map[string]interface{}
+}
+
+// Values returns the values printed by the program last run, in order.
+func (s *Session) Values() []Value {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
+	printed := s.values.String()
+	values := make([]Value, len(s.valueStarts))
+	for i, start := range s.valueStarts {
+		end := len(printed)
+		if i+1 < len(s.valueStarts) {
+			end = s.valueStarts[i+1]
+		}
+
+		values[i].Text = strings.TrimSuffix(printed[start:end], "\n")
+		if data := s.valueData[i]; data != nil {
+			values[i].Data, values[i].Metadata = data.Data, data.Metadata
+		}
+	}
+
+	return values
+}
+
+// redirectValues rewrites the printer function of f to print values to ipc.Values
+// instead of os.Stdout, keeping them apart from the output of the program. Every
+// value printed is marked with ipc.BeginValue, and its rich representations, such
+// as images or tables, are sent by display.Value; values which have a text
+// representation among them are not printed. The limits of printer are applied.
+func redirectValues(fset *token.FileSet, f *ast.File, printer printerSettings) {
+	obj := f.Scope.Lookup(printerName)
+	if obj == nil {
+		return
+	}
+
+	body := obj.Decl.(*ast.FuncDecl).Body
+	ast.Inspect(body, func(node ast.Node) bool {
+		if loop, ok := node.(*ast.RangeStmt); ok {
+			// __gore_ipc.BeginValue()
+			begin := &ast.ExprStmt{
+				X: &ast.CallExpr{
+					Fun: &ast.SelectorExpr{X: ast.NewIdent(ipcPkgName), Sel: ast.NewIdent("BeginValue")},
+				},
+			}
+			// if __gore_display.Value(x) { continue }
+			rich := &ast.IfStmt{
+				Cond: &ast.CallExpr{
+					Fun:  &ast.SelectorExpr{X: ast.NewIdent(displayPkgName), Sel: ast.NewIdent("Value")},
+					Args: []ast.Expr{loop.Value},
+				},
+				Body: &ast.BlockStmt{List: []ast.Stmt{&ast.BranchStmt{Tok: token.CONTINUE}}},
+			}
+			loop.Body.List = append([]ast.Stmt{begin, rich}, loop.Body.List...)
+			return true
+		}
+
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+			if fname, ok := fprintFuncs[sel.Sel.Name]; ok {
+				sel.Sel = ast.NewIdent(fname)
+				values := &ast.SelectorExpr{X: ast.NewIdent(ipcPkgName), Sel: ast.NewIdent("Values")}
+				call.Args = append([]ast.Expr{values}, call.Args...)
+			}
+		}
+		return true
+	})
+
+	// __gore_display.MaxDepth, __gore_display.MaxLength = depth, length
+	limits := []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: []ast.Expr{
+				&ast.SelectorExpr{X: ast.NewIdent(displayPkgName), Sel: ast.NewIdent("MaxDepth")},
+				&ast.SelectorExpr{X: ast.NewIdent(displayPkgName), Sel: ast.NewIdent("MaxLength")},
+			},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{
+				&ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(printer.maxDepth)},
+				&ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(printer.maxLength)},
+			},
+		},
+	}
+	if printer.pkg.depth != "" {
+		if stmt, err := parseStmt(fmt.Sprintf(printer.pkg.depth, printer.maxDepth)); err == nil {
+			limits = append(limits, stmt)
+		}
+	}
+	body.List = append(limits, body.List...)
+
+	astutil.AddNamedImport(fset, f, ipcPkgName, ipcPkgPath)
+	astutil.AddNamedImport(fset, f, displayPkgName, displayPkgPath)
+}
+
+// reportPanics rewrites the main function of f to report a panic to the kernel
+// by deferring ipc.HandlePanic, which sends the dynamic type of the panic value
+// along with the trace of the stack.
+func reportPanics(fset *token.FileSet, f *ast.File) {
+	obj := f.Scope.Lookup("main")
+	if obj == nil {
+		return
+	}
+
+	// defer __gore_ipc.HandlePanic()
+	handle := &ast.DeferStmt{
+		Call: &ast.CallExpr{
+			Fun: &ast.SelectorExpr{X: ast.NewIdent(ipcPkgName), Sel: ast.NewIdent("HandlePanic")},
+		},
+	}
+	body := obj.Decl.(*ast.FuncDecl).Body
+	body.List = append([]ast.Stmt{handle}, body.List...)
+
+	astutil.AddNamedImport(fset, f, ipcPkgName, ipcPkgPath)
+}
+
+// parseStmt parses a single statement.
+func parseStmt(src string) (ast.Stmt, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", "package p; func f() { "+src+" }", 0)
+	if err != nil {
+		return nil, err
+	}
+	return f.Decls[0].(*ast.FuncDecl).Body.List[0], nil
+}
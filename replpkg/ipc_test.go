@@ -0,0 +1,145 @@
+package replpkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fabian-z/gopherlab/ipc"
+)
+
+func TestRun_Stdin(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	var prompts int
+	s.Input = func(prompt string, password bool) (string, error) {
+		prompts++
+		return "hello gopher", nil
+	}
+
+	codes := []string{
+		`:import fmt`,
+		`var a, b string; fmt.Scan(&a, &b); fmt.Println(a, b)`,
+	}
+
+	var out string
+	for _, code := range codes {
+		out, err, _ = s.Eval(code)
+		noError(t, err)
+	}
+
+	if !strings.Contains(out, "gopher") {
+		t.Errorf("output should contain the input: %q", out)
+	}
+	if prompts == 0 {
+		t.Errorf("Input should have been called")
+	}
+}
+
+func TestRun_Stdin_evaluators(t *testing.T) {
+	for _, name := range []string{"interp", "worker"} {
+		s, err := NewSession()
+		noError(t, err)
+		defer s.Close()
+		s.Evaluator.Close()
+		s.Evaluator, err = s.NewEvaluator(name)
+		noError(t, err)
+
+		s.Input = func(prompt string, password bool) (string, error) {
+			return "hello gopher", nil
+		}
+
+		codes := []string{
+			`:import bufio`,
+			`:import fmt`,
+			`:import os`,
+			`sc := bufio.NewScanner(os.Stdin); sc.Scan(); fmt.Println(sc.Text())`,
+		}
+		var out string
+		for _, code := range codes {
+			out, err, _ = s.Eval(code)
+			noError(t, err)
+		}
+
+		if out != "hello gopher\n" {
+			t.Errorf("%s: the cell should read the input: %q", name, out)
+		}
+	}
+}
+
+func TestRun_Values(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	var stdout bytes.Buffer
+	s.Stdout = &stdout
+
+	codes := []string{
+		`:import fmt`,
+		`fmt.Println("streamed")`,
+		`1 + 1`,
+	}
+
+	var out string
+	for _, code := range codes {
+		out, err, _ = s.Eval(code)
+		noError(t, err)
+	}
+
+	if out != "2\n" {
+		t.Errorf("output should only contain the value: %q", out)
+	}
+	if !strings.Contains(stdout.String(), "streamed") {
+		t.Errorf("stdout should contain the printed line: %q", stdout.String())
+	}
+}
+
+func TestRun_RichValues(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	_, err, _ = s.Eval(`[]struct{ X, Y int }{{1, 2}, {3, 4}}`)
+	noError(t, err)
+
+	values := s.Values()
+	if len(values) != 1 {
+		t.Fatalf("there should be one value: %+v", values)
+	}
+	if values[0].Text == "" {
+		t.Errorf("the value should be printed as text as well")
+	}
+	if html, _ := values[0].Data["text/html"].(string); !strings.Contains(html, "<th>X</th>") {
+		t.Errorf("the value should be shown as a table: %+v", values[0].Data)
+	}
+}
+
+func TestSession_Caller(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	var callers []CellPos
+	s.Handler = func(conn *ipc.Conn, msg ipc.Message) {
+		var req ipc.CommMessage
+		msg.Decode(&req)
+		if pos, ok := s.Caller(req.Stack); ok {
+			callers = append(callers, pos)
+		}
+		ipc.Reply(conn, ipc.CommMessage{ID: "1"}, nil)
+	}
+
+	codes := []string{
+		`:import github.com/fabian-z/gopherlab/comm`,
+		"println(1)\nc, err := comm.Open(\"t\", nil)\nprintln(c.ID, err)",
+		`println(2)`,
+	}
+	for i, code := range codes {
+		s.Cell = i + 1
+		_, err, _ = s.Eval(code)
+		noError(t, err)
+	}
+
+	if len(callers) != 2 || callers[0] != callers[1] || callers[0].Cell != 2 || callers[0].Line != 2 {
+		t.Errorf("the comm should be opened by cell 2, line 2, as it runs again: %+v", callers)
+	}
+}
@@ -1,11 +1,9 @@
 package replpkg
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"strings"
-	"text/scanner"
 
 	"github.com/peterh/liner"
 )
@@ -99,22 +97,5 @@ func (cl *contLiner) Reindent() error {
 }
 
 func (cl *contLiner) countDepth() int {
-	reader := bytes.NewBufferString(cl.buffer)
-	sc := new(scanner.Scanner)
-	sc.Init(reader)
-	sc.Error = func(_ *scanner.Scanner, msg string) {
-		debugf("scanner: %s", msg)
-	}
-
-	depth := 0
-	for {
-		switch sc.Scan() {
-		case '{':
-			depth++
-		case '}':
-			depth--
-		case scanner.EOF:
-			return depth
-		}
-	}
+	return countDepth(cl.buffer)
 }
@@ -0,0 +1,260 @@
+package replpkg
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PanicError is returned by Eval if the program panicked, or failed with a fatal
+// error of the runtime such as a deadlock.
+type PanicError struct {
+	// Value is the panic value, or the message of a fatal error.
+	Value string
+	// Type is the dynamic type of the panic value, if known.
+	Type string
+	// Fatal is set for fatal errors, which are not panics.
+	Fatal bool
+	// Frames are the calls on the stack of the failing goroutine, innermost first.
+	Frames []Frame
+}
+
+func (e *PanicError) Error() string {
+	if e.Fatal {
+		return "fatal error: " + e.Value
+	}
+	return "panic: " + e.Value
+}
+
+// Deadlock reports whether the program failed because all goroutines were blocked.
+func (e *PanicError) Deadlock() bool {
+	return e.Fatal && strings.HasPrefix(e.Value, "all goroutines are asleep")
+}
+
+// Frame is a call on the stack of a failing goroutine.
+type Frame struct {
+	Func string
+	File string
+	Line int
+	// Pos locates calls in the statements entered in cells; its Line is 0 for
+	// the calls in the runtime, packages and code generated by the session.
+	Pos CellPos
+}
+
+var (
+	// panicPrefixes start the output of the runtime for panics and fatal errors.
+	panicPrefixes = []string{"panic: ", "fatal error: "}
+
+	goroutinePattern = regexp.MustCompile(`^goroutine \d+ \[.*\]:$`)
+	locationPattern  = regexp.MustCompile(`^\t(.+):(\d+)( \+0x[0-9a-f]+)?$`)
+	recoveredPattern = regexp.MustCompile(` \[recovered.*\]$`)
+)
+
+// panicWriter passes output on to w until a line starts like the report of a
+// panic or fatal error, which is held back from then on.
+type panicWriter struct {
+	w io.Writer
+
+	mu      sync.Mutex // guards the fields below
+	line    []byte     // start of the current line, if it may start a report
+	midLine bool       // the current line has been passed on in part
+	held    bytes.Buffer
+	holding bool
+}
+
+func (pw *panicWriter) Write(p []byte) (int, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	n := len(p)
+	for len(p) > 0 {
+		if pw.holding {
+			pw.held.Write(p)
+			break
+		}
+
+		i := bytes.IndexByte(p, '\n')
+		chunk := p
+		if i >= 0 {
+			chunk = p[:i+1]
+		}
+		p = p[len(chunk):]
+
+		if pw.midLine {
+			if _, err := pw.w.Write(chunk); err != nil {
+				return n - len(p) - len(chunk), err
+			}
+			pw.midLine = i < 0
+			continue
+		}
+
+		pw.line = append(pw.line, chunk...)
+		switch {
+		case startsReport(pw.line, true):
+			pw.holding = true
+			pw.held.Write(pw.line)
+			pw.line = nil
+		case i < 0 && startsReport(pw.line, false):
+			// wait for more of the line
+		default:
+			if _, err := pw.w.Write(pw.line); err != nil {
+				return n - len(p), err
+			}
+			pw.midLine = i < 0
+			pw.line = nil
+		}
+	}
+
+	return n, nil
+}
+
+// startsReport reports whether line starts the report of a panic, or if not
+// complete, whether it may still do so.
+func startsReport(line []byte, complete bool) bool {
+	for _, prefix := range panicPrefixes {
+		if complete && bytes.HasPrefix(line, []byte(prefix)) {
+			return true
+		}
+		if !complete && bytes.HasPrefix([]byte(prefix), line) {
+			return true
+		}
+	}
+	return false
+}
+
+// heldOutput returns the output held back.
+func (pw *panicWriter) heldOutput() string {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	return pw.held.String() + string(pw.line)
+}
+
+// flush passes the output held back on to w.
+func (pw *panicWriter) flush() error {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	pw.held.Write(pw.line)
+	pw.line = nil
+	_, err := pw.w.Write(pw.held.Bytes())
+	pw.held.Reset()
+	return err
+}
+
+// panicError returns the error for the panic of the program last run, reported
+// either over the ipc channel or in the output held back, or nil if it did not
+// panic.
+func (s *Session) panicError(held string) *PanicError {
+	s.valuesMu.Lock()
+	msg := s.panicMsg
+	s.valuesMu.Unlock()
+
+	if msg != nil {
+		return &PanicError{Value: msg.Value, Type: msg.Type, Frames: parseStack(msg.Stack, s.FilePath, s.srcMap)}
+	}
+	return parsePanic(held, s.FilePath, s.srcMap)
+}
+
+// parsePanic parses the report of a panic or fatal error printed by the runtime,
+// locating the calls in the program file in the cells according to srcMap. It
+// returns nil if report is no such report.
+func parsePanic(report string, programPath string, srcMap *sourceMap) *PanicError {
+	var err *PanicError
+	for _, prefix := range panicPrefixes {
+		if strings.HasPrefix(report, prefix) {
+			line := strings.SplitN(report[len(prefix):], "\n", 2)[0]
+			err = &PanicError{
+				Value: recoveredPattern.ReplaceAllString(line, ""),
+				Fatal: prefix == "fatal error: ",
+			}
+			break
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	err.Frames = parseStack(report, programPath, srcMap)
+	return err
+}
+
+// Caller returns the position in its cell of the statement of a cell running the
+// call whose goroutine has the trace stack, as printed by the runtime, in the
+// program running: that of the outermost call in the cells. It returns false if
+// there is none, and for the interp evaluator, which runs no statement again.
+func (s *Session) Caller(stack string) (CellPos, bool) {
+	if _, ok := s.Evaluator.(*interpreter); ok {
+		return CellPos{}, false
+	}
+
+	frames := parseStack(stack, s.srcPath, s.srcMap)
+	for i := len(frames) - 1; i >= 0; i-- {
+		if frames[i].Pos.Line != 0 {
+			return frames[i].Pos, true
+		}
+	}
+	return CellPos{}, false
+}
+
+// parseStack parses the calls of the first goroutine in the trace stack, from the
+// one which panicked on.
+func parseStack(stack string, programPath string, srcMap *sourceMap) []Frame {
+	var frames []Frame
+
+	sc := bufio.NewScanner(strings.NewReader(stack))
+	inGoroutine := false
+	for sc.Scan() {
+		text := sc.Text()
+		if !inGoroutine {
+			inGoroutine = goroutinePattern.MatchString(text)
+			continue
+		}
+		if text == "" {
+			break
+		}
+
+		m := locationPattern.FindStringSubmatch(text)
+		if m == nil {
+			// the function of the next frame
+			fn := strings.TrimPrefix(text, "created by ")
+			if i := strings.Index(fn, " in goroutine "); i > 0 {
+				fn = fn[:i]
+			}
+			if i := strings.LastIndexByte(fn, '('); i > 0 {
+				fn = fn[:i]
+			}
+			frames = append(frames, Frame{Func: fn})
+			continue
+		}
+		if len(frames) == 0 || frames[len(frames)-1].File != "" {
+			continue
+		}
+
+		frame := &frames[len(frames)-1]
+		frame.File = m[1]
+		frame.Line, _ = strconv.Atoi(m[2])
+		if filepath.Base(frame.File) == filepath.Base(programPath) {
+			frame.Pos, _ = srcMap.locateLine(frame.Line)
+		}
+	}
+
+	// leave out the frames of the panic itself, such as those of ipc.HandlePanic,
+	// and lines following the trace
+	for i := len(frames) - 1; i >= 0; i-- {
+		if frames[i].Func == "panic" {
+			frames = frames[i+1:]
+			break
+		}
+	}
+	if n := len(frames); n > 0 && frames[n-1].File == "" {
+		frames = frames[:n-1]
+	}
+
+	return frames
+}
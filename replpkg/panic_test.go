@@ -0,0 +1,98 @@
+package replpkg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRun_Panic(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	s.Cell = 4
+	_, err, _ = s.Eval("var e error\n_ = e\npanic(fmt.Errorf(\"boom %d\", 1))")
+
+	panicErr, ok := err.(*PanicError)
+	if !ok {
+		t.Fatalf("should be a panic: %#v", err)
+	}
+	if panicErr.Value != "boom 1" || panicErr.Type != "*errors.errorString" {
+		t.Errorf("got value %q of type %q", panicErr.Value, panicErr.Type)
+	}
+
+	var located bool
+	for _, frame := range panicErr.Frames {
+		if frame.Pos.Line != 0 {
+			located = true
+			if frame.Pos.Cell != 4 || frame.Pos.Line != 3 || frame.Func != "main.main" {
+				t.Errorf("the panic should be located in the cell: %+v", frame)
+			}
+		}
+	}
+	if !located {
+		t.Errorf("no frame located in the cell: %+v", panicErr.Frames)
+	}
+
+	out, err, _ := s.Eval(`1 + 1`)
+	noError(t, err)
+	if out != "2\n" {
+		t.Errorf("the failed cell should have been dropped: %q", out)
+	}
+}
+
+func TestPanicWriter(t *testing.T) {
+	var out bytes.Buffer
+	pw := &panicWriter{w: &out}
+
+	for _, s := range []string{"some output\npan", "ic is not a report\n", "pan", "ic: boom\n\ngoroutine 1 [running]:\n"} {
+		pw.Write([]byte(s))
+	}
+
+	if got := out.String(); got != "some output\npanic is not a report\n" {
+		t.Errorf("got output %q", got)
+	}
+	if got := pw.heldOutput(); got != "panic: boom\n\ngoroutine 1 [running]:\n" {
+		t.Errorf("got held output %q", got)
+	}
+
+	pw.flush()
+	if got := out.String(); got != "some output\npanic is not a report\npanic: boom\n\ngoroutine 1 [running]:\n" {
+		t.Errorf("got flushed output %q", got)
+	}
+}
+
+func TestParsePanic(t *testing.T) {
+	const report = `fatal error: all goroutines are asleep - deadlock!
+
+goroutine 1 [chan receive]:
+main.main()
+	/tmp/gore_session.go:3 +0x2d
+exit status 2
+`
+
+	m := &sourceMap{
+		src: "func main() {\n\tch := make(chan int)\n\t<-ch\n}\n",
+		stmts: []stmtSpan{
+			{15, 35, &origin{cell: 2, src: "ch := make(chan int)", pos: 0, end: 20}},
+			{37, 41, &origin{cell: 5, src: "<-ch", pos: 0, end: 4}},
+		},
+	}
+
+	err := parsePanic(report, "/tmp/gore_session.go", m)
+	if err == nil {
+		t.Fatal("should be parsed")
+	}
+	if !err.Fatal || !err.Deadlock() {
+		t.Errorf("should be a deadlock: %+v", err)
+	}
+	if len(err.Frames) != 1 {
+		t.Fatalf("got frames %+v", err.Frames)
+	}
+	if frame := err.Frames[0]; frame.Func != "main.main" || frame.Line != 3 || frame.Pos.Cell != 5 || frame.Pos.Source != "<-ch" {
+		t.Errorf("got frame %+v", frame)
+	}
+
+	if parsePanic("exit status 1\n", "/tmp/gore_session.go", m) != nil {
+		t.Error("should not be parsed")
+	}
+}
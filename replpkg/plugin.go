@@ -0,0 +1,432 @@
+package replpkg
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const (
+	workerPkgPath = "github.com/fabian-z/gopherlab/worker"
+	workerPkgName = "__gore_worker"
+
+	// cellFuncName is the function the plugins of cells run, worker.CellFunc.
+	cellFuncName = "GoreCell"
+)
+
+// sourceEdit replaces the bytes from pos to end of a source by text.
+type sourceEdit struct {
+	pos, end int
+	text     string
+}
+
+// cellPlugin returns the source of the plugin running the statements added by the
+// last call to Eval in the worker, and the statements of the session the body of
+// its cell function ends with, as newSourceMap expects them.
+//
+// The variables of earlier cells the statements use are pointers in the plugin,
+// looked up with worker.Var, and the variables they define are registered with
+// worker.SetVar for the later cells. The constants and types declared by earlier
+// cells are declared again, as are the declarations at package scope. A
+// fallbackError is returned if the value of a variable cannot be passed from one
+// plugin to another, or if cells declare variables at package scope, which every
+// plugin would declare anew.
+func (s *Session) cellPlugin() ([]byte, []ast.Stmt, error) {
+	source, err := s.source(false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "gore_session.go", source, parser.Mode(0))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, decl := range f.Decls {
+		if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.VAR {
+			return nil, nil, fallbackError("variables declared at package scope cannot be kept in the worker")
+		}
+	}
+
+	files := []*ast.File{f}
+	for _, path := range s.ExtraFilePaths {
+		ext, err := parser.ParseFile(fset, path, nil, parser.Mode(0))
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, ext)
+	}
+
+	info := types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	config := types.Config{Importer: s.Types.Importer, Error: func(error) {}}
+	pkg, _ := config.Check("main", fset, files, &info)
+
+	mainDecl := f.Scope.Lookup("main").Decl.(*ast.FuncDecl)
+	body := mainDecl.Body.List
+	if len(body) != len(s.mainBody.List) {
+		return nil, nil, fmt.Errorf("session source does not match its statements")
+	}
+
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+	text := func(node ast.Node) string {
+		return source[offset(node.Pos()):offset(node.End())]
+	}
+
+	// the statements of earlier cells declare variables, constants and types, and
+	// those of the last call to Eval are run
+	var earlierVars []*types.Var
+	isEarlier := make(map[types.Object]bool)
+	var decls, stmts []ast.Stmt
+	var sessionStmts []ast.Stmt
+	for i, stmt := range body {
+		if o := s.origins[s.mainBody.List[i]]; o != nil && o.eval == s.evals {
+			stmts = append(stmts, stmt)
+			sessionStmts = append(sessionStmts, s.mainBody.List[i])
+			continue
+		}
+
+		for _, v := range definedVars(stmt, &info) {
+			earlierVars = append(earlierVars, v)
+			isEarlier[v] = true
+		}
+		if decl, ok := stmt.(*ast.DeclStmt); ok && decl.Decl.(*ast.GenDecl).Tok != token.VAR {
+			decls = append(decls, stmt)
+		}
+	}
+
+	used := make(map[types.Object]bool)
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok && isEarlier[info.Uses[ident]] {
+				used[info.Uses[ident]] = true
+			}
+			return true
+		})
+	}
+
+	imports := newImportNames(f, pkg)
+
+	var lines []string
+	var aligned []ast.Stmt
+	for _, v := range earlierVars {
+		if !used[v] {
+			continue
+		}
+		typ, err := imports.typeString(v.Type())
+		if err != nil {
+			return nil, nil, fallbackError(fmt.Sprintf("%s cannot be used by later cells: %s", v.Name(), err))
+		}
+		lines = append(lines, fmt.Sprintf("%s := %s.Var(%q).(*%s)", v.Name(), workerPkgName, s.varKey(v.Name()), typ))
+		aligned = append(aligned, nil)
+	}
+
+	for _, decl := range decls {
+		lines = append(lines, text(decl))
+		aligned = append(aligned, nil)
+	}
+
+	var defined []*types.Var
+	for i, stmt := range stmts {
+		defined = append(defined, definedVars(stmt, &info)...)
+
+		var edits []sourceEdit
+		var before, after []string
+
+		// a short variable declaration may assign to variables of earlier cells,
+		// which it cannot dereference: it assigns to temporary variables instead
+		replaced := make(map[*ast.Ident]bool)
+		if assign, ok := stmt.(*ast.AssignStmt); ok && assign.Tok == token.DEFINE {
+			for _, lhs := range assign.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || !isEarlier[info.Uses[ident]] {
+					continue
+				}
+				typ, _ := imports.typeString(info.Uses[ident].Type())
+				tmp := "__gore_tmp_" + ident.Name
+				before = append(before, fmt.Sprintf("var %s %s", tmp, typ))
+				edits = append(edits, sourceEdit{offset(ident.Pos()), offset(ident.End()), tmp})
+				after = append(after, fmt.Sprintf("*%s = %s", ident.Name, tmp))
+				replaced[ident] = true
+			}
+		}
+
+		ast.Inspect(stmt, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok && !replaced[ident] && isEarlier[info.Uses[ident]] {
+				edits = append(edits, sourceEdit{offset(ident.Pos()), offset(ident.End()), "(*" + ident.Name + ")"})
+			}
+			return true
+		})
+
+		for _, line := range before {
+			lines = append(lines, line)
+			aligned = append(aligned, nil)
+		}
+		lines = append(lines, applyEdits(source, offset(stmt.Pos()), offset(stmt.End()), edits))
+		aligned = append(aligned, sessionStmts[i])
+		for _, line := range after {
+			lines = append(lines, line)
+			aligned = append(aligned, nil)
+		}
+	}
+
+	for _, v := range defined {
+		lines = append(lines, fmt.Sprintf("%s.SetVar(%q, &%s)", workerPkgName, v.Name(), v.Name()))
+		aligned = append(aligned, nil)
+	}
+
+	// the cell function takes the place of main
+	var src bytes.Buffer
+	src.WriteString(source[:offset(mainDecl.Pos())])
+	fmt.Fprintf(&src, "func %s() {\n%s\n}", cellFuncName, strings.Join(lines, "\n"))
+	src.WriteString(source[offset(mainDecl.End()):])
+
+	pf, err := parser.ParseFile(fset, "gore_cell.go", src.Bytes(), parser.Mode(0))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	redirectValues(fset, pf, s.printer)
+	astutil.AddNamedImport(fset, pf, workerPkgName, workerPkgPath)
+	for _, imp := range imports.added {
+		astutil.AddNamedImport(fset, pf, imports.names[imp], imp)
+	}
+	imports.removeUnused(pf)
+
+	var out bytes.Buffer
+	if err := printer.Fprint(&out, fset, pf); err != nil {
+		return nil, nil, err
+	}
+
+	return out.Bytes(), aligned, nil
+}
+
+// definedVars returns the variables declared by stmt, a statement of main.
+func definedVars(stmt ast.Stmt, info *types.Info) []*types.Var {
+	var idents []*ast.Ident
+	switch stmt := stmt.(type) {
+	case *ast.AssignStmt:
+		if stmt.Tok == token.DEFINE {
+			for _, lhs := range stmt.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					idents = append(idents, ident)
+				}
+			}
+		}
+	case *ast.DeclStmt:
+		if decl := stmt.Decl.(*ast.GenDecl); decl.Tok == token.VAR {
+			for _, spec := range decl.Specs {
+				idents = append(idents, spec.(*ast.ValueSpec).Names...)
+			}
+		}
+	}
+
+	var vars []*types.Var
+	for _, ident := range idents {
+		if v, ok := info.Defs[ident].(*types.Var); ok && v.Name() != "_" {
+			vars = append(vars, v)
+		}
+	}
+	return vars
+}
+
+// applyEdits returns the bytes from pos to end of src with edits applied.
+func applyEdits(src string, pos, end int, edits []sourceEdit) string {
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].pos < edits[j].pos
+	})
+
+	var buf bytes.Buffer
+	for _, edit := range edits {
+		buf.WriteString(src[pos:edit.pos])
+		buf.WriteString(edit.text)
+		pos = edit.end
+	}
+	buf.WriteString(src[pos:end])
+
+	return buf.String()
+}
+
+// importNames names the packages in the source of a plugin.
+type importNames struct {
+	names map[string]string // by package path
+	added []string          // paths of the packages to import in addition
+}
+
+// newImportNames returns the names of the packages imported by the session source
+// f, checked as pkg.
+func newImportNames(f *ast.File, pkg *types.Package) *importNames {
+	pkgNames := make(map[string]string)
+	if pkg != nil {
+		for _, imp := range pkg.Imports() {
+			pkgNames[imp.Path()] = imp.Name()
+		}
+	}
+
+	imports := &importNames{names: make(map[string]string)}
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		switch {
+		case imp.Name != nil && imp.Name.Name != "_" && imp.Name.Name != ".":
+			imports.names[path] = imp.Name.Name
+		case imp.Name == nil && pkgNames[path] != "":
+			imports.names[path] = pkgNames[path]
+		}
+	}
+	return imports
+}
+
+// typeString returns the type t as written in the plugin, importing the packages
+// it refers to. Types which are the same in every plugin can be written: they must
+// not be declared by the session, nor unexported by their packages.
+func (imports *importNames) typeString(t types.Type) (string, error) {
+	if err := passable(t, make(map[types.Type]bool)); err != nil {
+		return "", err
+	}
+
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if name, ok := imports.names[pkg.Path()]; ok {
+			return name
+		}
+		name := "__gore_pkg_" + pkg.Name()
+		imports.names[pkg.Path()] = name
+		imports.added = append(imports.added, pkg.Path())
+		return name
+	}), nil
+}
+
+// passable returns an error if values of type t cannot be passed from one plugin to
+// another.
+func passable(t types.Type, seen map[types.Type]bool) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	switch t := t.(type) {
+	case *types.Named:
+		obj := t.Obj()
+		switch {
+		case obj.Pkg() == nil:
+			return nil
+		case obj.Pkg().Path() == "main":
+			return fmt.Errorf("type %s is declared in the session", obj.Name())
+		case !obj.Exported():
+			return fmt.Errorf("type %s.%s is not exported", obj.Pkg().Name(), obj.Name())
+		}
+	case *types.Pointer:
+		return passable(t.Elem(), seen)
+	case *types.Slice:
+		return passable(t.Elem(), seen)
+	case *types.Array:
+		return passable(t.Elem(), seen)
+	case *types.Chan:
+		return passable(t.Elem(), seen)
+	case *types.Map:
+		if err := passable(t.Key(), seen); err != nil {
+			return err
+		}
+		return passable(t.Elem(), seen)
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			field := t.Field(i)
+			if !field.Exported() {
+				return fmt.Errorf("struct field %s is not exported", field.Name())
+			}
+			if err := passable(field.Type(), seen); err != nil {
+				return err
+			}
+		}
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				if err := passable(tuple.At(i).Type(), seen); err != nil {
+					return err
+				}
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumMethods(); i++ {
+			method := t.Method(i)
+			if !method.Exported() {
+				return fmt.Errorf("interface method %s is not exported", method.Name())
+			}
+			if err := passable(method.Type(), seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeUnused removes the imports of f which are not used any more.
+func (imports *importNames) removeUnused(f *ast.File) {
+	used := make(map[string]bool)
+	ast.Inspect(f, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	isUsed := func(imp *ast.ImportSpec) bool {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		name := path.Base(p)
+		if n, ok := imports.names[p]; ok {
+			name = n
+		}
+		if imp.Name != nil {
+			if imp.Name.Name == "_" || imp.Name.Name == "." {
+				return true
+			}
+			name = imp.Name.Name
+		}
+		return used[name]
+	}
+
+	var decls []ast.Decl
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			decls = append(decls, decl)
+			continue
+		}
+
+		var specs []ast.Spec
+		for _, spec := range gen.Specs {
+			if isUsed(spec.(*ast.ImportSpec)) {
+				specs = append(specs, spec)
+			}
+		}
+		if len(specs) > 0 {
+			gen.Specs = specs
+			decls = append(decls, gen)
+		}
+	}
+	f.Decls = decls
+
+	var specs []*ast.ImportSpec
+	for _, imp := range f.Imports {
+		if isUsed(imp) {
+			specs = append(specs, imp)
+		}
+	}
+	f.Imports = specs
+}
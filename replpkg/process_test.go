@@ -0,0 +1,34 @@
+package replpkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSession_Interrupt(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	done := make(chan error)
+	go func() {
+		_, err, _ := s.Eval(`for {}`)
+		done <- err
+	}()
+
+	for !s.Interrupt() {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case err = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Eval should return after Interrupt")
+	}
+	if err != ErrInterrupt {
+		t.Fatalf("Eval should return ErrInterrupt: got %v", err)
+	}
+
+	if len(s.mainBody.List) != 0 {
+		t.Errorf("interrupted statements should be removed: %s", showNode(s.Fset, s.mainBody))
+	}
+}
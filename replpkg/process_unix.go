@@ -0,0 +1,26 @@
+// +build !windows
+
+package replpkg
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup makes cmd start a process group of its own, so that the
+// processes it starts can be stopped together with it.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills the process group started by cmd.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// interruptProcess sends SIGINT to the process started by cmd, leaving the other
+// processes of its group running.
+func interruptProcess(cmd *exec.Cmd) error {
+	return cmd.Process.Signal(os.Interrupt)
+}
@@ -0,0 +1,24 @@
+package replpkg
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup makes cmd start a process group of its own, so that the
+// processes it starts can be stopped together with it.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killProcessGroup kills the process started by cmd. Windows offers no way to
+// kill the whole group, so the processes it starts may survive.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// interruptProcess kills the process started by cmd, as Windows cannot send it
+// an interrupt.
+func interruptProcess(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
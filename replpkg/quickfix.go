@@ -73,7 +73,9 @@ quickFixAttempt:
 
 						stmts := s.mainBody.List[0:i]
 						for _, expr := range exprs {
-							stmts = append(stmts, &ast.ExprStmt{expr})
+							exprStmt := &ast.ExprStmt{X: expr}
+							s.origins[exprStmt] = s.origins[stmt]
+							stmts = append(stmts, exprStmt)
 						}
 
 						s.mainBody.List = append(stmts, s.mainBody.List[i+1:]...)
@@ -127,7 +129,9 @@ func (s *Session) clearQuickFix() {
 			s.mainBody.List, trailing = s.mainBody.List[0:i], s.mainBody.List[i+1:]
 			for _, expr := range exprs {
 				if !isNamedIdent(expr, "_") {
-					s.mainBody.List = append(s.mainBody.List, &ast.ExprStmt{X: expr})
+					exprStmt := &ast.ExprStmt{X: expr}
+					s.origins[exprStmt] = s.origins[stmt]
+					s.mainBody.List = append(s.mainBody.List, exprStmt)
 				}
 			}
 
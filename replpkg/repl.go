@@ -12,6 +12,8 @@ Some special functionalities are provided as commands, which starts with colons:
 	:print                  Prints current source code
 	:write [<filename>]     Writes out current code
 	:doc <target>           Shows documentation for an expression or package name given
+	:printer [<package>]    Selects how values are printed, and their depth and length limits
+	:cells [drop <n>]       Lists the cells run, or drops one
 	:help                   Lists commands
 	:quit                   Quit the session
 */
@@ -21,11 +23,14 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"syscall"
 
 	"go/ast"
@@ -41,16 +46,24 @@ import (
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/motemen/go-quickfix"
+
+	"github.com/fabian-z/gopherlab/display"
+	"github.com/fabian-z/gopherlab/ipc"
 )
 
 const version = "0.2.6"
 const printerName = "__gore_p"
 
+// checkPkgPath is the package path the session source is type checked as.
+const checkPkgPath = "_tmp"
+
 var (
 	flagAutoImport = flag.Bool("autoimport", false, "formats and adjusts imports automatically")
 	flagExtFiles   = flag.String("context", "",
 		"import packages, functions, variables and constants from external golang source files")
-	flagPkg = flag.String("pkg", "", "specify a package where the session will be run inside")
+	flagPkg       = flag.String("pkg", "", "specify a package where the session will be run inside")
+	flagEvaluator = flag.String("evaluator", "run",
+		`how cells are run: "run" builds and runs the whole session again, "worker" runs every cell once in a worker process, "interp" interprets every cell in the kernel`)
 )
 
 func homeDir() (home string, err error) {
@@ -80,8 +93,84 @@ type Session struct {
 	StdoutChannel	chan string
 	StderrChannel	chan string
 
+	// Stdout and Stderr receive the output of the evaluated program as it is
+	// produced. If Stdout is nil, the output is returned by Eval, followed by
+	// the printed values.
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Input is called when the evaluated program waits for input on its standard
+	// input, and returns the next line of input. If nil, the program's standard
+	// input is empty, or kept open by the process of the worker evaluator. Waiting programs are found through /proc, so on systems
+	// other than Linux the standard input is always empty.
+	Input func(prompt string, password bool) (string, error)
+
+	// Handler receives the messages sent by the evaluated program on the ipc
+	// channel which the session does not handle itself.
+	Handler ipc.Handler
+
+	// Cell is the number of the cell whose code is passed to Eval. Compile errors
+	// in the statements of a cell refer to it by this number.
+	Cell int
+
+	// CellID is the ID of the cell whose code is passed to Eval, if the frontend
+	// sends one. The statements and declarations of the earlier runs of the cell
+	// are replaced in place by those of its code.
+	CellID string
+
+	// Exprs are expressions evaluated along with the next cell passed to Eval by
+	// the "run" evaluator, which prints their values after the statements of the
+	// session; EvalExprs then returns them instead of running the session again.
+	Exprs []string
+
+	// Evaluator runs the statements of the session for Eval. NewSession sets the
+	// one selected by the -evaluator flag; another one, see NewEvaluator, may be
+	// set before the first call to Eval. If it cannot run the statements of a cell,
+	// the session falls back to running the whole session again.
+	Evaluator Evaluator
+
+	// evals counts the calls to Eval.
+	evals int
+
+	// origins holds the cells the statements of main and the declarations at
+	// package scope come from, and srcMap their lines in the program last run,
+	// the file srcPath.
+	origins map[ast.Node]*origin
+	srcMap  *sourceMap
+	srcPath string
+
 	mainBody         *ast.BlockStmt
 	storedBodyLength int
+
+	// declared holds the names declared at package scope by the last call to
+	// Eval, and replaced the declarations they replace, see restoreMainBody.
+	declared []string
+	replaced []removedDecl
+
+	// shadows counts the declarations of main renamed as they are declared again,
+	// shadowed holds those renamed by the last call to Eval, and renamed the names
+	// renamed variables have been declared with, by their new names.
+	shadows  int
+	shadowed []shadowing
+	renamed  map[string]string
+
+	// snapshot is the session source before the last call to Eval replaced the
+	// statements of its cell, see replaceCell.
+	snapshot *cellSnapshot
+
+	// printer selects how values are printed, see the :printer command.
+	printer printerSettings
+
+	valuesMu    sync.Mutex // guards values, valueStarts, valueData, panicMsg and exprs
+	values      bytes.Buffer
+	valueStarts []int                 // offsets in values where the value of a statement begins
+	valueData   []*ipc.DisplayMessage // rich representations of the values, if any
+	panicMsg    *ipc.PanicMessage     // the panic of the main goroutine, if any
+	exprs       *exprsRun             // Exprs as evaluated along with the last cell, if any
+
+	runMu       sync.Mutex // guards running and interrupted
+	running     *exec.Cmd
+	interrupted bool
 }
 
 const initialSourceTemplate = `
@@ -99,15 +188,30 @@ func main() {
 }
 `
 
-// printerPkgs is a list of packages that provides
-// pretty printing function. Preceding first.
-var printerPkgs = []struct {
+// printerPkg is a package providing a pretty printing function.
+type printerPkg struct {
+	name string
 	path string
 	code string
-}{
-	{"github.com/k0kubun/pp", `pp.Println(x)`},
-	{"github.com/davecgh/go-spew/spew", `spew.Printf("%#v\n", x)`},
-	{"fmt", `fmt.Printf("%#v\n", x)`},
+	// depth is the statement limiting the depth of the printed values, if the
+	// package supports it.
+	depth string
+}
+
+// printerPkgs is a list of packages that provides
+// pretty printing function. Preceding first.
+var printerPkgs = []printerPkg{
+	{"pp", "github.com/k0kubun/pp", `pp.Println(x)`, ""},
+	{"spew", "github.com/davecgh/go-spew/spew", `spew.Printf("%#v\n", x)`, "spew.Config.MaxDepth = %d"},
+	{"fmt", "fmt", `fmt.Printf("%#v\n", x)`, ""},
+}
+
+// printerSettings select how the values of cells are printed.
+type printerSettings struct {
+	pkg printerPkg
+	// maxDepth and maxLength limit the nesting and the number of elements of the
+	// values shown, see package github.com/fabian-z/gopherlab/display.
+	maxDepth, maxLength int
 }
 
 func NewSession() (*Session, error) {
@@ -120,6 +224,8 @@ func NewSession() (*Session, error) {
 		},
 		StdoutChannel: make(chan string, 1),
 		StderrChannel: make(chan string, 1),
+		origins:       make(map[ast.Node]*origin),
+		renamed:       make(map[string]string),
 	}
 
 	s.FilePath, err = tempFile()
@@ -127,11 +233,17 @@ func NewSession() (*Session, error) {
 		return nil, err
 	}
 
+	s.Evaluator, err = s.NewEvaluator(*flagEvaluator)
+	if err != nil {
+		return nil, err
+	}
+
 	var initialSource string
 	for _, pp := range printerPkgs {
 		_, err := s.Types.Importer.Import(pp.path)
 		if err == nil {
 			initialSource = fmt.Sprintf(initialSourceTemplate, pp.path, pp.code)
+			s.printer = printerSettings{pp, display.MaxDepth, display.MaxLength}
 			break
 		}
 		debugf("could not import %q: %s", pp.path, err)
@@ -155,18 +267,69 @@ func (s *Session) mainFunc() *ast.FuncDecl {
 	return s.File.Scope.Lookup("main").Decl.(*ast.FuncDecl)
 }
 
+// Run runs the statements added by the last call to Eval with the evaluator of the
+// session, and returns their output, followed by the values printed, along with a
+// copy of their standard error. If the evaluator cannot run them, the session runs
+// the whole session again from then on.
 func (s *Session) Run() ([]byte, error, bytes.Buffer) {
-	f, err := os.Create(s.FilePath)
+	output, err, stderr := s.Evaluator.Run()
+	if _, ok := err.(fallbackError); !ok {
+		return output, err, stderr
+	}
+
+	errorf("%s; running the whole session from now on", err)
+	if s.Stderr != nil {
+		fmt.Fprintf(s.Stderr, "note: %s; running the whole session from now on\n", err)
+	}
+	s.Evaluator.Close()
+	s.Evaluator = &runner{s}
+	return s.Evaluator.Run()
+}
+
+// outputWriters returns the writers of the output of the program run: stdout
+// receives its standard output unless s.Stdout is set, and stderr a copy of its
+// standard error.
+func (s *Session) outputWriters(stdout, stderr *bytes.Buffer) (io.Writer, io.Writer) {
+	var outw, errw io.Writer = stdout, stderr
+	if s.Stdout != nil {
+		outw = s.Stdout
+	}
+	if s.Stderr != nil {
+		errw = io.MultiWriter(stderr, s.Stderr)
+	}
+	return outw, errw
+}
+
+// resetValues forgets the values and the panic of the program run before.
+func (s *Session) resetValues() {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
+	s.values.Reset()
+	s.valueStarts = nil
+	s.valueData = nil
+	s.panicMsg = nil
+}
+
+// program returns a copy of the session source which is adjusted to run under
+// the kernel: values are printed to the ipc channel, and panics are reported to
+// it. These changes are kept out of the session source.
+func (s *Session) program() (*token.FileSet, *ast.File, error) {
+	source, err := s.source(false)
 	if err != nil {
-		return []byte{}, err, bytes.Buffer{}
+		return nil, nil, err
 	}
 
-	err = printer.Fprint(f, s.Fset, s.File)
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "gore_session.go", splitMain(source), parser.Mode(0))
 	if err != nil {
-		return []byte{}, err, bytes.Buffer{}
+		return nil, nil, err
 	}
 
-	return goRun(append(s.ExtraFilePaths, s.FilePath))
+	redirectValues(fset, f, s.printer)
+	reportPanics(fset, f)
+
+	return fset, f, nil
 }
 
 func tempFile() (string, error) {
@@ -183,25 +346,118 @@ func tempFile() (string, error) {
 	return filepath.Join(dir, "gore_session.go"), nil
 }
 
-func goRun(files []string) ([]byte, error, bytes.Buffer) {
-
-	var stderr bytes.Buffer
+// goRun builds the program from files and runs it. If it does not compile, the
+// compiler errors are returned as a *CompileError instead of being written to
+// stderr.
+func (s *Session) goRun(files []string, env []string, stdout, stderr io.Writer) error {
+	bin := filepath.Join(filepath.Dir(s.FilePath), "gore_session")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
 
-	args := append([]string{"run"}, files...)
+	args := append([]string{"build", "-o", bin}, files...)
 	debugf("go %s", strings.Join(args, " "))
-	cmd := exec.Command("go", args...)
-	
-	//TODO: Support Stdin from notebook / lab
-	cmd.Stdin = os.Stdin
-	//cmd.Stdout = os.Stdout
-	//cmd.Stderr = os.Stderr
-	cmd.Stderr = &stderr
-	out, err := cmd.Output()
-	return out, err, stderr
+	var out bytes.Buffer
+	build := exec.Command("go", args...)
+	build.Stdout, build.Stderr = &out, &out
+	if err := s.runCmd(build, nil); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return compileError(out.String(), s.FilePath, s.srcMap)
+		}
+		return err
+	}
+
+	cmd := exec.Command(bin)
+	cmd.Env = env
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if s.Input == nil || !stdinAvailable {
+		return s.runCmd(cmd, nil)
+	}
+
+	// the standard input is read from the notebook, see Session.Input
+	stdin, err := newStdinPipe()
+	if err != nil {
+		return err
+	}
+	defer stdin.Close()
+	cmd.Stdin = stdin.r
+	return s.runCmd(cmd, stdin)
+}
+
+// runCmd runs cmd such that it can be stopped by Interrupt. If stdin is set, the
+// standard input of cmd, it is fed with the lines returned by s.Input while cmd
+// runs, and closed if s.Input fails.
+func (s *Session) runCmd(cmd *exec.Cmd, stdin *stdinPipe) error {
+	setProcessGroup(cmd)
+
+	s.runMu.Lock()
+	err := cmd.Start()
+	if err != nil {
+		s.runMu.Unlock()
+		return err
+	}
+	s.running = cmd
+	s.interrupted = false
+	s.runMu.Unlock()
+
+	if stdin != nil {
+		stop := make(chan struct{})
+		defer close(stop)
+		input := s.Input
+		go func() {
+			if err := stdin.serve(cmd.Process.Pid, 0, input, stop); err != nil {
+				stdin.w.Close()
+			}
+		}()
+	}
+
+	err = cmd.Wait()
+
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	s.running = nil
+	if s.interrupted {
+		return ErrInterrupt
+	}
+	return err
+}
+
+// Interrupt stops the statements run by a concurrent call to Eval, which then
+// returns ErrInterrupt. It reports whether they were running.
+func (s *Session) Interrupt() bool {
+	return s.Evaluator.Interrupt()
+}
+
+// interruptCmd kills the command run by runCmd, if any, and reports whether one
+// was running.
+func (s *Session) interruptCmd() bool {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	if s.running == nil {
+		return false
+	}
+
+	s.interrupted = true
+	if err := killProcessGroup(s.running); err != nil {
+		errorf("interrupt: %s", err)
+	}
+	return true
+}
+
+// Close closes the evaluator of the session, which stops the statements running,
+// and removes the temporary directory holding the session source and the files
+// loaded by :import. The session must not be used afterwards.
+func (s *Session) Close() error {
+	if err := s.Evaluator.Close(); err != nil {
+		errorf("close: %s", err)
+	}
+	return os.RemoveAll(filepath.Dir(s.FilePath))
 }
 
 func (s *Session) evalExpr(in string) (ast.Expr, error) {
-	expr, err := parser.ParseExpr(in)
+	expr, err := parser.ParseExprFrom(s.Fset, "", in, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -214,6 +470,7 @@ func (s *Session) evalExpr(in string) (ast.Expr, error) {
 	}
 
 	s.appendStatements(stmt)
+	s.origins[stmt] = s.newOrigin(in, s.Fset, expr, 0)
 
 	return expr, nil
 }
@@ -224,7 +481,8 @@ func isNamedIdent(expr ast.Expr, name string) bool {
 }
 
 func (s *Session) evalStmt(in string) error {
-	src := fmt.Sprintf("package P; func F() { %s }", in)
+	const prefix = stmtPrefix
+	src := fmt.Sprintf(prefix+"%s }", in)
 	f, err := parser.ParseFile(s.Fset, "stmt.go", src, parser.Mode(0))
 	if err != nil {
 		return err
@@ -233,6 +491,10 @@ func (s *Session) evalStmt(in string) error {
 	enclosingFunc := f.Scope.Lookup("F").Decl.(*ast.FuncDecl)
 	stmts := enclosingFunc.Body.List
 
+	for _, stmt := range stmts {
+		s.origins[stmt] = s.newOrigin(in, s.Fset, stmt, len(prefix))
+	}
+
 	if len(stmts) > 0 {
 		debugf("evalStmt :: %s", showNode(s.Fset, stmts))
 		lastStmt := stmts[len(stmts)-1]
@@ -252,11 +514,13 @@ func (s *Session) evalStmt(in string) error {
 					},
 				}
 				stmts = append(stmts, printLastValues)
+				s.origins[printLastValues] = s.origins[lastStmt]
 			}
 		}
 	}
 
 	s.appendStatements(stmts...)
+	s.shadowRedeclared(len(s.mainBody.List) - len(stmts))
 
 	return nil
 }
@@ -268,8 +532,9 @@ func (s *Session) appendStatements(stmts ...ast.Stmt) {
 type Error string
 
 const (
-	ErrContinue Error = "<continue input>"
-	ErrQuit     Error = "<quit session>"
+	ErrContinue  Error = "<continue input>"
+	ErrQuit      Error = "<quit session>"
+	ErrInterrupt Error = "<interrupted>"
 )
 
 func (e Error) Error() string {
@@ -307,14 +572,17 @@ func (s *Session) reset() error {
 		return err
 	}
 
+	old := s.File
 	s.File = file
 	s.mainBody = s.mainFunc().Body
+	s.remapOrigins(old, s.File)
 
 	return nil
 }
 
 func (s *Session) Eval(in string) (string, error, bytes.Buffer) {
 	debugf("eval >>> %q", in)
+	s.evals++
 
 	s.clearQuickFix()
 	s.storeMainBody()
@@ -345,17 +613,20 @@ func (s *Session) Eval(in string) (string, error, bytes.Buffer) {
 		return "", nil, bytes.Buffer{}
 	}
 
-	if _, err := s.evalExpr(in); err != nil {
-		debugf("expr :: err = %s", err)
-
-		err := s.evalStmt(in)
-		if err != nil {
-			debugf("stmt :: err = %s", err)
-
-			if _, ok := err.(scanner.ErrorList); ok {
-				return "", ErrContinue, bytes.Buffer{}
-			}
+	tail := s.replaceCell()
+	imports, err := s.evalCode(in)
+	s.appendTail(tail)
+	if err != nil {
+		s.restoreMainBody()
+		if err != ErrContinue {
+			errorf("%s", err)
 		}
+		return "", err, bytes.Buffer{}
+	}
+	if imports {
+		// the cell only imports packages, like :import
+		s.doQuickFix()
+		return "", nil, bytes.Buffer{}
 	}
 
 	if *flagAutoImport {
@@ -365,7 +636,16 @@ func (s *Session) Eval(in string) (string, error, bytes.Buffer) {
 
 	output, err, strerr := s.Run()
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
+		if err == ErrInterrupt {
+			debugf("interrupted, popping out last input")
+			s.restoreMainBody()
+		} else if _, ok := err.(*CompileError); ok {
+			debugf("compilation failed, popping out last input")
+			s.restoreMainBody()
+		} else if _, ok := err.(*PanicError); ok {
+			debugf("panicked, popping out last input")
+			s.restoreMainBody()
+		} else if exitErr, ok := err.(*exec.ExitError); ok {
 			// if failed with status 2, remove the last statement
 			if st, ok := exitErr.ProcessState.Sys().(syscall.WaitStatus); ok {
 				if st.ExitStatus() == 2 {
@@ -380,14 +660,59 @@ func (s *Session) Eval(in string) (string, error, bytes.Buffer) {
 	return string(output), err, strerr
 }
 
+// evalCode adds the code in of a cell to the session source, as an expression,
+// declarations or statements, and reports whether it only imports packages.
+func (s *Session) evalCode(in string) (bool, error) {
+	_, err := s.evalExpr(in)
+	if err == nil {
+		return false, nil
+	}
+	debugf("expr :: err = %s", err)
+
+	decls, err := s.evalDecls(in)
+	switch {
+	case err == errNotDecls:
+		err := s.evalStmt(in)
+		if err != nil {
+			debugf("stmt :: err = %s", err)
+
+			if _, ok := err.(scanner.ErrorList); ok {
+				if decls, stmts, ok := splitDecls(in); ok {
+					return false, s.evalDeclsStmts(in, decls, stmts)
+				}
+				if status, _ := IsComplete(in); status == StatusIncomplete {
+					return false, ErrContinue
+				}
+				return false, s.syntaxError(in)
+			}
+		}
+	case err != nil:
+		return false, err
+	case len(decls) == 0:
+		return true, nil
+	}
+	return false, nil
+}
+
 // storeMainBody stores current state of code so that it can be restored
-// actually it saves the length of statements inside main()
+// actually it saves the length of statements inside main(), and forgets the
+// declarations replaced and renamed by the previous call to Eval
 func (s *Session) storeMainBody() {
 	s.storedBodyLength = len(s.mainBody.List)
+	s.declared, s.replaced = nil, nil
+	s.shadowed = nil
+	s.snapshot = nil
 }
 
 func (s *Session) restoreMainBody() {
-	s.mainBody.List = s.mainBody.List[0:s.storedBodyLength]
+	if s.snapshot != nil {
+		s.restoreSnapshot(s.snapshot)
+		s.snapshot = nil
+	} else {
+		s.mainBody.List = s.mainBody.List[0:s.storedBodyLength]
+		s.restoreDecls()
+	}
+	s.restoreShadowed()
 }
 
 // includeFiles imports packages and funcsions from multiple golang source
@@ -494,11 +819,14 @@ func (s *Session) fixImports() error {
 		return err
 	}
 
+	old := s.File
 	s.File, err = parser.ParseFile(s.Fset, "", formatted, parser.Mode(0))
 	if err != nil {
+		s.File = old
 		return err
 	}
 	s.mainBody = s.mainFunc().Body
+	s.remapOrigins(old, s.File)
 
 	return nil
 }
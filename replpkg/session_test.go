@@ -1,6 +1,8 @@
 package replpkg
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -15,7 +17,7 @@ func TestRun_import(t *testing.T) {
 	}
 
 	for _, code := range codes {
-		err := s.Eval(code)
+		_, err, _ := s.Eval(code)
 		noError(t, err)
 	}
 }
@@ -34,7 +36,7 @@ func TestRun_QuickFix_evaluated_but_not_used(t *testing.T) {
 	}
 
 	for _, code := range codes {
-		err := s.Eval(code)
+		_, err, _ := s.Eval(code)
 		noError(t, err)
 	}
 }
@@ -50,7 +52,7 @@ func TestRun_QuickFix_used_as_value(t *testing.T) {
 	}
 
 	for _, code := range codes {
-		err := s.Eval(code)
+		_, err, _ := s.Eval(code)
 		noError(t, err)
 	}
 }
@@ -67,7 +69,7 @@ func TestRun_FixImports(t *testing.T) {
 	}
 
 	for _, code := range codes {
-		err := s.Eval(code)
+		_, err, _ := s.Eval(code)
 		noError(t, err)
 	}
 }
@@ -79,7 +81,7 @@ func TestIncludePackage(t *testing.T) {
 	err = s.includePackage("github.com/fabian-z/gopherlab/replpkg/gocode")
 	noError(t, err)
 
-	err = s.Eval("Completer{}")
+	_, err, _ = s.Eval("Completer{}")
 	noError(t, err)
 }
 
@@ -97,7 +99,7 @@ func TestRun_Copy(t *testing.T) {
 	}
 
 	for _, code := range codes {
-		err := s.Eval(code)
+		_, err, _ := s.Eval(code)
 		noError(t, err)
 	}
 }
@@ -113,7 +115,55 @@ func TestRun_Const(t *testing.T) {
 	}
 
 	for _, code := range codes {
-		err := s.Eval(code)
+		_, err, _ := s.Eval(code)
 		noError(t, err)
 	}
 }
+
+func TestRun_redeclare(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+
+	for _, cell := range []struct{ code, out string }{
+		{`x := 21`, "21\n"},
+		{`x := x * 2`, "42\n"},
+		{`x := x > 40`, "true\n"},
+	} {
+		out, err, _ := s.Eval(cell.code)
+		noError(t, err)
+		if out != cell.out {
+			t.Errorf("%s: got %q, want %q", cell.code, out, cell.out)
+		}
+	}
+
+	_, err, _ = s.Eval(`x := x + undefinedName`)
+	compileErr, ok := err.(*CompileError)
+	if !ok {
+		t.Fatalf("should be a compile error: %#v", err)
+	}
+	if cellErr := compileErr.Errors[0]; cellErr.Line != 1 || cellErr.Col != 10 {
+		t.Errorf("the error should be located in the cell: %+v", cellErr)
+	}
+
+	out, err, _ := s.Eval(`!x`)
+	noError(t, err)
+	if out != "false\n" {
+		t.Errorf("x should be the bool declared last: %q", out)
+	}
+}
+
+func TestSession_Close(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	_, err, _ = s.Eval(`1`)
+	noError(t, err)
+
+	dir := filepath.Dir(s.FilePath)
+	noError(t, s.Close())
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("%s should have been removed: %v", dir, err)
+	}
+}
@@ -0,0 +1,199 @@
+package replpkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// shadowing is a variable, constant or type of main renamed by shadowRedeclared.
+type shadowing struct {
+	name, renamed string
+}
+
+// shadowRedeclared renames the variables, constants and types of main which the
+// statements of main from index first on declare again, and their uses before, so
+// that the statements declare new ones shadowing them, of other types if need be.
+// A short variable declaration declaring new variables as well only renames those
+// it declares again if it would not compile otherwise, as it assigns to them.
+func (s *Session) shadowRedeclared(first int) {
+	earlier := make(map[string]bool)
+	for _, stmt := range s.mainBody.List[:first] {
+		for _, ident := range declaredIdents(stmt) {
+			earlier[ident.Name] = true
+		}
+	}
+
+	for i := first; i < len(s.mainBody.List); i++ {
+		stmt := s.mainBody.List[i]
+		idents := declaredIdents(stmt)
+
+		again := false
+		for _, ident := range idents {
+			again = again || earlier[ident.Name]
+			earlier[ident.Name] = true
+		}
+		if again {
+			s.shadow(i)
+		}
+	}
+}
+
+// shadow renames what the statement of main at index i declares again, see
+// shadowRedeclared.
+func (s *Session) shadow(i int) {
+	stmt := s.mainBody.List[i]
+
+	body := s.mainBody.List
+	s.mainBody.List = body[:i+1]
+	info := types.Info{
+		Defs:   make(map[*ast.Ident]types.Object),
+		Uses:   make(map[*ast.Ident]types.Object),
+		Scopes: make(map[ast.Node]*types.Scope),
+	}
+	failed := false
+	config := types.Config{
+		Importer: s.Types.Importer,
+		Error: func(err error) {
+			if err, ok := err.(types.Error); ok && !err.Soft && stmt.Pos() <= err.Pos && err.Pos < stmt.End() {
+				failed = true
+			}
+		},
+	}
+	config.Check("main", s.Fset, append(s.ExtraFiles[:len(s.ExtraFiles):len(s.ExtraFiles)], s.File), &info)
+	s.mainBody.List = body
+
+	scope := info.Scopes[s.mainFunc().Type]
+	if scope == nil {
+		return
+	}
+
+	declaring := make(map[*ast.Ident]bool)
+	idents := declaredIdents(stmt)
+	var again []*ast.Ident
+	for _, ident := range idents {
+		declaring[ident] = true
+		if obj := scope.Lookup(ident.Name); obj != nil && info.Defs[ident] != obj {
+			again = append(again, ident)
+		}
+	}
+	if _, ok := stmt.(*ast.AssignStmt); ok && len(again) < len(idents) && !failed {
+		debugf("shadow :: %s assigns to the variables it declares again", showNode(s.Fset, stmt))
+		return
+	}
+
+	// what the statements added by this call to Eval declare is not known to the
+	// evaluator yet, it is declared renamed
+	current := make(map[*ast.Ident]bool)
+	for _, stmt := range s.mainBody.List {
+		if o := s.origins[stmt]; o != nil && o.eval == s.evals {
+			ast.Inspect(stmt, func(node ast.Node) bool {
+				if ident, ok := node.(*ast.Ident); ok {
+					current[ident] = true
+				}
+				return true
+			})
+		}
+	}
+	isCurrent := make(map[types.Object]bool)
+	for ident, obj := range info.Defs {
+		if current[ident] {
+			isCurrent[obj] = true
+		}
+	}
+
+	redeclared := make(map[types.Object]string)
+	for _, ident := range again {
+		obj := scope.Lookup(ident.Name)
+		s.shadows++
+		renamed := fmt.Sprintf("__gore_%s_%d", ident.Name, s.shadows)
+		redeclared[obj] = renamed
+		if isCurrent[obj] {
+			continue
+		}
+		s.shadowed = append(s.shadowed, shadowing{ident.Name, renamed})
+		if _, ok := obj.(*types.Var); ok {
+			s.renamed[renamed] = ident.Name
+		}
+	}
+
+	for _, uses := range []map[*ast.Ident]types.Object{info.Defs, info.Uses} {
+		for ident, obj := range uses {
+			if renamed, ok := redeclared[obj]; ok && !declaring[ident] {
+				ident.Name = renamed
+			}
+		}
+	}
+}
+
+// restoreShadowed undoes the renaming of the last call to Eval.
+func (s *Session) restoreShadowed() {
+	for i := len(s.shadowed) - 1; i >= 0; i-- {
+		sh := s.shadowed[i]
+		ast.Inspect(s.mainBody, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok && ident.Name == sh.renamed {
+				ident.Name = sh.name
+			}
+			return true
+		})
+		delete(s.renamed, sh.renamed)
+	}
+	s.shadowed = nil
+}
+
+// shadowedName returns the name of what shadowRedeclared has renamed to renamed,
+// or false if renamed is no such name.
+func shadowedName(renamed string) (string, bool) {
+	const prefix = "__gore_"
+	i := strings.LastIndexByte(renamed, '_')
+	if !strings.HasPrefix(renamed, prefix) || i <= len(prefix) {
+		return "", false
+	}
+	if _, err := strconv.Atoi(renamed[i+1:]); err != nil {
+		return "", false
+	}
+	return renamed[len(prefix):i], true
+}
+
+// varKey returns the name the variable of main name has been declared with, which
+// is its name unless it has been renamed since.
+func (s *Session) varKey(name string) string {
+	if key, ok := s.renamed[name]; ok {
+		return key
+	}
+	return name
+}
+
+// declaredIdents returns the identifiers stmt, a statement of main, declares in
+// the scope of main.
+func declaredIdents(stmt ast.Stmt) []*ast.Ident {
+	var idents []*ast.Ident
+	switch stmt := stmt.(type) {
+	case *ast.AssignStmt:
+		if stmt.Tok == token.DEFINE {
+			for _, lhs := range stmt.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name != "_" {
+					idents = append(idents, ident)
+				}
+			}
+		}
+	case *ast.DeclStmt:
+		for _, spec := range stmt.Decl.(*ast.GenDecl).Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				idents = append(idents, spec.Name)
+			case *ast.ValueSpec:
+				for _, ident := range spec.Names {
+					if ident.Name != "_" {
+						idents = append(idents, ident)
+					}
+				}
+			}
+		}
+	}
+	return idents
+}
@@ -0,0 +1,420 @@
+package replpkg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+)
+
+// origin is the code of a cell a statement of main or a declaration at package
+// scope of the session source comes from.
+type origin struct {
+	cell int
+	id   string // the ID of the cell, if known
+	eval int    // the call to Eval which added the statement
+	src  string // the code of the cell
+	// pos and end are the byte offsets of the statement or declaration in src.
+	pos, end int
+}
+
+// newOrigin returns the origin of node, which is parsed from the code in of the
+// current cell. base is the offset of in in the file node has been parsed from.
+func (s *Session) newOrigin(in string, fset *token.FileSet, node ast.Node, base int) *origin {
+	return &origin{
+		cell: s.Cell,
+		id:   s.CellID,
+		eval: s.evals,
+		src:  in,
+		pos:  fset.Position(node.Pos()).Offset - base,
+		end:  fset.Position(node.End()).Offset - base,
+	}
+}
+
+// remapOrigins carries the origins of the session source old over to the source
+// new which replaces it, e.g. after it has been parsed again: the statements of
+// main are replaced one by one, and the declarations at package scope by those
+// declaring the same names. The origins of other nodes are forgotten.
+func (s *Session) remapOrigins(old, new *ast.File) {
+	origins := make(map[ast.Node]*origin)
+
+	oldStmts := old.Scope.Lookup("main").Decl.(*ast.FuncDecl).Body.List
+	newStmts := new.Scope.Lookup("main").Decl.(*ast.FuncDecl).Body.List
+	for i := 0; i < len(oldStmts) && i < len(newStmts); i++ {
+		if o := s.origins[oldStmts[i]]; o != nil {
+			origins[newStmts[i]] = o
+		}
+	}
+
+	declOrigins := s.declOrigins(old)
+	for _, decl := range new.Decls {
+		if o := declOrigins[declKey(decl)]; o != nil {
+			origins[decl] = o
+		}
+	}
+
+	s.origins = origins
+}
+
+// declOrigins returns the origins of the declarations at package scope of f, by
+// their keys.
+func (s *Session) declOrigins(f *ast.File) map[string]*origin {
+	origins := make(map[string]*origin)
+	for _, decl := range f.Decls {
+		if o := s.origins[decl]; o != nil {
+			origins[declKey(decl)] = o
+		}
+	}
+	return origins
+}
+
+// declKey identifies decl, a declaration at package scope, by the names it
+// declares.
+func declKey(decl ast.Decl) string {
+	return strings.Join(declNames(decl), " ")
+}
+
+// sourceMap maps the statements of main and the declarations at package scope in
+// the program file to the cells they come from.
+type sourceMap struct {
+	src   string
+	stmts []stmtSpan
+}
+
+// stmtSpan is the byte range of a statement of main or a declaration in the
+// program file, and its origin.
+type stmtSpan struct {
+	pos, end int
+	origin   *origin
+}
+
+// newSourceMap returns the source map of the program file src, where the body of
+// the function fn ends with the statements stmts of the session source. Statements
+// generated for the program are nil in stmts. The declarations of src are mapped
+// to those of the session source declaring the same names.
+func (s *Session) newSourceMap(src []byte, fn string, stmts []ast.Stmt) *sourceMap {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil
+	}
+
+	obj := f.Scope.Lookup(fn)
+	if obj == nil {
+		return nil
+	}
+	body := obj.Decl.(*ast.FuncDecl).Body.List
+	if len(body) < len(stmts) {
+		return nil
+	}
+	body = body[len(body)-len(stmts):]
+
+	m := &sourceMap{src: string(src), stmts: make([]stmtSpan, len(body))}
+	for i, stmt := range body {
+		m.stmts[i] = stmtSpan{
+			pos: fset.Position(stmt.Pos()).Offset,
+			end: fset.Position(stmt.End()).Offset,
+		}
+		if stmts[i] != nil {
+			m.stmts[i].origin = s.origins[stmts[i]]
+		}
+	}
+
+	declOrigins := s.declOrigins(s.File)
+	for _, decl := range f.Decls {
+		if o := declOrigins[declKey(decl)]; o != nil {
+			m.stmts = append(m.stmts, stmtSpan{
+				pos:    fset.Position(decl.Pos()).Offset,
+				end:    fset.Position(decl.End()).Offset,
+				origin: o,
+			})
+		}
+	}
+	return m
+}
+
+// splitMain puts the statements of main in the source src on lines of their own,
+// so that the lines in traces of the stack identify the statements.
+func splitMain(src string) string {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return src
+	}
+
+	obj := f.Scope.Lookup("main")
+	if obj == nil {
+		return src
+	}
+	body := obj.Decl.(*ast.FuncDecl).Body
+
+	var buf bytes.Buffer
+	last := 0
+	for _, pos := range append(stmtPositions(body.List), body.Rbrace) {
+		offset := fset.Position(pos).Offset
+		buf.WriteString(src[last:offset])
+		buf.WriteByte('\n')
+		last = offset
+	}
+	buf.WriteString(src[last:])
+
+	return buf.String()
+}
+
+// stmtPositions returns the positions of stmts.
+func stmtPositions(stmts []ast.Stmt) []token.Pos {
+	positions := make([]token.Pos, len(stmts))
+	for i, stmt := range stmts {
+		positions[i] = stmt.Pos()
+	}
+	return positions
+}
+
+// locate returns the position in its cell of line and col of the program file,
+// or false if they are not in a statement entered in a cell. The statement may be
+// formatted differently in the program; the position is mapped to the same token
+// of the cell, or to the start of the statement if the tokens do not match.
+func (m *sourceMap) locate(line, col int) (CellPos, bool) {
+	if m == nil {
+		return CellPos{}, false
+	}
+
+	offset, ok := lineColOffset(m.src, line, col)
+	if !ok {
+		return CellPos{}, false
+	}
+	return m.locateOffset(offset)
+}
+
+// locateLine is like locate, for the first token on line.
+func (m *sourceMap) locateLine(line int) (CellPos, bool) {
+	if m == nil {
+		return CellPos{}, false
+	}
+
+	offset, ok := lineColOffset(m.src, line, 1)
+	if !ok {
+		return CellPos{}, false
+	}
+	for offset < len(m.src) && (m.src[offset] == ' ' || m.src[offset] == '\t') {
+		offset++
+	}
+	return m.locateOffset(offset)
+}
+
+func (m *sourceMap) locateOffset(offset int) (CellPos, bool) {
+	for _, stmt := range m.stmts {
+		if offset < stmt.pos || offset >= stmt.end || stmt.origin == nil {
+			continue
+		}
+
+		o := stmt.origin
+		cellOffset := o.pos
+		gen, cell := tokenOffsets(m.src[stmt.pos:stmt.end]), tokenOffsets(o.src[o.pos:o.end])
+		if k := tokensIndex(gen, cell); k >= 0 {
+			for i := len(cell) - 1; i >= 0; i-- {
+				if at := stmt.pos + gen[k+i].offset; at <= offset {
+					delta := offset - at
+					if delta >= len(cell[i].text) {
+						delta = 0
+					}
+					cellOffset = o.pos + cell[i].offset + delta
+					break
+				}
+			}
+		}
+
+		cellLine, cellCol, source := offsetLineCol(o.src, cellOffset)
+		return CellPos{Cell: o.cell, Line: cellLine, Col: cellCol, Source: source}, true
+	}
+
+	return CellPos{}, false
+}
+
+// srcToken is a token of a statement, at a byte offset in the statement.
+type srcToken struct {
+	offset int
+	tok    token.Token
+	text   string
+}
+
+// tokenOffsets returns the tokens of src, leaving out automatic semicolons.
+func tokenOffsets(src string) []srcToken {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", -1, len(src))
+
+	var sc scanner.Scanner
+	sc.Init(file, []byte(src), nil, 0)
+
+	var toks []srcToken
+	for {
+		pos, tok, lit := sc.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		if lit == "" {
+			lit = tok.String()
+		}
+		toks = append(toks, srcToken{fset.Position(pos).Offset, tok, lit})
+	}
+	return toks
+}
+
+// tokensIndex returns the index at which the tokens sub occur in toks, or -1.
+// Identifiers of toks renamed by shadowRedeclared match their former names.
+func tokensIndex(toks, sub []srcToken) int {
+	if len(sub) == 0 {
+		return -1
+	}
+
+outer:
+	for k := 0; k+len(sub) <= len(toks); k++ {
+		for i := range sub {
+			if toks[k+i].tok != sub[i].tok || toks[k+i].text != sub[i].text && !isShadowed(toks[k+i].text, sub[i].text) {
+				continue outer
+			}
+		}
+		return k
+	}
+	return -1
+}
+
+// isShadowed reports whether the identifier renamed is name renamed by
+// shadowRedeclared.
+func isShadowed(renamed, name string) bool {
+	shadowed, ok := shadowedName(renamed)
+	return ok && shadowed == name
+}
+
+// lineColOffset returns the byte offset of line and col, both starting at 1, in src.
+func lineColOffset(src string, line, col int) (int, bool) {
+	offset := 0
+	for l := 1; l < line; l++ {
+		i := strings.IndexByte(src[offset:], '\n')
+		if i < 0 {
+			return 0, false
+		}
+		offset += i + 1
+	}
+
+	offset += col - 1
+	if offset > len(src) {
+		return 0, false
+	}
+	return offset, true
+}
+
+// offsetLineCol returns the line and column of the byte offset in src, and the
+// line itself.
+func offsetLineCol(src string, offset int) (line, col int, text string) {
+	if offset > len(src) {
+		offset = len(src)
+	}
+
+	start := strings.LastIndexByte(src[:offset], '\n') + 1
+	end := strings.IndexByte(src[start:], '\n')
+	if end < 0 {
+		end = len(src)
+	} else {
+		end += start
+	}
+
+	return strings.Count(src[:offset], "\n") + 1, offset - start + 1, src[start:end]
+}
+
+// CompileError is returned by Eval if the program of the session does not
+// compile.
+type CompileError struct {
+	Errors []CellError
+}
+
+func (e *CompileError) Error() string {
+	if len(e.Errors) == 0 {
+		return "compilation failed"
+	}
+
+	msg := e.Errors[0].String()
+	if len(e.Errors) > 1 {
+		msg += fmt.Sprintf(" (and %d more errors)", len(e.Errors)-1)
+	}
+	return msg
+}
+
+// CellPos is a position in the code of a cell.
+type CellPos struct {
+	Cell      int
+	Line, Col int
+	// Source is the line of the cell.
+	Source string
+}
+
+// CellError is an error reported by the compiler. Errors in statements entered in
+// a cell refer to the line and column in the cell; Line is 0 for other errors.
+type CellError struct {
+	CellPos
+	Msg string
+}
+
+// String returns the error as "Cell [n], line x:col: msg".
+func (e CellError) String() string {
+	switch {
+	case e.Line == 0:
+		return e.Msg
+	case e.Cell == 0:
+		return fmt.Sprintf("line %d:%d: %s", e.Line, e.Col, e.Msg)
+	}
+	return fmt.Sprintf("Cell [%d], line %d:%d: %s", e.Cell, e.Line, e.Col, e.Msg)
+}
+
+// compilerErrorPattern matches the file, line, column and message of the errors
+// reported by the compiler.
+var compilerErrorPattern = regexp.MustCompile(`^(.+\.go):(\d+):(\d+): (.*)$`)
+
+// compileError returns the error for the compiler output out, locating the errors
+// in the program file programPath in the cells according to srcMap.
+func compileError(out string, programPath string, srcMap *sourceMap) *CompileError {
+	var err CompileError
+
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		text := sc.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		m := compilerErrorPattern.FindStringSubmatch(text)
+		if m == nil {
+			if len(err.Errors) > 0 && strings.HasPrefix(text, "\t") {
+				// continuation of the previous error
+				last := &err.Errors[len(err.Errors)-1]
+				last.Msg += "\n" + text
+			} else {
+				err.Errors = append(err.Errors, CellError{Msg: text})
+			}
+			continue
+		}
+
+		if filepath.Base(m[1]) != filepath.Base(programPath) {
+			err.Errors = append(err.Errors, CellError{Msg: text})
+			continue
+		}
+
+		line, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		pos, _ := srcMap.locate(line, col)
+		err.Errors = append(err.Errors, CellError{CellPos: pos, Msg: m[4]})
+	}
+
+	return &err
+}
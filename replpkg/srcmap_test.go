@@ -0,0 +1,61 @@
+package replpkg
+
+import (
+	"testing"
+)
+
+func TestRun_CompileError(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+
+	s.Cell = 3
+	_, err, _ = s.Eval("1 +\n\t2*undefinedName")
+
+	compileErr, ok := err.(*CompileError)
+	if !ok {
+		t.Fatalf("should be a compile error: %#v", err)
+	}
+
+	cellErr := compileErr.Errors[0]
+	if cellErr.Cell != 3 || cellErr.Line != 2 || cellErr.Col != 4 || cellErr.Source != "\t2*undefinedName" {
+		t.Errorf("the error should be located in the cell: %+v", cellErr)
+	}
+	if got := cellErr.String(); got != "Cell [3], line 2:4: undefined: undefinedName" {
+		t.Errorf("got %q", got)
+	}
+
+	out, err, _ := s.Eval(`1 + 1`)
+	noError(t, err)
+	if out != "2\n" {
+		t.Errorf("the failed cell should have been dropped: %q", out)
+	}
+}
+
+func TestSourceMap_locate(t *testing.T) {
+	const cell = "a := 1\nb := a+\n  undefinedName"
+
+	m := &sourceMap{
+		src: "func main() {\n\ta := 1\n\tb := a + undefinedName\n}\n",
+		stmts: []stmtSpan{
+			{15, 21, &origin{cell: 7, src: cell, pos: 0, end: 6}},
+			{23, 45, &origin{cell: 7, src: cell, pos: 7, end: len(cell)}},
+		},
+	}
+
+	pos, ok := m.locate(3, 11)
+	if !ok {
+		t.Fatal("the error should be located")
+	}
+	if pos.Cell != 7 || pos.Line != 3 || pos.Col != 3 || pos.Source != "  undefinedName" {
+		t.Errorf("got %+v", pos)
+	}
+
+	pos, ok = m.locateLine(3)
+	if !ok || pos.Line != 2 || pos.Col != 1 {
+		t.Errorf("the line should be located at the start of the statement: %+v", pos)
+	}
+
+	if _, ok := m.locate(1, 1); ok {
+		t.Errorf("errors outside of statements should not be located")
+	}
+}
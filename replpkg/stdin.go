@@ -0,0 +1,59 @@
+package replpkg
+
+import (
+	"os"
+	"time"
+)
+
+// stdinPollInterval is how often a program is checked for waiting for input.
+const stdinPollInterval = 50 * time.Millisecond
+
+// stdinPipe is the standard input of a program, which the lines returned by
+// Session.Input are written to whenever the program waits for input on it.
+type stdinPipe struct {
+	r, w *os.File
+}
+
+// newStdinPipe returns a new standard input. Its read end is in blocking mode, so
+// that a program reading it waits in the read system call, see waitsReading.
+func newStdinPipe() (*stdinPipe, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	r.Fd()
+	return &stdinPipe{r: r, w: w}, nil
+}
+
+// serve writes a line returned by input to the pipe whenever it is empty and a
+// thread of the process pid waits reading it as its file descriptor fd, until stop
+// is closed. It returns the error of input, if any.
+func (p *stdinPipe) serve(pid int, fd uintptr, input func(prompt string, password bool) (string, error), stop <-chan struct{}) error {
+	ticker := time.NewTicker(stdinPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+
+		if !pipeEmpty(p.w) || !waitsReading(pid, fd) {
+			continue
+		}
+		line, err := input("", false)
+		if err != nil {
+			return err
+		}
+		if _, err := p.w.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+}
+
+// Close closes both ends of the pipe.
+func (p *stdinPipe) Close() error {
+	p.w.Close()
+	return p.r.Close()
+}
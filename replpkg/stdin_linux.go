@@ -0,0 +1,57 @@
+package replpkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// stdinAvailable tells whether programs can read their standard input from the
+// notebook, which needs to find them waiting for it.
+const stdinAvailable = true
+
+// waitsReading reports whether a thread of the process pid is blocked reading its
+// file descriptor fd, as shown by /proc.
+func waitsReading(pid int, fd uintptr) bool {
+	files, _ := filepath.Glob(fmt.Sprintf("/proc/%d/task/*/syscall", pid))
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		// the number of the system call and its arguments, or "running"
+		fields := strings.Fields(string(b))
+		if len(fields) < 2 {
+			continue
+		}
+		nr, err := strconv.Atoi(fields[0])
+		if err != nil || nr != syscall.SYS_READ {
+			continue
+		}
+		if arg, err := strconv.ParseUint(fields[1], 0, 64); err == nil && arg == uint64(fd) {
+			return true
+		}
+	}
+	return false
+}
+
+// pipeEmpty reports whether all bytes written to the pipe f have been read.
+func pipeEmpty(f *os.File) bool {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return false
+	}
+
+	var n int32
+	var errno syscall.Errno
+	err = conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCINQ, uintptr(unsafe.Pointer(&n)))
+	})
+	return err == nil && errno == 0 && n == 0
+}
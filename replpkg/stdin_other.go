@@ -0,0 +1,19 @@
+// +build !linux
+
+package replpkg
+
+import (
+	"os"
+)
+
+// stdinAvailable tells whether programs can read their standard input from the
+// notebook, which needs to find them waiting for it.
+const stdinAvailable = false
+
+func waitsReading(pid int, fd uintptr) bool {
+	return false
+}
+
+func pipeEmpty(f *os.File) bool {
+	return false
+}
@@ -0,0 +1,439 @@
+package replpkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fabian-z/gopherlab/ipc"
+)
+
+// workerSource is the program of the worker process.
+const workerSource = `package main
+
+import "` + workerPkgPath + `"
+
+func main() {
+	worker.Serve()
+}
+`
+
+// errWorkerExited is returned for cells during which the worker process exited
+// without a report of a panic.
+var errWorkerExited = errors.New("the worker process exited")
+
+// worker is the evaluator running every cell once, in a persistent process which
+// keeps the variables; see package github.com/fabian-z/gopherlab/worker.
+type worker struct {
+	s       *Session
+	dir     string
+	plugins []workerPlugin // the plugins of the cells run so far, in order
+	built   int            // the number of plugins built, which name them
+
+	srv    *ipc.Server
+	cmd    *exec.Cmd
+	conn   *ipc.Conn
+	errOut *panicWriter // the standard error of the process, holding back the report of a panic crashing it
+	stdin  *stdinPipe   // the standard input of the process, if it can be read from the notebook
+	exited chan struct{}
+
+	ready   chan *ipc.Conn
+	results chan ipc.RunResult
+
+	mu             sync.Mutex // guards stdout and stderr
+	stdout, stderr io.Writer  // receive the output of the cell running
+}
+
+// workerPlugin is a plugin run by the worker process, the origins of the
+// statements and declarations of the cell it has been built for, and the source
+// file of the plugin with its source map.
+type workerPlugin struct {
+	path    string
+	origins []*origin
+	src     string
+	srcMap  *sourceMap
+}
+
+// workerOutput passes the output of the worker process on to the writers of the
+// cell running.
+type workerOutput struct {
+	w      *worker
+	stderr bool
+}
+
+func (o workerOutput) Write(p []byte) (int, error) {
+	o.w.mu.Lock()
+	dst := o.w.stdout
+	if o.stderr {
+		dst = o.w.stderr
+	}
+	o.w.mu.Unlock()
+
+	if dst == nil {
+		return len(p), nil
+	}
+	return dst.Write(p)
+}
+
+// setOutput directs the output of the cells run from now on to stdout and stderr.
+func (w *worker) setOutput(stdout, stderr io.Writer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stdout, w.stderr = stdout, stderr
+}
+
+func newWorker(s *Session) *worker {
+	return &worker{s: s, dir: filepath.Dir(s.FilePath)}
+}
+
+// running reports whether the worker process is running.
+func (w *worker) running() bool {
+	if w.cmd == nil {
+		return false
+	}
+
+	select {
+	case <-w.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+// Run runs the statements added by the last call to Eval in the worker process,
+// which is started first if needed.
+func (w *worker) Run() ([]byte, error, bytes.Buffer) {
+	s := w.s
+	var stdout, stderr bytes.Buffer
+	outw, errw := s.outputWriters(&stdout, &stderr)
+
+	if !w.running() {
+		if n := len(w.heldPlugins()); n > 0 {
+			fmt.Fprintf(errw, "note: restarting the worker process, running %d earlier cells again\n", n)
+		}
+		if err := w.start(); err != nil {
+			return nil, err, stderr
+		}
+	}
+
+	src, stmts, err := s.cellPlugin()
+	if err != nil {
+		return nil, err, stderr
+	}
+
+	// plugins are loaded once per path, and the header makes the package path of
+	// every plugin, derived from its source, a different one
+	w.built++
+	name := fmt.Sprintf("gore_cell_%d", w.built)
+	src = append([]byte(fmt.Sprintf("// Code generated by gopherlab for %s. DO NOT EDIT.\n\n", name)), src...)
+	file := filepath.Join(w.dir, name+".go")
+	if err := ioutil.WriteFile(file, src, 0644); err != nil {
+		return nil, err, stderr
+	}
+	s.srcMap, s.srcPath = s.newSourceMap(src, cellFuncName, stmts), file
+
+	plugin := filepath.Join(w.dir, name+".so")
+	args := append([]string{"build", "-buildmode=plugin", "-o", plugin, file}, s.ExtraFilePaths...)
+	debugf("go %s", strings.Join(args, " "))
+	var out bytes.Buffer
+	build := exec.Command("go", args...)
+	build.Stdout, build.Stderr = &out, &out
+	if err := s.runCmd(build, nil); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return nil, compileError(out.String(), file, s.srcMap), stderr
+		}
+		return nil, err, stderr
+	}
+
+	s.resetValues()
+	w.setOutput(outw, errw)
+	result, err := w.runPlugin(plugin)
+	w.setOutput(nil, nil)
+
+	switch {
+	case err == errWorkerExited:
+		if panicErr := parsePanic(w.errOut.heldOutput(), file, s.srcMap); panicErr != nil {
+			renamePluginFrames(panicErr.Frames)
+			err = panicErr
+		} else {
+			w.errOut.flush()
+		}
+	case err != nil:
+	case result.Panic != nil:
+		frames := parseStack(result.Panic.Stack, file, s.srcMap)
+		renamePluginFrames(frames)
+		err = &PanicError{Value: result.Panic.Value, Type: result.Panic.Type, Frames: frames}
+	case result.Error != "":
+		err = errors.New(result.Error)
+	default:
+		var origins []*origin
+		for _, o := range s.origins {
+			if o.eval == s.evals {
+				origins = append(origins, o)
+			}
+		}
+		w.plugins = append(w.heldPlugins(), workerPlugin{plugin, origins, file, s.srcMap})
+	}
+
+	return append(stdout.Bytes(), s.values.Bytes()...), err, stderr
+}
+
+// renamePluginFrames names the functions of the plugins in frames, like
+// "plugin/unnamed-4a5b….GoreCell.func1", as those of the programs run otherwise,
+// like "main.main.func1".
+func renamePluginFrames(frames []Frame) {
+	for i, frame := range frames {
+		if !strings.HasPrefix(frame.Func, "plugin/unnamed-") {
+			continue
+		}
+		fn := frame.Func[strings.IndexByte(frame.Func, '.')+1:]
+		if fn == cellFuncName || strings.HasPrefix(fn, cellFuncName+".") {
+			fn = "main" + fn[len(cellFuncName):]
+		}
+		frames[i].Func = "main." + fn
+	}
+}
+
+// heldPlugins returns the plugins of the cells whose statements or declarations the
+// session still holds, leaving out those replaced or dropped since they have been
+// run, in the order of their statements in the session.
+func (w *worker) heldPlugins() []workerPlugin {
+	s := w.s
+
+	holds := make(map[*origin]bool)
+	for _, o := range s.origins {
+		holds[o] = true
+	}
+	index := make(map[*origin]int)
+	for i, stmt := range s.mainBody.List {
+		if o := s.origins[stmt]; o != nil {
+			if _, ok := index[o]; !ok {
+				index[o] = i
+			}
+		}
+	}
+
+	type heldPlugin struct {
+		workerPlugin
+		first int // the index of its first statement in main, or -1
+	}
+	var held []heldPlugin
+	for _, p := range w.plugins {
+		first, isHeld := -1, false
+		for _, o := range p.origins {
+			if !holds[o] {
+				continue
+			}
+			isHeld = true
+			if i, ok := index[o]; ok && (first < 0 || i < first) {
+				first = i
+			}
+		}
+		if isHeld {
+			held = append(held, heldPlugin{p, first})
+		}
+	}
+
+	sort.SliceStable(held, func(i, j int) bool {
+		return held[i].first < held[j].first
+	})
+	plugins := make([]workerPlugin, len(held))
+	for i, h := range held {
+		plugins[i] = h.workerPlugin
+	}
+	return plugins
+}
+
+// start builds the program of the worker process unless it has been built already,
+// and starts it. The plugins of the cells the session holds are run again, their
+// output discarded. A fallbackError is returned if the process cannot be started,
+// or the earlier cells fail to run again.
+func (w *worker) start() error {
+	s := w.s
+
+	bin := filepath.Join(w.dir, "gore_worker")
+	if _, err := os.Stat(bin); err != nil {
+		file := filepath.Join(w.dir, "gore_worker.go")
+		if err := ioutil.WriteFile(file, []byte(workerSource), 0644); err != nil {
+			return err
+		}
+
+		var out bytes.Buffer
+		build := exec.Command("go", "build", "-o", bin, file)
+		build.Stdout, build.Stderr = &out, &out
+		if err := s.runCmd(build, nil); err != nil {
+			if _, ok := err.(*exec.ExitError); ok {
+				return fallbackError(fmt.Sprintf("cannot build the worker process: %s", strings.TrimSpace(out.String())))
+			}
+			return err
+		}
+	}
+
+	if w.srv == nil {
+		sock := filepath.Join(w.dir, "worker.sock")
+		os.Remove(sock)
+		srv, err := ipc.Listen(sock, w.handleIPC)
+		if err != nil {
+			return err
+		}
+		w.srv = srv
+	}
+
+	w.ready = make(chan *ipc.Conn, 1)
+	w.results = make(chan ipc.RunResult, 1)
+	w.exited = make(chan struct{})
+	w.errOut = &panicWriter{w: workerOutput{w, true}}
+
+	cmd := exec.Command(bin)
+	cmd.Env = append(os.Environ(), ipc.EnvVar+"="+w.srv.Addr())
+	cmd.Stdout = workerOutput{w, false}
+	cmd.Stderr = w.errOut
+	if w.stdin != nil {
+		w.stdin.Close()
+		w.stdin = nil
+	}
+	if stdinAvailable {
+		stdin, err := newStdinPipe()
+		if err != nil {
+			return err
+		}
+		cmd.Stdin, w.stdin = stdin.r, stdin
+	}
+	setProcessGroup(cmd)
+	err := cmd.Start()
+	if w.stdin != nil {
+		w.stdin.r.Close()
+	}
+	if err != nil {
+		return fallbackError(fmt.Sprintf("cannot start the worker process: %s", err))
+	}
+	w.cmd = cmd
+
+	exited := w.exited
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	select {
+	case w.conn = <-w.ready:
+	case <-exited:
+		return fallbackError("the worker process exited on start")
+	}
+
+	w.setOutput(nil, nil)
+	for _, p := range w.heldPlugins() {
+		s.srcMap, s.srcPath = p.srcMap, p.src
+		if _, err := w.runPlugin(p.path); err != nil {
+			return fallbackError(fmt.Sprintf("running %s again: %s", filepath.Base(p.path), err))
+		}
+	}
+
+	return nil
+}
+
+// runPlugin runs the plugin in the worker process, which can be interrupted with
+// Interrupt. The standard input of the process is fed with the lines returned by
+// s.Input meanwhile; it stays open if s.Input fails or is nil, so that a cell
+// reading it waits until it is interrupted.
+func (w *worker) runPlugin(plugin string) (ipc.RunResult, error) {
+	s := w.s
+
+	s.runMu.Lock()
+	s.running = w.cmd
+	s.interrupted = false
+	s.runMu.Unlock()
+
+	if w.stdin != nil && s.Input != nil {
+		stop := make(chan struct{})
+		defer close(stop)
+		go w.stdin.serve(w.cmd.Process.Pid, 0, s.Input, stop)
+	}
+
+	var result ipc.RunResult
+	err := w.conn.Send("run", ipc.RunRequest{Plugin: plugin})
+	if err == nil {
+		select {
+		case result = <-w.results:
+		case <-w.exited:
+			err = errWorkerExited
+		}
+	}
+
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	s.running = nil
+	if s.interrupted || result.Interrupted {
+		return result, ErrInterrupt
+	}
+	return result, err
+}
+
+// handleIPC responds to the messages of the worker process about the cells it runs,
+// and passes the others on to the session.
+func (w *worker) handleIPC(conn *ipc.Conn, msg ipc.Message) {
+	switch msg.Type {
+	case "worker_ready":
+		w.ready <- conn
+	case "run_result":
+		var result ipc.RunResult
+		if err := msg.Decode(&result); err != nil {
+			result.Error = err.Error()
+		}
+		w.results <- result
+	case "stream":
+		var stream ipc.StreamMessage
+		if err := msg.Decode(&stream); err != nil {
+			errorf("ipc: %s", err)
+			return
+		}
+		workerOutput{w, stream.Name == "stderr"}.Write([]byte(stream.Text))
+	default:
+		w.s.handleIPC(conn, msg)
+	}
+}
+
+// Interrupt sends SIGINT to the worker process, which then stops waiting for the
+// cell running but keeps the variables; it is restarted by the next cell only if
+// it exits.
+func (w *worker) Interrupt() bool {
+	s := w.s
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	if s.running == nil {
+		return false
+	}
+
+	s.interrupted = true
+	if err := interruptProcess(s.running); err != nil {
+		errorf("interrupt: %s", err)
+	}
+	return true
+}
+
+// Close kills the worker process, if it runs, and stops serving it.
+func (w *worker) Close() error {
+	if w.running() {
+		if err := killProcessGroup(w.cmd); err != nil {
+			errorf("worker: %s", err)
+		}
+		<-w.exited
+	}
+	if w.stdin != nil {
+		w.stdin.Close()
+	}
+	if w.srv != nil {
+		return w.srv.Close()
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package replpkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRun_worker(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+	s.Evaluator, err = s.NewEvaluator("worker")
+	noError(t, err)
+
+	codes := []string{
+		":import time",
+		"t := time.Now().UnixNano()",
+	}
+	var first string
+	for _, code := range codes {
+		first, err, _ = s.Eval(code)
+		noError(t, err)
+	}
+	if _, ok := s.Evaluator.(*worker); !ok {
+		t.Skip("the worker process is not available")
+	}
+
+	out, err, _ := s.Eval("t")
+	noError(t, err)
+	if out != first {
+		t.Errorf("the earlier cell should not have run again: got %q, then %q", first, out)
+	}
+
+	_, err, _ = s.Eval("t, n := 1, int64(2)")
+	noError(t, err)
+	out, err, _ = s.Eval("t+n")
+	noError(t, err)
+	if out != "3\n" {
+		t.Errorf("got %q", out)
+	}
+}
+
+func TestRun_workerRestart(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+	s.Evaluator, err = s.NewEvaluator("worker")
+	noError(t, err)
+
+	cells := []struct{ id, code string }{
+		{"a", "xs := []int{1}"},
+		{"b", "xs = append(xs, 2)"},
+		{"a", "xs := []int{1, 2}"},
+		{"c", "xs = append(xs, 3)"},
+	}
+	for _, cell := range cells {
+		s.CellID = cell.id
+		_, err, _ = s.Eval(cell.code)
+		noError(t, err)
+	}
+	w, ok := s.Evaluator.(*worker)
+	if !ok {
+		t.Skip("the worker process is not available")
+	}
+	noError(t, s.DropCell("c"))
+
+	noError(t, killProcessGroup(w.cmd))
+	<-w.exited
+
+	s.CellID = "d"
+	out, err, _ := s.Eval("xs")
+	noError(t, err)
+	if out != "[]int{1, 2, 2}\n" {
+		t.Errorf("the cells replaced and dropped should not have run again: got %q", out)
+	}
+}
+
+func TestRenamePluginFrames(t *testing.T) {
+	frames := []Frame{
+		{Func: "plugin/unnamed-4a5b6c.GoreCell.func1"},
+		{Func: "plugin/unnamed-4a5b6c.GoreCell"},
+		{Func: "plugin/unnamed-4a5b6c.square"},
+		{Func: "github.com/fabian-z/gopherlab/worker.run"},
+	}
+	renamePluginFrames(frames)
+
+	for i, want := range []string{"main.main.func1", "main.main", "main.square", "github.com/fabian-z/gopherlab/worker.run"} {
+		if frames[i].Func != want {
+			t.Errorf("got %q, want %q", frames[i].Func, want)
+		}
+	}
+}
+
+func TestRun_workerInterrupt(t *testing.T) {
+	s, err := NewSession()
+	noError(t, err)
+	defer s.Close()
+	s.Evaluator, err = s.NewEvaluator("worker")
+	noError(t, err)
+
+	_, err, _ = s.Eval("x := 1")
+	noError(t, err)
+	w, ok := s.Evaluator.(*worker)
+	if !ok {
+		t.Skip("the worker process is not available")
+	}
+	cmd := w.cmd
+
+	done := make(chan error)
+	go func() {
+		_, err, _ := s.Eval(`for {}`)
+		done <- err
+	}()
+
+	for !s.Interrupt() {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case err = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Eval should return after Interrupt")
+	}
+	if err != ErrInterrupt {
+		t.Fatalf("Eval should return ErrInterrupt: got %v", err)
+	}
+
+	out, err, stderr := s.Eval("x")
+	noError(t, err)
+	if out != "1\n" || w.cmd != cmd || stderr.Len() > 0 {
+		t.Errorf("the worker process should keep running: got %q, %q", out, stderr.String())
+	}
+}
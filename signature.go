@@ -0,0 +1,112 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sync"
+)
+
+// replayCacheSize is the number of signatures remembered to detect replayed messages.
+const replayCacheSize = 1 << 16
+
+// signatureSchemes maps the signature schemes of connection files to their hash functions.
+var signatureSchemes = map[string]func() hash.Hash{
+	"hmac-md5":        md5.New,
+	"hmac-sha1":       sha1.New,
+	"hmac-sha224":     sha256.New224,
+	"hmac-sha256":     sha256.New,
+	"hmac-sha384":     sha512.New384,
+	"hmac-sha512":     sha512.New,
+	"hmac-sha512-224": sha512.New512_224,
+	"hmac-sha512-256": sha512.New512_256,
+}
+
+// ReplayedMessageError is returned when a received message carries a signature
+// which has been seen before.
+type ReplayedMessageError struct{}
+
+func (e *ReplayedMessageError) Error() string {
+	return "A message was replayed"
+}
+
+// Signer signs and verifies messages with the key and signature scheme of the
+// connection file. It is safe for concurrent use.
+type Signer struct {
+	key     []byte
+	newHash func() hash.Hash
+
+	mu   sync.Mutex // guards seen and ring
+	seen map[string]bool
+	ring []string // the signatures in seen, oldest first
+}
+
+// NewSigner returns a Signer for the key and signature scheme, e.g. "hmac-sha256".
+// The scheme defaults to hmac-sha256. If key is empty, messages are not signed.
+func NewSigner(key []byte, scheme string) (*Signer, error) {
+	if scheme == "" {
+		scheme = "hmac-sha256"
+	}
+
+	newHash, ok := signatureSchemes[scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signature scheme %q", scheme)
+	}
+
+	return &Signer{
+		key:     key,
+		newHash: newHash,
+		seen:    make(map[string]bool),
+	}, nil
+}
+
+// Sign returns the hex encoded signature of the message parts, or nil if messages
+// are not signed.
+func (s *Signer) Sign(msgparts [][]byte) []byte {
+	if len(s.key) == 0 {
+		return nil
+	}
+
+	mac := hmac.New(s.newHash, s.key)
+	for _, msgpart := range msgparts {
+		mac.Write(msgpart)
+	}
+
+	signature := make([]byte, hex.EncodedLen(mac.Size()))
+	hex.Encode(signature, mac.Sum(nil))
+	return signature
+}
+
+// Verify checks the hex encoded signature of the message parts of a received message.
+// Signatures are accepted once only, so that replayed messages are rejected.
+func (s *Signer) Verify(signature []byte, msgparts [][]byte) error {
+	if len(s.key) == 0 {
+		return nil
+	}
+
+	if !hmac.Equal(s.Sign(msgparts), signature) {
+		return &InvalidSignatureError{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sig := string(signature)
+	if s.seen[sig] {
+		return &ReplayedMessageError{}
+	}
+
+	s.seen[sig] = true
+	s.ring = append(s.ring, sig)
+	if len(s.ring) > replayCacheSize {
+		delete(s.seen, s.ring[0])
+		s.ring = s.ring[1:]
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/fabian-z/gopherlab/protocol"
+	zmq "github.com/pebbe/zmq4"
+)
+
+// errInputInterrupted is returned by RequestInput if the cell is interrupted
+// while waiting for input.
+var errInputInterrupted = errors.New("interrupted while waiting for input")
+
+// inputRequest is an input request queued for HandleStdinMsgs.
+type inputRequest struct {
+	receipt  MsgReceipt
+	prompt   string
+	password bool
+	reply    chan inputResult
+}
+
+type inputResult struct {
+	value string
+	err   error
+}
+
+var (
+	inputRequests = make(chan inputRequest)
+	// stdinDone is closed when HandleStdinMsgs has returned.
+	stdinDone = make(chan struct{})
+)
+
+// RequestInput asks the frontend which sent receipt for a line of input. The
+// input_request is sent on the stdin socket by HandleStdinMsgs, and RequestInput
+// waits for the corresponding input_reply.
+func RequestInput(receipt MsgReceipt, prompt string, password bool) (string, error) {
+	reply := make(chan inputResult, 1)
+	select {
+	case inputRequests <- inputRequest{receipt, prompt, password, reply}:
+	case <-stdinDone:
+		return "", errInputInterrupted
+	}
+	result := <-reply
+	return result.value, result.err
+}
+
+// HandleStdinMsgs owns the stdin ROUTER socket. It sends the input requests made
+// with RequestInput and answers them with the input_reply messages received;
+// other messages are discarded.
+func HandleStdinMsgs(sockets SocketGroup) {
+	defer close(stdinDone)
+
+	pi := zmq.NewPoller()
+	pi.Add(sockets.Stdin_socket, zmq.POLLIN)
+
+	var pending *inputRequest
+	for {
+		if pending == nil {
+			select {
+			case req := <-inputRequests:
+				request := NewMsg("input_request", req.receipt.Msg)
+				request.Content = protocol.InputRequest{Prompt: req.prompt, Password: req.password}
+				req.receipt.SendResponse(sockets.Stdin_socket, request)
+				pending = &req
+			default:
+			}
+		}
+
+		polled, err := pi.Poll(100 * time.Millisecond)
+		if zmq.AsErrno(err) == zmq.ETERM {
+			sockets.Stdin_socket.Close()
+			if pending != nil {
+				pending.reply <- inputResult{err: errInputInterrupted}
+			}
+			return
+		}
+		if err != nil {
+			logger.Println(err)
+			continue
+		}
+
+		if len(polled) == 0 {
+			if pending != nil && atomic.LoadInt32(&interrupted) != 0 {
+				pending.reply <- inputResult{err: errInputInterrupted}
+				pending = nil
+			}
+			continue
+		}
+
+		msgparts, err := sockets.Stdin_socket.RecvMessageBytes(0)
+		if err != nil {
+			logger.Println(err)
+			continue
+		}
+
+		msg, _, err := WireMsgToComposedMsg(msgparts, sockets.Signer)
+		if err != nil {
+			logger.Println("Skipping stdin message:", err)
+			continue
+		}
+		if msg.Header.MsgType != "input_reply" || pending == nil {
+			logger.Println("Unexpected stdin message:", msg.Header.MsgType)
+			continue
+		}
+
+		var reply protocol.InputReply
+		err = msg.DecodeContent(&reply)
+		pending.reply <- inputResult{reply.Value, err}
+		pending = nil
+	}
+}
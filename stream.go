@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"github.com/fabian-z/gopherlab/protocol"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// streamChunkSize is the amount of output which is published at once.
+	streamChunkSize = 4096
+	// streamFlushInterval is how long output may be held back before it is published.
+	streamFlushInterval = 50 * time.Millisecond
+)
+
+// StreamWriter publishes what is written to it as stream messages on the IOPub socket.
+// Writes are collected into chunks, which are sent once they are large enough or
+// after streamFlushInterval.
+type StreamWriter struct {
+	name    string
+	receipt MsgReceipt
+
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	timer *time.Timer
+}
+
+// NewStreamWriter returns a StreamWriter for the stream name ("stdout" or "stderr")
+// in response to receipt.
+func NewStreamWriter(receipt MsgReceipt, name string) *StreamWriter {
+	return &StreamWriter{name: name, receipt: receipt}
+}
+
+// Write queues p for publishing.
+func (w *StreamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	if w.buf.Len() >= streamChunkSize {
+		w.flush(false)
+	} else if w.timer == nil {
+		w.timer = time.AfterFunc(streamFlushInterval, func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			w.timer = nil
+			w.flush(false)
+		})
+	}
+
+	return len(p), nil
+}
+
+// Flush publishes all queued output.
+func (w *StreamWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.flush(true)
+}
+
+// flush publishes the queued output. Unless all is set, an incomplete UTF-8
+// sequence at its end is held back for the next chunk.
+func (w *StreamWriter) flush(all bool) {
+	n := w.buf.Len()
+	if !all {
+		n = completeUTF8(w.buf.Bytes())
+	}
+	if n == 0 {
+		return
+	}
+
+	msg := NewMsg("stream", w.receipt.Msg)
+	msg.Content = protocol.Stream{Name: w.name, Text: string(w.buf.Next(n))}
+	w.receipt.Publish(msg)
+}
+
+// completeUTF8 returns the length of the longest prefix of p not ending in an
+// incomplete UTF-8 sequence.
+func completeUTF8(p []byte) int {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(p)
+}
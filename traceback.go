@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"github.com/fabian-z/gopherlab/protocol"
+	repl "github.com/fabian-z/gopherlab/replpkg"
+)
+
+// ANSI escape sequences used to highlight tracebacks.
+const (
+	ansiRed     = "\x1b[0;31m"
+	ansiBoldRed = "\x1b[1;31m"
+	ansiReset   = "\x1b[0m"
+)
+
+// EvalError returns the error published for err, returned by evaluating a cell
+// whose program wrote stderr. Unless silent, stderr has been streamed already.
+func EvalError(err error, stderr string, silent bool) protocol.Error {
+	if compileErr, ok := err.(*repl.CompileError); ok {
+		return protocol.Error{EName: "CompileError", EValue: compileErr.Error(), Traceback: compileErrorTraceback(compileErr)}
+	}
+
+	if panicErr, ok := err.(*repl.PanicError); ok {
+		ename := "panic"
+		switch {
+		case panicErr.Deadlock():
+			ename = "Deadlock"
+		case panicErr.Fatal:
+			ename = "fatal error"
+		case panicErr.Type != "":
+			ename = panicErr.Type
+		}
+		return protocol.Error{EName: ename, EValue: panicErr.Value, Traceback: panicTraceback(ename, panicErr)}
+	}
+
+	if err == repl.ErrInterrupt {
+		evalue := "execution was interrupted"
+		return protocol.Error{EName: "KeyboardInterrupt", EValue: evalue, Traceback: []string{"KeyboardInterrupt: " + evalue}}
+	}
+
+	// the details have been streamed to stderr already, unless silent
+	traceback := []string{err.Error()}
+	if silent {
+		traceback = []string{stderr}
+	}
+	return protocol.Error{EName: "Error", EValue: err.Error(), Traceback: traceback}
+}
+
+// compileErrorTraceback shows the errors of err with the offending lines of the
+// cells, marking the columns with a caret.
+func compileErrorTraceback(err *repl.CompileError) []string {
+	var traceback []string
+	for _, cellErr := range err.Errors {
+		if cellErr.Line == 0 {
+			traceback = append(traceback, ansiRed+cellErr.Msg+ansiReset)
+			continue
+		}
+
+		location := fmt.Sprintf("Cell [%d], line %d:%d", cellErr.Cell, cellErr.Line, cellErr.Col)
+		if cellErr.Cell == 0 {
+			// in an expression evaluated apart from the cells
+			location = fmt.Sprintf("line %d:%d", cellErr.Line, cellErr.Col)
+		}
+		traceback = append(traceback,
+			ansiRed+location+ansiReset+": "+cellErr.Msg,
+			"    "+cellErr.Source,
+			"    "+caretIndent(cellErr.Source, cellErr.Col)+ansiBoldRed+"^"+ansiReset,
+		)
+	}
+	return traceback
+}
+
+// panicTraceback shows the panic value of err, and the calls on the stack from
+// the cells with their lines. Calls in between, in the runtime and packages, are
+// summarized.
+func panicTraceback(ename string, err *repl.PanicError) []string {
+	traceback := []string{ansiBoldRed + ename + ansiReset + ": " + err.Value}
+	if err.Deadlock() {
+		traceback = append(traceback, "all goroutines are blocked, waiting forever on channels, locks or other goroutines")
+	}
+
+	var skipped int
+	skip := func() {
+		if skipped == 1 {
+			traceback = append(traceback, "    [1 call in the runtime or packages]")
+		} else if skipped > 1 {
+			traceback = append(traceback, fmt.Sprintf("    [%d calls in the runtime or packages]", skipped))
+		}
+		skipped = 0
+	}
+
+	for _, frame := range err.Frames {
+		if frame.Pos.Line == 0 {
+			skipped++
+			continue
+		}
+		skip()
+
+		location := fmt.Sprintf("Cell [%d], line %d", frame.Pos.Cell, frame.Pos.Line)
+		traceback = append(traceback,
+			ansiRed+location+ansiReset+", in "+frame.Func,
+			"    "+frame.Pos.Source,
+		)
+	}
+	skip()
+
+	return traceback
+}
+
+// caretIndent returns the whitespace placing a caret below the byte column col
+// of line, keeping its tabs.
+func caretIndent(line string, col int) string {
+	if col-1 > len(line) {
+		col = len(line) + 1
+	}
+
+	indent := []rune(line[:col-1])
+	for i, r := range indent {
+		if r != '\t' {
+			indent[i] = ' '
+		}
+	}
+	return string(indent)
+}
@@ -0,0 +1,213 @@
+// Package worker implements the persistent process in which the gopherlab kernel
+// runs the cells of a session when they are not to be run again with every later
+// cell.
+//
+// Every cell is built as a plugin, which the process loads and runs once. The
+// variables a cell defines stay in memory: the cell registers pointers to them
+// with SetVar, and the later cells using them look the pointers up with Var.
+package worker
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"plugin"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/fabian-z/gopherlab/ipc"
+)
+
+// CellFunc is the function run by the plugin of a cell.
+const CellFunc = "GoreCell"
+
+var (
+	varsMu sync.Mutex
+	vars   = make(map[string]interface{})
+)
+
+// Var returns the pointer to the variable name, registered by an earlier cell.
+func Var(name string) interface{} {
+	varsMu.Lock()
+	defer varsMu.Unlock()
+
+	return vars[name]
+}
+
+// SetVar registers ptr as the pointer to the variable name, for the later cells.
+func SetVar(name string, ptr interface{}) {
+	varsMu.Lock()
+	defer varsMu.Unlock()
+
+	vars[name] = ptr
+}
+
+// Serve runs the plugins the kernel requests, one at a time, until the kernel
+// closes the connection. The output of the cells is sent to the kernel as well.
+//
+// The kernel interrupts a cell with SIGINT: the result of the cell is sent right
+// away, leaving its goroutine running behind, as Go cannot stop it.
+func Serve() {
+	// the process keeps running on interrupts
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+
+	conn, err := ipc.Default()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "worker:", err)
+		os.Exit(1)
+	}
+
+	if err := conn.Send("worker_ready", nil); err != nil {
+		fmt.Fprintln(os.Stderr, "worker:", err)
+		os.Exit(1)
+	}
+
+	for {
+		msg, err := conn.Recv()
+		if err != nil {
+			return
+		}
+		if msg.Type != "run" {
+			continue
+		}
+
+		var req ipc.RunRequest
+		var result ipc.RunResult
+		if err := msg.Decode(&req); err != nil {
+			result.Error = err.Error()
+		} else {
+			done := make(chan ipc.RunResult, 1)
+			go func() {
+				done <- run(conn, req.Plugin)
+			}()
+			select {
+			case result = <-done:
+				// the kernel reports an interrupt arriving as the cell returns
+				// itself, and it must not stop the next cell
+				select {
+				case <-interrupts:
+				default:
+				}
+			case <-interrupts:
+				result.Interrupted = true
+			}
+		}
+
+		// sent after the values and the output of the cell, on the same connection
+		if err := conn.Send("run_result", result); err != nil {
+			return
+		}
+	}
+}
+
+// run loads the plugin at path and runs its cell, recovering a panic.
+func run(conn *ipc.Conn, path string) (result ipc.RunResult) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	sym, err := p.Lookup(CellFunc)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	cell, ok := sym.(func())
+	if !ok {
+		result.Error = fmt.Sprintf("%s: %s is a %T", path, CellFunc, sym)
+		return
+	}
+
+	restoreStdout := forward(conn, "stdout", &os.Stdout, stdout)
+	defer restoreStdout()
+	restoreStderr := forward(conn, "stderr", &os.Stderr, stderr)
+	defer restoreStderr()
+
+	defer func() {
+		if r := recover(); r != nil {
+			msg := ipc.NewPanicMessage(r)
+			result.Panic = &msg
+		}
+	}()
+
+	cell()
+	return
+}
+
+// stdout and stderr are the standard output and error of the process, which the
+// output of the cells is forwarded from.
+var stdout, stderr = os.Stdout, os.Stderr
+
+// forward points *f, which is orig or the pipe of an interrupted cell still
+// running, at a pipe whose contents are sent to the kernel as the output stream
+// name, and the standard logger as well if it writes to *f. The returned function
+// points them back at orig unless a later cell has pointed them elsewhere, and
+// returns once all of the output has been sent.
+func forward(conn *ipc.Conn, name string, f **os.File, orig *os.File) (restore func()) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return func() {}
+	}
+
+	prev := *f
+	*f = w
+	logged := log.Writer() == prev || log.Writer() == orig
+	if logged {
+		log.SetOutput(w)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var pending []byte
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := r.Read(buf)
+			pending = append(pending, buf[:n]...)
+
+			// keep a rune split between reads for the next message
+			k := len(pending)
+			if err == nil {
+				k = completeRunes(pending)
+			}
+			if k > 0 {
+				conn.Send("stream", ipc.StreamMessage{Name: name, Text: string(pending[:k])})
+				pending = append(pending[:0], pending[k:]...)
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return func() {
+		if *f == w {
+			*f = orig
+		}
+		if logged && log.Writer() == w {
+			log.SetOutput(orig)
+		}
+		w.Close()
+		<-done
+		r.Close()
+	}
+}
+
+// completeRunes returns the length of the longest prefix of p not ending in an
+// incomplete UTF-8 sequence.
+func completeRunes(p []byte) int {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(p)
+}